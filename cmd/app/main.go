@@ -2,17 +2,27 @@ package main
 
 import (
 	"context"
+	_ "time/tzdata"
 
 	app "Tasks/internal/app"
+	"Tasks/internal/board"
 	"Tasks/internal/config"
+	"Tasks/internal/events"
+	"Tasks/internal/graph"
+	grpcserver "Tasks/internal/grpc-server"
+	"Tasks/internal/health"
 	"Tasks/internal/http-server/handlers"
+	"Tasks/internal/interfaces"
 	k "Tasks/internal/kafka"
+	"Tasks/internal/lib/jwt"
 	"Tasks/internal/lib/logger"
 	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/metrics"
 	repo "Tasks/internal/repository/postgres"
 	repoCache "Tasks/internal/repository/redis"
 	"Tasks/internal/service"
 	"Tasks/internal/storage"
+	"Tasks/internal/webhook"
 )
 
 func main() {
@@ -34,6 +44,9 @@ func main() {
 	}()
 
 	repoStorage := repo.NewStorage(storages.Postgres, log)
+	idempotency := repoCache.NewIdempotency(storages.Redis, log)
+	eventStream := repoCache.NewEvents(storages.Redis, log, cfg.Events.Replay)
+	boardRepo := repoCache.NewBoard(storages.Redis, log)
 	repoCache := repoCache.NewCache(storages.Redis, log)
 	broker, err := k.New(cfg.KafkaAddresses)
 	if err != nil {
@@ -42,16 +55,88 @@ func main() {
 	}
 	log.Info("successful connection to the kafka")
 	//defer broker.Close()
-	serv := service.NewService(log, repoStorage, repoCache, broker)
+
+	var (
+		cache    interfaces.CacheRepository = repoCache
+		producer interfaces.Broker          = broker
+		m        *metrics.Metrics
+	)
+	if cfg.Metrics.Enabled {
+		m = metrics.New()
+		cache, producer = m.Cache(repoCache), m.Broker(broker)
+	}
+	serv := service.NewService(log, repoStorage, cache, producer, eventStream)
+	if m != nil {
+		m.Register(
+			metrics.NewPoolCollector(storages.Postgres.Pool),
+			metrics.NewTaskCollector(log, serv.TaskCounts, cfg.Metrics.TasksTimeout),
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	hub := events.NewHub(log, eventStream, cfg.Events.Buffer, cfg.Events.Replay)
+	go hub.Run(ctx)
+	boards := board.NewHub(log, boardRepo, hub, board.Config{
+		Authenticate: func(token string) (jwt.Claims, error) {
+			return jwt.ParseClaims(token, cfg.HTTP.JWTSecret)
+		},
+		Access: serv.BoardScope,
+		Buffer: cfg.Events.Buffer,
+	})
+	go boards.Run(ctx)
+	dispatcher := webhook.NewDispatcher(log, repoStorage, hub, webhook.Config{
+		Interval:     cfg.Webhooks.Interval,
+		Batch:        cfg.Webhooks.Batch,
+		Timeout:      cfg.Webhooks.Timeout,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		BackoffBase:  cfg.Webhooks.BackoffBase,
+		BackoffMax:   cfg.Webhooks.BackoffMax,
+		DisableAfter: cfg.Webhooks.DisableAfter,
+		AllowPrivate: cfg.Webhooks.AllowPrivate,
+	})
+	go dispatcher.Run(ctx)
+
+	checker := health.New(log, cfg.Health.Timeout)
+	checker.Add("postgres", storages.Postgres.Pool.Ping)
+	checker.Add("redis", func(ctx context.Context) error {
+		return storages.Redis.Client.Ping(ctx).Err()
+	})
+	checker.Add("kafka", broker.Ping)
+
 	deps := &handlers.Dependencies{
 		Service: serv,
 		Log:     log,
+		Events:  hub,
+		Board:   boards,
+		Health:  checker,
+	}
+	if m != nil {
+		deps.Metrics = m.Handler()
+	}
+	if cfg.GraphQL.Enabled {
+		deps.GraphQL = graph.New(log, serv, graph.Config{
+			MaxDepth:      cfg.GraphQL.MaxDepth,
+			MaxComplexity: cfg.GraphQL.MaxComplexity,
+		})
 	}
 
 	h := handlers.NewHandler(deps)
-	router := app.SetupRouter(h, log)
-	// TODO: разобраться с авторизацией
+	router := app.SetupRouter(h, log, app.RouterConfig{
+		JWTSecret:      cfg.HTTP.JWTSecret,
+		Idempotency:    idempotency,
+		IdempotencyTTL: cfg.HTTP.IdempotencyTTL,
+		Metrics:        m,
+	})
 	server := app.New(cfg, log, router)
+	server.WithHealth(checker)
+	// открытые потоки событий завершаются в начале остановки, иначе сервер ждал бы их до таймаута.
+	// Соединения досок сервер не отслеживает, их нужно закрыть, чтобы клиенты узнали об остановке.
+	server.OnShutdown(hub.Close)
+	server.OnShutdown(boards.Close)
+	if cfg.GRPC.Address != "" {
+		server.WithGRPC(grpcserver.New(log, cfg.GRPC.Address, cfg.HTTP.JWTSecret, serv))
+	}
 	if err := server.Run(); err != nil {
 		log.Error("server stopped with error", sl.Err(err))
 	}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
+
+	"Tasks/internal/config"
+	k "Tasks/internal/kafka"
+	"Tasks/internal/lib/logger"
+	"Tasks/internal/lib/logger/sl"
+	repo "Tasks/internal/repository/postgres"
+	repoCache "Tasks/internal/repository/redis"
+	"Tasks/internal/scheduler"
+	"Tasks/internal/service"
+	"Tasks/internal/storage"
+)
+
+func main() {
+	cfg := config.MustLoad()
+
+	log := logger.SetupLogger(cfg.Env)
+	log.Info("start scheduler")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Redis нужен эскалации: назначение руководителя сбрасывает кэш задачи и публикуется в поток событий
+	storages, err := storage.NewStorage(ctx, cfg)
+	if err != nil {
+		log.Error("failed to connect to database", sl.Err(err))
+		panic(err)
+	}
+	defer func() {
+		if err := storages.Close(context.TODO()); err != nil {
+			log.Error("Failed to close storages", sl.Err(err))
+		}
+	}()
+	log.Info("successful connection to the database")
+
+	broker, err := k.New(cfg.KafkaAddresses)
+	if err != nil {
+		log.Error("failed to connect to kafka", sl.Err(err))
+		panic(err)
+	}
+	defer broker.Close()
+	log.Info("successful connection to the kafka")
+
+	repoStorage := repo.NewStorage(storages.Postgres, log)
+	serv := service.NewService(log, repoStorage, repoCache.NewCache(storages.Redis, log), broker,
+		repoCache.NewEvents(storages.Redis, log, cfg.Events.Replay))
+
+	s := scheduler.New(log, cfg.Scheduler.Interval,
+		scheduler.NewReminder(log, repoStorage, broker, cfg.Scheduler.Thresholds),
+		scheduler.NewEscalation(log, repoStorage, serv, broker),
+		scheduler.NewViewSubscriptions(log, repoStorage, broker),
+		scheduler.NewSyncTombstones(log, repoStorage, cfg.Scheduler.SyncTombstoneTTL),
+	)
+	s.Run(ctx)
+}
@@ -2,31 +2,174 @@ package application
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	"Tasks/internal/http-server/handlers"
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/http-server/middleware/deprecation"
+	"Tasks/internal/http-server/middleware/idempotency"
+	"Tasks/internal/http-server/middleware/language"
 	mwLogger "Tasks/internal/http-server/middleware/logger"
+	mwMetrics "Tasks/internal/http-server/middleware/metrics"
+	"Tasks/internal/interfaces"
+	"Tasks/internal/metrics"
 )
 
-func SetupRouter(h *handlers.Handler, log *slog.Logger) *chi.Mux {
+// RouterConfig зависимости middleware. Без Idempotency заголовок Idempotency-Key игнорируется.
+type RouterConfig struct {
+	JWTSecret      string
+	Idempotency    interfaces.IdempotencyRepository
+	IdempotencyTTL time.Duration
+	// Metrics метрики HTTP-запросов, nil - без метрик
+	Metrics *metrics.Metrics
+}
+
+func SetupRouter(h *handlers.Handler, log *slog.Logger, cfg RouterConfig) *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
+	if cfg.Metrics != nil {
+		router.Use(mwMetrics.New(cfg.Metrics))
+	}
 	router.Use(mwLogger.New(log))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
+	router.Use(language.New())
+	router.Use(auth.New(log, cfg.JWTSecret, h.UserLanguage))
+	if cfg.Idempotency != nil {
+		// после auth: ключи разделены по пользователям
+		router.Use(idempotency.New(log, cfg.Idempotency, cfg.IdempotencyTTL))
+	}
+
+	// URLFormat отрезает расширение, поэтому маршрут /openapi отвечает на /openapi.json
+	router.Get("/openapi", h.OpenAPISpec)
+	router.Get("/docs", h.Docs)
+	router.Post("/graphql", h.GraphQL)
+	router.Get("/healthz", h.Healthz)
+	router.Get("/readyz", h.Readyz)
+	router.Get("/metrics", h.Metrics)
+
+	router.Route("/v1", func(r chi.Router) {
+		r.Post("/tasks:batch", h.BatchTasksV1)
+		r.Route("/tasks", func(r chi.Router) {
+			r.Post("/", h.CreateNewTask)
+			r.Get("/{id}", h.GetTaskV1)
+			r.Delete("/{id}", h.DeleteTaskV1)
+			r.Put("/{id}/status", h.UpdateStatusV1)
+			r.Get("/{id}/history", h.TaskHistoryV1)
+			r.Get("/{id}/assignees", h.AllUsersV1)
+			r.Put("/{id}/assignees/{userID}", h.AddUserV1)
+			r.Delete("/{id}/assignees/{userID}", h.RemoveUserV1)
+		})
+		r.Route("/users/{id}", func(r chi.Router) {
+			r.Get("/tasks", h.UserTasksV1)
+			r.Get("/tasks/milestone-overrun", h.MilestoneOverrunV1)
+			r.Put("/timezone", h.SetUserTimezoneV1)
+			r.Put("/language", h.SetUserLanguageV1)
+			r.Put("/calendar", h.SetUserCalendarV1)
+		})
+		r.Route("/projects", func(r chi.Router) {
+			r.Post("/", h.CreateProject)
+			r.Get("/{id}/tasks", h.ProjectTasksV1)
+			r.Get("/{id}/escalation-rules", h.EscalationRulesV1)
+			r.Put("/{id}/calendar", h.SetProjectCalendarV1)
+			r.Get("/{id}/board", h.BoardV1)
+			r.Post("/{id}/webhooks", h.CreateWebhookV1)
+			r.Get("/{id}/webhooks", h.WebhooksV1)
+		})
+		r.Route("/escalation-rules", func(r chi.Router) {
+			r.Post("/", h.CreateEscalationRule)
+			r.Delete("/{id}", h.DeleteEscalationRuleV1)
+		})
+		r.Route("/teams", func(r chi.Router) {
+			r.Post("/", h.CreateTeam)
+			r.Get("/{id}/tasks", h.TeamTasksV1)
+			r.Put("/{id}/members/{userID}", h.AddTeamMemberV1)
+		})
+		r.Route("/sprints", func(r chi.Router) {
+			r.Post("/", h.CreateSprint)
+			r.Get("/{id}/tasks", h.SprintTasksV1)
+			r.Put("/{id}/tasks/{taskID}", h.AddTaskToSprintV1)
+			r.Post("/{id}/start", h.StartSprintV1)
+			r.Post("/{id}/complete", h.CompleteSprintV1)
+		})
+		r.Route("/milestones", func(r chi.Router) {
+			r.Post("/", h.CreateMilestone)
+			r.Put("/{id}/tasks/{taskID}", h.SetTaskMilestoneV1)
+		})
+		r.Route("/calendars", func(r chi.Router) {
+			r.Post("/", h.CreateCalendar)
+			r.Get("/{id}", h.GetCalendarV1)
+			r.Post("/{id}/holidays", h.ImportHolidaysV1)
+		})
+		r.Route("/views", func(r chi.Router) {
+			r.Post("/", h.CreateViewV1)
+			r.Get("/", h.ViewsV1)
+			r.Get("/{id}", h.GetViewV1)
+			r.Put("/{id}", h.UpdateViewV1)
+			r.Delete("/{id}", h.DeleteViewV1)
+			r.Get("/{id}/tasks", h.ViewTasksV1)
+			r.Put("/{id}/subscription", h.SubscribeViewV1)
+			r.Delete("/{id}/subscription", h.UnsubscribeViewV1)
+		})
+		r.Route("/webhooks", func(r chi.Router) {
+			r.Get("/{id}", h.GetWebhookV1)
+			r.Put("/{id}", h.UpdateWebhookV1)
+			r.Delete("/{id}", h.DeleteWebhookV1)
+			r.Get("/{id}/deliveries", h.WebhookDeliveriesV1)
+			r.Post("/{id}/deliveries/{deliveryID}/replay", h.ReplayWebhookDeliveryV1)
+		})
+		r.Get("/sync", h.SyncV1)
+		r.Post("/sync", h.SyncMutationsV1)
+		r.Get("/search", h.SearchV1)
+		r.Get("/stream", h.StreamV1)
+	})
+
+	// Устаревшие маршруты с идентификаторами в теле запроса, оставлены на время миграции на /v1
+	router.Group(func(r chi.Router) {
+		r.Use(deprecation.New(log, "/v1"))
+
+		r.Post("/task", h.CreateNewTask)
+		r.Post("/adduser", h.AddUserFromTask)
+		r.Get("/users", h.AllUsers)
+		r.Get("/tasks", h.AllTasks)
+		r.Get("/shortdeadline", h.ShortDeadline)
+		r.Get("/taskbyid", h.GetTaskByID)
+		r.Put("/status", h.UpdateStatus)
+		r.Delete("/task", h.DeleteTask)
+		r.Delete("/user", h.RemoveUser)
+
+		r.Post("/sprint", h.CreateSprint)
+		r.Post("/sprint/task", h.AddTaskToSprint)
+		r.Get("/sprint/tasks", h.SprintTasks)
+		r.Put("/sprint/start", h.StartSprint)
+		r.Put("/sprint/complete", h.CompleteSprint)
+		r.Post("/milestone", h.CreateMilestone)
+		r.Put("/milestone/task", h.SetTaskMilestone)
+		r.Get("/milestoneoverrun", h.MilestoneOverrun)
+
+		r.Post("/project", h.CreateProject)
+		r.Post("/project/rule", h.CreateEscalationRule)
+		r.Get("/project/rules", h.EscalationRules)
+		r.Delete("/project/rule", h.DeleteEscalationRule)
+		r.Get("/task/history", h.TaskHistory)
+
+		r.Get("/tasks/deadline", h.DeadlineTasks)
+		r.Get("/project/tasks", h.ProjectTasks)
+		r.Post("/team", h.CreateTeam)
+		r.Post("/team/member", h.AddTeamMember)
+		r.Get("/team/tasks", h.TeamTasks)
+		r.Put("/user/timezone", h.SetUserTimezone)
 
-	router.Post("/task", h.CreateNewTask)
-	router.Post("/adduser", h.AddUserFromTask)
-	router.Get("/users", h.AllUsers)
-	router.Get("/tasks", h.AllTasks)
-	router.Get("/shortdeadline", h.ShortDeadline)
-	router.Get("/taskbyid", h.GetTaskByID)
-	router.Put("/status", h.UpdateStatus)
-	router.Delete("/task", h.DeleteTask)
-	router.Delete("/user", h.RemoveUser)
+		r.Post("/calendar", h.CreateCalendar)
+		r.Post("/calendar/holidays", h.ImportHolidays)
+		r.Get("/calendar", h.GetCalendar)
+		r.Put("/project/calendar", h.SetProjectCalendar)
+		r.Put("/user/calendar", h.SetUserCalendar)
+	})
 
 	return router
 }
@@ -0,0 +1,130 @@
+package application
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/http-server/handlers"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/service"
+)
+
+// routePath приводит шаблон chi к пути из спецификации: "/v1/tasks/" -> "/v1/tasks".
+// Маршрут /openapi описан как /openapi.json, расширение отрезает URLFormat.
+func routePath(route string) string {
+	if route != "/" {
+		route = strings.TrimSuffix(route, "/")
+	}
+	if route == "/openapi" {
+		route += ".json"
+	}
+	return route
+}
+
+func TestRouter_OpenAPI(t *testing.T) {
+	router := SetupRouter(&handlers.Handler{}, slogdiscard.NewDiscardLogger(), RouterConfig{JWTSecret: "secret"})
+	doc := handlers.OpenAPI()
+
+	routes := make(map[string]bool)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		path := routePath(route)
+		routes[method+" "+path] = true
+		if !doc.Has(method, path) {
+			t.Errorf("route %s %s is not described in the OpenAPI document", method, path)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	for path, item := range doc.Paths {
+		for method := range *item {
+			key := strings.ToUpper(method) + " " + path
+			if !routes[key] {
+				t.Errorf("OpenAPI document describes %s, but the router has no such route", key)
+			}
+		}
+	}
+}
+
+func TestRouter_OpenAPISpec(t *testing.T) {
+	router := SetupRouter(&handlers.Handler{}, slogdiscard.NewDiscardLogger(), RouterConfig{JWTSecret: "secret"})
+
+	for _, path := range []string{"/openapi.json", "/docs"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		router.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code, path)
+	}
+}
+
+func TestRouter_Auth(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	h := handlers.NewHandler(&handlers.Dependencies{Service: &service.Service{}, Log: log})
+	router := SetupRouter(h, log, RouterConfig{JWTSecret: "secret"})
+
+	tests := []struct {
+		name          string
+		authorization string
+		status        int
+	}{
+		{name: "anonymous search", status: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer not.a.token", status: http.StatusUnauthorized},
+		{name: "not a bearer token", authorization: "Basic dXNlcjpwYXNz", status: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/v1/search?q=deploy", nil)
+			require.NoError(t, err)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			require.Equal(t, tt.status, rr.Code)
+			require.Contains(t, rr.Body.String(), `"code":"unauthorized"`)
+		})
+	}
+}
+
+// TestRouter_AuthRequired операции, помеченные в спецификации как требующие токен,
+// отвечают анонимному клиенту 401
+func TestRouter_AuthRequired(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	h := handlers.NewHandler(&handlers.Dependencies{Service: &service.Service{}, Log: log})
+	router := SetupRouter(h, log, RouterConfig{JWTSecret: "secret"})
+
+	for path, item := range handlers.OpenAPI().Paths {
+		for method, op := range *item {
+			if len(op.Security) == 0 {
+				continue
+			}
+			target := strings.NewReplacer("{id}", "1").Replace(path)
+			req, err := http.NewRequest(strings.ToUpper(method), target, strings.NewReader("{}"))
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+			require.Equal(t, http.StatusUnauthorized, rr.Code, "%s %s", method, path)
+		}
+	}
+}
+
+// TestRouter_Batch маршрут с двоеточием не перехватывается маршрутами /v1/tasks/{id}
+func TestRouter_Batch(t *testing.T) {
+	log := slogdiscard.NewDiscardLogger()
+	h := handlers.NewHandler(&handlers.Dependencies{Service: &service.Service{}, Log: log})
+	router := SetupRouter(h, log, RouterConfig{JWTSecret: "secret"})
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/tasks:batch", strings.NewReader(`{"task_ids": [], "status": "done"}`))
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	require.Contains(t, rr.Body.String(), `"field":"task_ids"`)
+}
@@ -7,14 +7,21 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"Tasks/internal/config"
+	grpcserver "Tasks/internal/grpc-server"
+	"Tasks/internal/health"
 )
 
 type Server struct {
 	cfg    *config.Config
 	log    *slog.Logger
 	server *http.Server
+	// grpc запускается и останавливается вместе с HTTP-сервером, nil - без gRPC
+	grpc *grpcserver.Server
+	// health при остановке переводится в неготовность до остановки серверов, nil - без проверок
+	health *health.Checker
 }
 
 func New(cfg *config.Config, log *slog.Logger, handler http.Handler) *Server {
@@ -31,6 +38,21 @@ func New(cfg *config.Config, log *slog.Logger, handler http.Handler) *Server {
 	}
 }
 
+// OnShutdown регистрирует функцию, которая вызывается в начале остановки сервера
+func (s *Server) OnShutdown(f func()) {
+	s.server.RegisterOnShutdown(f)
+}
+
+// WithGRPC добавляет gRPC-сервер, который работает на своём порту рядом с HTTP-сервером
+func (s *Server) WithGRPC(grpc *grpcserver.Server) {
+	s.grpc = grpc
+}
+
+// WithHealth подключает проверки готовности, которые отказывают с начала остановки
+func (s *Server) WithHealth(health *health.Checker) {
+	s.health = health
+}
+
 func (s *Server) Run() error {
 	s.log.Info("starting server", slog.String("address", s.cfg.HTTP.Address))
 
@@ -43,6 +65,13 @@ func (s *Server) Run() error {
 			s.log.Error("server error", slog.String("error", err.Error()))
 		}
 	}()
+	if s.grpc != nil {
+		go func() {
+			if err := s.grpc.Run(); err != nil {
+				s.log.Error("grpc server error", slog.String("error", err.Error()))
+			}
+		}()
+	}
 
 	s.log.Info("server started")
 
@@ -50,13 +79,31 @@ func (s *Server) Run() error {
 	<-doneCh
 	s.log.Info("stopping server")
 
+	// новые запросы перестают приходить, пока сервер ещё обслуживает уже направленные к нему
+	if s.health != nil {
+		s.health.Shutdown()
+		time.Sleep(s.cfg.Health.ShutdownDelay)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.HTTP.WithTimeout)
 	defer cancel()
 
+	// gRPC останавливается одновременно с HTTP и укладывается в тот же таймаут
+	grpcDone := make(chan error, 1)
+	if s.grpc != nil {
+		go func() { grpcDone <- s.grpc.Shutdown(ctx) }()
+	} else {
+		grpcDone <- nil
+	}
+
 	if err := s.server.Shutdown(ctx); err != nil {
 		s.log.Error("failed to shutdown server", slog.String("error", err.Error()))
 		return err
 	}
+	if err := <-grpcDone; err != nil {
+		s.log.Error("failed to shutdown grpc server", slog.String("error", err.Error()))
+		return err
+	}
 
 	s.log.Info("server stopped gracefully")
 	return nil
@@ -0,0 +1,210 @@
+package board
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/events"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+)
+
+// memoryBoard присутствие и сигналы досок в памяти одного процесса
+type memoryBoard struct {
+	mu       sync.Mutex
+	presence map[string]model.Presence
+	signals  chan model.BoardSignal
+}
+
+func newMemoryBoard() *memoryBoard {
+	return &memoryBoard{presence: make(map[string]model.Presence), signals: make(chan model.BoardSignal, 100)}
+}
+
+func (m *memoryBoard) SetPresence(_ context.Context, _ int, connID string, presence model.Presence, _ time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.presence[connID] = presence
+	return nil
+}
+
+func (m *memoryBoard) RemovePresence(_ context.Context, _ int, connID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.presence, connID)
+	return nil
+}
+
+func (m *memoryBoard) Presence(context.Context, int) ([]model.Presence, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var presence []model.Presence
+	for _, p := range m.presence {
+		presence = append(presence, p)
+	}
+	return presence, nil
+}
+
+func (m *memoryBoard) PublishBoardSignal(_ context.Context, signal model.BoardSignal) error {
+	m.signals <- signal
+	return nil
+}
+
+func (m *memoryBoard) BoardSignals(context.Context) (<-chan model.BoardSignal, error) {
+	return m.signals, nil
+}
+
+// токены вида "user-<id>": пользователь 1 участвует в проекте 3, остальные - нет
+var users = map[string]jwt.Claims{
+	"user-1": {UserID: 1, Login: "anna"},
+	"user-2": {UserID: 2, Login: "boris"},
+	"user-9": {UserID: 9, Login: "stranger"},
+}
+
+func authenticate(token string) (jwt.Claims, error) {
+	claims, ok := users[token]
+	if !ok {
+		return jwt.Claims{}, jwt.ErrInvalidToken
+	}
+	claims.ExpiresAt = time.Now().Add(time.Hour)
+	return claims, nil
+}
+
+func access(_ context.Context, projectID int, userID int) (model.EventScope, error) {
+	if projectID != 3 || userID == 9 {
+		return model.EventScope{}, model.Forbidden("user with ID %d is not a member of project %d", userID, projectID)
+	}
+	return model.EventScope{UserID: userID}, nil
+}
+
+func newTestHub(t *testing.T) (*Hub, *httptest.Server) {
+	log := slogdiscard.NewDiscardLogger()
+	hub := NewHub(log, newMemoryBoard(), events.NewHub(log, nil, 10, 10), Config{
+		Authenticate: authenticate,
+		Access:       access,
+		Buffer:       10,
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	go hub.runSignals(ctx)
+
+	upgrader := websocket.Upgrader{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		hub.Serve(r.Context(), ws, 3, "")
+	}))
+	t.Cleanup(func() {
+		cancel()
+		hub.Close()
+		server.Close()
+	})
+	return hub, server
+}
+
+func dial(t *testing.T, server *httptest.Server, token string) *websocket.Conn {
+	ws, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { ws.Close() })
+	require.NoError(t, ws.WriteJSON(model.BoardMessage{Type: model.BoardAuth, Token: token}))
+	return ws
+}
+
+// next следующее сообщение типа msgType, остальные пропускаются
+func next(t *testing.T, ws *websocket.Conn, msgType string) model.BoardMessage {
+	t.Helper()
+	require.NoError(t, ws.SetReadDeadline(time.Now().Add(2*time.Second)))
+	for {
+		var msg model.BoardMessage
+		require.NoError(t, ws.ReadJSON(&msg))
+		if msg.Type == msgType {
+			return msg
+		}
+	}
+}
+
+func TestHub_Auth(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		code  string
+	}{
+		{name: "invalid token", token: "forged", code: "unauthorized"},
+		{name: "not a project member", token: "user-9", code: "forbidden"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, server := newTestHub(t)
+			ws := dial(t, server, tt.token)
+
+			msg := next(t, ws, model.BoardError)
+			require.Equal(t, tt.code, msg.Code)
+			_, _, err := ws.ReadMessage()
+			var closeErr *websocket.CloseError
+			require.True(t, errors.As(err, &closeErr), "unexpected error: %v", err)
+			require.Equal(t, websocket.ClosePolicyViolation, closeErr.Code)
+		})
+	}
+}
+
+func TestHub_PresenceAndTyping(t *testing.T) {
+	_, server := newTestHub(t)
+
+	anna := dial(t, server, "user-1")
+	require.Equal(t, "anna", next(t, anna, model.BoardReady).Login)
+	boris := dial(t, server, "user-2")
+	next(t, boris, model.BoardReady)
+
+	require.NoError(t, anna.WriteJSON(model.BoardMessage{Type: model.BoardView, TaskID: 12}))
+	require.Eventually(t, func() bool {
+		msg := next(t, boris, model.BoardPresence)
+		return len(msg.Presence) == 2 && msg.Presence[0] == model.Presence{UserID: 1, Login: "anna", TaskID: 12}
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, anna.WriteJSON(model.BoardMessage{Type: model.BoardTyping, TaskID: 12}))
+	typing := next(t, boris, model.BoardTyping)
+	require.Equal(t, model.BoardMessage{Type: model.BoardTyping, UserID: 1, Login: "anna", TaskID: 12}, typing)
+
+	require.NoError(t, anna.WriteJSON(model.BoardMessage{Type: "dance"}))
+	require.Equal(t, "bad_request", next(t, anna, model.BoardError).Code)
+}
+
+func TestHub_DispatchEvent(t *testing.T) {
+	hub, server := newTestHub(t)
+	anna := dial(t, server, "user-1")
+	next(t, anna, model.BoardReady)
+
+	// событие чужого проекта и невидимое событие не доходят, видимое - доходит
+	require.Eventually(t, func() bool { return len(hub.conns(3)) == 1 }, 2*time.Second, 10*time.Millisecond)
+	hub.dispatchEvent(model.TaskEvent{ID: "1-0", Type: model.TaskEventUpdated, TaskID: 5, ProjectID: 4, Assignees: []int{1}})
+	hub.dispatchEvent(model.TaskEvent{ID: "2-0", Type: model.TaskEventUpdated, TaskID: 6, ProjectID: 3, Assignees: []int{2}})
+	hub.dispatchEvent(model.TaskEvent{ID: "3-0", Type: model.TaskEventAssigned, TaskID: 7, ProjectID: 3, Assignees: []int{1}})
+
+	msg := next(t, anna, model.BoardTaskEvent)
+	require.Equal(t, "3-0", msg.Event.ID)
+}
+
+func TestConn_SlowClient(t *testing.T) {
+	c := &Conn{hub: NewHub(slogdiscard.NewDiscardLogger(), newMemoryBoard(), nil, Config{}), send: make(chan []byte, 1), done: make(chan struct{})}
+	c.enqueue([]byte("first"))
+	c.enqueue([]byte("second"))
+
+	select {
+	case <-c.done:
+	default:
+		t.Fatal("slow client was not disconnected")
+	}
+	require.Equal(t, websocket.CloseTryAgainLater, c.closeCode)
+}
@@ -0,0 +1,349 @@
+package board
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const (
+	// writeWait сколько ждать записи одного сообщения
+	writeWait = 10 * time.Second
+	// pongWait соединение закрывается, если от клиента ничего не пришло, даже ответа на ping
+	pongWait = 60 * time.Second
+	// pingPeriod интервал ping, меньше pongWait, чтобы ответ успел прийти
+	pingPeriod = pongWait * 9 / 10
+	// authWait сколько ждать сообщения auth после подключения
+	authWait = 10 * time.Second
+	// presenceTTL запись присутствия продлевается с каждым ping и пропадает, если соединение исчезло без закрытия
+	presenceTTL = 3 * pingPeriod
+	// typingInterval сигнал набора от соединения отправляется не чаще
+	typingInterval = 2 * time.Second
+	// maxMessage наибольший размер сообщения клиента
+	maxMessage = 4096
+)
+
+// Conn соединение с доской проекта. Писать в websocket может только writePump,
+// остальные кладут сообщения в очередь send.
+type Conn struct {
+	id        string
+	hub       *Hub
+	ws        *websocket.Conn
+	projectID int
+	lang      string
+	send      chan []byte
+
+	mu         sync.Mutex
+	claims     jwt.Claims
+	eventScope model.EventScope
+	taskID     int
+	lastTyping time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+	closeCode int
+	closeText string
+}
+
+// Serve обслуживает соединение до его закрытия. token - токен из заголовка Authorization,
+// без него первым сообщением клиент должен прислать auth.
+func (h *Hub) Serve(ctx context.Context, ws *websocket.Conn, projectID int, token string) {
+	c := &Conn{
+		id:        newConnID(),
+		hub:       h,
+		ws:        ws,
+		projectID: projectID,
+		lang:      i18n.FromContext(ctx),
+		send:      make(chan []byte, h.cfg.Buffer),
+		done:      make(chan struct{}),
+	}
+	log := h.log.With(slog.Int("project_id", projectID), slog.String("conn_id", c.id))
+	defer ws.Close()
+	ws.SetReadLimit(maxMessage)
+
+	if token == "" {
+		var err error
+		if token, err = c.readAuth(); err != nil {
+			log.Info("board connection without authentication", sl.Err(err))
+			c.reject(model.Unauthorized("authentication required"), websocket.ClosePolicyViolation)
+			return
+		}
+	}
+	claims, err := h.cfg.Authenticate(token)
+	if err != nil {
+		c.reject(model.Unauthorized("invalid or expired token"), websocket.ClosePolicyViolation)
+		return
+	}
+	scope, err := h.cfg.Access(ctx, projectID, claims.UserID)
+	if err != nil {
+		log.Info("board access denied", slog.Int("user_id", claims.UserID), sl.Err(err))
+		code := websocket.ClosePolicyViolation
+		var domainErr *model.Error
+		if !errors.As(err, &domainErr) {
+			code = websocket.CloseInternalServerErr
+		}
+		c.reject(err, code)
+		return
+	}
+	c.claims, c.eventScope = claims, scope
+
+	if !h.register(c) {
+		c.reject(model.Conflict("server is shutting down"), websocket.CloseGoingAway)
+		return
+	}
+	defer h.unregister(c)
+	log = log.With(slog.Int("user_id", claims.UserID))
+	log.Info("board connection opened")
+
+	c.enqueueMessage(model.BoardMessage{Type: model.BoardReady, UserID: claims.UserID, Login: claims.Login})
+	c.updatePresence(ctx, true)
+	defer func() {
+		// контекст запроса уже может быть отменён, а запись присутствия нужно удалить
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), writeWait)
+		defer cancel()
+		if err := h.repo.RemovePresence(ctx, projectID, c.id); err != nil {
+			log.Warn("failed to remove presence", sl.Err(err))
+		}
+		h.signal(ctx, model.BoardSignal{Type: model.BoardPresence, ProjectID: projectID, ConnID: c.id})
+	}()
+
+	go c.writePump(ctx)
+	c.readPump(ctx)
+	log.Info("board connection closed", slog.Int("code", c.closeCode))
+}
+
+func newConnID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// readAuth ждёт сообщения auth и возвращает его токен
+func (c *Conn) readAuth() (string, error) {
+	if err := c.ws.SetReadDeadline(time.Now().Add(authWait)); err != nil {
+		return "", err
+	}
+	_, data, err := c.ws.ReadMessage()
+	if err != nil {
+		return "", err
+	}
+	var msg model.BoardMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return "", err
+	}
+	if msg.Type != model.BoardAuth || msg.Token == "" {
+		return "", errors.New("first message is not auth")
+	}
+	return msg.Token, nil
+}
+
+// reject отправляет ошибку и закрывает соединение до запуска writePump
+func (c *Conn) reject(err error, code int) {
+	_, body := resp.FromError(err, c.lang)
+	data, _ := json.Marshal(model.BoardMessage{Type: model.BoardError, Code: body.Code, Error: body.Error})
+	deadline := time.Now().Add(writeWait)
+	_ = c.ws.SetWriteDeadline(deadline)
+	_ = c.ws.WriteMessage(websocket.TextMessage, data)
+	_ = c.ws.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, body.Error), deadline)
+}
+
+func (c *Conn) readPump(ctx context.Context) {
+	defer c.close(websocket.CloseNormalClosure, "")
+	_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	c.ws.SetPongHandler(func(string) error {
+		return c.ws.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		_, data, err := c.ws.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				c.hub.log.Debug("board connection read failed", slog.String("conn_id", c.id), sl.Err(err))
+			}
+			return
+		}
+		_ = c.ws.SetReadDeadline(time.Now().Add(pongWait))
+
+		var msg model.BoardMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			c.sendError(resp.BadRequest("failed to decode message"))
+			continue
+		}
+		if err := c.handle(ctx, msg); err != nil {
+			c.sendError(err)
+		}
+	}
+}
+
+func (c *Conn) handle(ctx context.Context, msg model.BoardMessage) error {
+	switch msg.Type {
+	case model.BoardAuth:
+		// продление соединения новым токеном того же пользователя
+		claims, err := c.hub.cfg.Authenticate(msg.Token)
+		if err != nil {
+			return model.Unauthorized("invalid or expired token")
+		}
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if claims.UserID != c.claims.UserID {
+			return model.Forbidden("token belongs to another user")
+		}
+		c.claims = claims
+		return nil
+	case model.BoardView:
+		if msg.TaskID < 0 {
+			return model.Invalid("invalid task ID %d", msg.TaskID)
+		}
+		c.mu.Lock()
+		c.taskID = msg.TaskID
+		c.mu.Unlock()
+		c.updatePresence(ctx, true)
+		return nil
+	case model.BoardTyping:
+		if msg.TaskID <= 0 {
+			return model.Invalid("invalid task ID %d", msg.TaskID)
+		}
+		c.mu.Lock()
+		throttled := time.Since(c.lastTyping) < typingInterval
+		if !throttled {
+			c.lastTyping = time.Now()
+		}
+		claims := c.claims
+		c.mu.Unlock()
+		if !throttled {
+			c.hub.signal(ctx, model.BoardSignal{
+				Type: model.BoardTyping, ProjectID: c.projectID, ConnID: c.id,
+				UserID: claims.UserID, Login: claims.Login, TaskID: msg.TaskID,
+			})
+		}
+		return nil
+	}
+	return resp.BadRequest("unknown message type %q", msg.Type)
+}
+
+func (c *Conn) writePump(ctx context.Context) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		// закрытие сокета завершает и readPump
+		c.ws.Close()
+	}()
+	for {
+		select {
+		case data := <-c.send:
+			_ = c.ws.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.ws.WriteMessage(websocket.TextMessage, data); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+		case <-ticker.C:
+			c.mu.Lock()
+			expired := time.Now().After(c.claims.ExpiresAt)
+			c.mu.Unlock()
+			if expired {
+				c.close(websocket.ClosePolicyViolation, "token expired")
+				continue
+			}
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				c.close(websocket.CloseAbnormalClosure, "")
+				return
+			}
+			c.refresh(ctx)
+		case <-c.done:
+			if c.closeCode != websocket.CloseAbnormalClosure {
+				_ = c.ws.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(c.closeCode, c.closeText), time.Now().Add(writeWait))
+			}
+			return
+		}
+	}
+}
+
+// close закрывает соединение с кодом code, повторные вызовы ничего не делают
+func (c *Conn) close(code int, text string) {
+	c.closeOnce.Do(func() {
+		c.closeCode, c.closeText = code, text
+		close(c.done)
+	})
+}
+
+// enqueue кладёт сообщение в очередь. Клиент, который не успевает читать, отключается с кодом 1013:
+// после переподключения он получит актуальное присутствие, а задачи перечитает.
+func (c *Conn) enqueue(data []byte) {
+	select {
+	case <-c.done:
+	case c.send <- data:
+	default:
+		c.hub.log.Warn("board client is too slow, disconnecting", slog.String("conn_id", c.id))
+		c.close(websocket.CloseTryAgainLater, "client is too slow")
+	}
+}
+
+func (c *Conn) enqueueMessage(msg model.BoardMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		c.hub.log.Error("failed to marshal board message", sl.Err(err))
+		return
+	}
+	c.enqueue(data)
+}
+
+func (c *Conn) sendError(err error) {
+	_, body := resp.FromError(err, c.lang)
+	c.enqueueMessage(model.BoardMessage{Type: model.BoardError, Code: body.Code, Error: body.Error})
+}
+
+func (c *Conn) scope() model.EventScope {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.eventScope
+}
+
+// refresh продлевает присутствие и перечитывает область видимости: участника, которого убрали
+// из проекта, доска отключает
+func (c *Conn) refresh(ctx context.Context) {
+	c.updatePresence(ctx, false)
+
+	c.mu.Lock()
+	userID := c.claims.UserID
+	c.mu.Unlock()
+	scope, err := c.hub.cfg.Access(ctx, c.projectID, userID)
+	var domainErr *model.Error
+	switch {
+	case errors.As(err, &domainErr):
+		c.close(websocket.ClosePolicyViolation, domainErr.Error())
+	case err != nil:
+		c.hub.log.Warn("failed to refresh board access", slog.String("conn_id", c.id), sl.Err(err))
+	default:
+		c.mu.Lock()
+		c.eventScope = scope
+		c.mu.Unlock()
+	}
+}
+
+// updatePresence записывает или продлевает присутствие соединения; announce - сообщить доске об изменении
+func (c *Conn) updatePresence(ctx context.Context, announce bool) {
+	c.mu.Lock()
+	presence := model.Presence{UserID: c.claims.UserID, Login: c.claims.Login, TaskID: c.taskID}
+	c.mu.Unlock()
+	if err := c.hub.repo.SetPresence(ctx, c.projectID, c.id, presence, presenceTTL); err != nil {
+		c.hub.log.Error("failed to set presence", slog.String("conn_id", c.id), sl.Err(err))
+		return
+	}
+	if !announce {
+		return
+	}
+	c.hub.signal(ctx, model.BoardSignal{Type: model.BoardPresence, ProjectID: c.projectID, ConnID: c.id})
+}
@@ -0,0 +1,244 @@
+package board
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"Tasks/internal/events"
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// retryDelay пауза перед повторной подпиской на события или сигналы после ошибки
+const retryDelay = time.Second
+
+// Authenticator проверяет токен доступа из сообщения auth
+type Authenticator func(token string) (jwt.Claims, error)
+
+// Access проверяет, что пользователь участвует в проекте, и возвращает его область видимости задач
+type Access func(ctx context.Context, projectID int, userID int) (model.EventScope, error)
+
+// Config Buffer - очередь исходящих сообщений соединения, при переполнении соединение закрывается
+type Config struct {
+	Authenticate Authenticator
+	Access       Access
+	Buffer       int
+}
+
+// Hub соединения досок проектов этого экземпляра приложения. События задач приходят из events.Hub,
+// присутствие и набор комментариев - сигналами через Redis, поэтому пользователи, подключённые
+// к разным экземплярам, видят друг друга.
+type Hub struct {
+	log    *slog.Logger
+	repo   interfaces.BoardRepository
+	events *events.Hub
+	cfg    Config
+
+	mu     sync.Mutex
+	boards map[int]map[*Conn]struct{}
+	closed bool
+}
+
+func NewHub(log *slog.Logger, repo interfaces.BoardRepository, events *events.Hub, cfg Config) *Hub {
+	return &Hub{
+		log:    log.With(slog.String("component", "board/hub")),
+		repo:   repo,
+		events: events,
+		cfg:    cfg,
+		boards: make(map[int]map[*Conn]struct{}),
+	}
+}
+
+// Run раздаёт события и сигналы соединениям, пока не будет отменён ctx, затем закрывает соединения
+func (h *Hub) Run(ctx context.Context) {
+	h.log.Info("starting board hub")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		h.runEvents(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		h.runSignals(ctx)
+	}()
+	wg.Wait()
+	h.Close()
+	h.log.Info("board hub stopped")
+}
+
+func (h *Hub) runEvents(ctx context.Context) {
+	for ctx.Err() == nil {
+		sub, err := h.events.Subscribe(ctx, "")
+		if err != nil {
+			h.log.Error("failed to subscribe to task events", sl.Err(err))
+		} else {
+			for event := range sub.C {
+				h.dispatchEvent(event)
+			}
+			// подписку отключили: доска не успевала или остановлен поток событий
+			sub.Close()
+		}
+		wait(ctx, retryDelay)
+	}
+}
+
+func (h *Hub) runSignals(ctx context.Context) {
+	for ctx.Err() == nil {
+		signals, err := h.repo.BoardSignals(ctx)
+		if err != nil {
+			h.log.Error("failed to subscribe to board signals", sl.Err(err))
+		} else {
+			for signal := range signals {
+				h.dispatchSignal(ctx, signal)
+			}
+		}
+		wait(ctx, retryDelay)
+	}
+}
+
+func wait(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+// Close закрывает все соединения с кодом 1001 и запрещает новые
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, conns := range h.boards {
+		for c := range conns {
+			c.close(websocket.CloseGoingAway, "server is shutting down")
+		}
+	}
+}
+
+func (h *Hub) register(c *Conn) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return false
+	}
+	conns, ok := h.boards[c.projectID]
+	if !ok {
+		conns = make(map[*Conn]struct{})
+		h.boards[c.projectID] = conns
+	}
+	conns[c] = struct{}{}
+	return true
+}
+
+func (h *Hub) unregister(c *Conn) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conns := h.boards[c.projectID]
+	delete(conns, c)
+	if len(conns) == 0 {
+		delete(h.boards, c.projectID)
+	}
+}
+
+// conns соединения доски; копия, чтобы не держать блокировку во время отправки
+func (h *Hub) conns(projectID int) []*Conn {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	conns := make([]*Conn, 0, len(h.boards[projectID]))
+	for c := range h.boards[projectID] {
+		conns = append(conns, c)
+	}
+	return conns
+}
+
+func (h *Hub) dispatchEvent(event model.TaskEvent) {
+	if event.ProjectID == 0 {
+		return
+	}
+	conns := h.conns(event.ProjectID)
+	if len(conns) == 0 {
+		return
+	}
+	data, err := json.Marshal(model.BoardMessage{Type: model.BoardTaskEvent, Event: &event})
+	if err != nil {
+		h.log.Error("failed to marshal task event", sl.Err(err))
+		return
+	}
+	for _, c := range conns {
+		if events.Visible(c.scope(), event) {
+			c.enqueue(data)
+		}
+	}
+}
+
+func (h *Hub) dispatchSignal(ctx context.Context, signal model.BoardSignal) {
+	conns := h.conns(signal.ProjectID)
+	if len(conns) == 0 {
+		return
+	}
+	var msg model.BoardMessage
+	switch signal.Type {
+	case model.BoardPresence:
+		presence, err := h.presence(ctx, signal.ProjectID)
+		if err != nil {
+			h.log.Error("failed to get presence", slog.Int("project_id", signal.ProjectID), sl.Err(err))
+			return
+		}
+		msg = model.BoardMessage{Type: model.BoardPresence, Presence: presence}
+	case model.BoardTyping:
+		msg = model.BoardMessage{Type: model.BoardTyping, UserID: signal.UserID, Login: signal.Login, TaskID: signal.TaskID}
+	default:
+		return
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.log.Error("failed to marshal board message", sl.Err(err))
+		return
+	}
+	for _, c := range conns {
+		// свой набор пользователь не видит
+		if signal.Type == model.BoardTyping && c.id == signal.ConnID {
+			continue
+		}
+		c.enqueue(data)
+	}
+}
+
+// presence присутствие на доске: одна запись на пользователя и задачу, даже если у него открыто несколько вкладок
+func (h *Hub) presence(ctx context.Context, projectID int) ([]model.Presence, error) {
+	entries, err := h.repo.Presence(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[model.Presence]bool, len(entries))
+	presence := make([]model.Presence, 0, len(entries))
+	for _, p := range entries {
+		if !seen[p] {
+			seen[p] = true
+			presence = append(presence, p)
+		}
+	}
+	sort.Slice(presence, func(i, j int) bool {
+		if presence[i].UserID != presence[j].UserID {
+			return presence[i].UserID < presence[j].UserID
+		}
+		return presence[i].TaskID < presence[j].TaskID
+	})
+	return presence, nil
+}
+
+// signal публикует сигнал доски; без Redis доска работает, но другие её не увидят
+func (h *Hub) signal(ctx context.Context, signal model.BoardSignal) {
+	if err := h.repo.PublishBoardSignal(ctx, signal); err != nil {
+		h.log.Error("failed to publish board signal", slog.String("type", signal.Type), sl.Err(err))
+	}
+}
@@ -13,7 +13,14 @@ type Config struct {
 	Postgres       PostgresStorage `envconfig:"POSTGRES" required:"true"`
 	Redis          RedisStorage    `envconfig:"REDIS" required:"true"`
 	HTTP           HTTPServer      `envconfig:"HTTP_SERVER" required:"true"`
+	GRPC           GRPCServer      `envconfig:"GRPC_SERVER"`
 	KafkaAddresses []string        `envconfig:"KAFKA_ADDRESSES" required:"true"`
+	Scheduler      Scheduler       `envconfig:"SCHEDULER"`
+	Events         Events          `envconfig:"EVENTS"`
+	Webhooks       Webhooks        `envconfig:"WEBHOOKS"`
+	GraphQL        GraphQL         `envconfig:"GRAPHQL"`
+	Health         Health          `envconfig:"HEALTH"`
+	Metrics        Metrics         `envconfig:"METRICS"`
 }
 
 type PostgresStorage struct {
@@ -31,10 +38,78 @@ type HTTPServer struct {
 	Timeout     time.Duration `envconfig:"TIMEOUT" default:"4s"`
 	IdleTimeout time.Duration `envconfig:"IDLE_TIMEOUT" default:"60s"`
 	WithTimeout time.Duration `envconfig:"WITH_TIMEOUT" default:"10s"`
+	// JWTSecret ключ подписи токенов доступа (HS256)
+	JWTSecret string `envconfig:"JWT_SECRET" required:"true"`
+	// IdempotencyTTL сколько хранится ответ на запрос с заголовком Idempotency-Key
+	IdempotencyTTL time.Duration `envconfig:"IDEMPOTENCY_TTL" default:"24h"`
 	//User        string        `envconfig:"USER" required:"true"`
 	//Password    string        `envconfig:"PASSWORD" required:"true"`
 }
 
+// GRPCServer gRPC API; токены доступа проверяются ключом HTTP_SERVER_JWT_SECRET.
+// Пустой адрес отключает gRPC-сервер.
+type GRPCServer struct {
+	Address string `envconfig:"ADDRESS" default:"localhost:9090"`
+}
+
+// Scheduler настройки планировщика напоминаний о дедлайнах
+type Scheduler struct {
+	Interval   time.Duration   `envconfig:"INTERVAL" default:"1m"`
+	Thresholds []time.Duration `envconfig:"THRESHOLDS" default:"72h,24h,1h"`
+	// SyncTombstoneTTL сколько хранятся надгробия синхронизации; клиент с более старым токеном получает все задачи заново
+	SyncTombstoneTTL time.Duration `envconfig:"SYNC_TOMBSTONE_TTL" default:"720h"`
+}
+
+// Events настройки потока событий задач
+type Events struct {
+	// Replay сколько последних событий хранится для продолжения потока после переподключения
+	Replay int64 `envconfig:"REPLAY" default:"1000"`
+	// Buffer очередь событий одного подписчика, при переполнении подписчик отключается
+	Buffer int `envconfig:"BUFFER" default:"64"`
+}
+
+// Webhooks настройки доставки вебхуков
+type Webhooks struct {
+	// Interval опрос очереди доставок, Batch - сколько доставок выполняется одновременно
+	Interval time.Duration `envconfig:"INTERVAL" default:"5s"`
+	Batch    int           `envconfig:"BATCH" default:"16"`
+	// Timeout ожидание ответа получателя
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"10s"`
+	// MaxAttempts попыток доставки одного события; повторы - через BackoffBase с удвоением до BackoffMax
+	MaxAttempts int           `envconfig:"MAX_ATTEMPTS" default:"10"`
+	BackoffBase time.Duration `envconfig:"BACKOFF_BASE" default:"10s"`
+	BackoffMax  time.Duration `envconfig:"BACKOFF_MAX" default:"1h"`
+	// DisableAfter неудачных попыток подряд, после которых вебхук отключается
+	DisableAfter int `envconfig:"DISABLE_AFTER" default:"20"`
+	// AllowPrivate разрешает доставку на loopback и во внутренние сети, только для локальной разработки
+	AllowPrivate bool `envconfig:"ALLOW_PRIVATE" default:"false"`
+}
+
+// GraphQL настройки эндпоинта /graphql
+type GraphQL struct {
+	Enabled bool `envconfig:"ENABLED" default:"true"`
+	// MaxDepth наибольшая вложенность полей запроса
+	MaxDepth int `envconfig:"MAX_DEPTH" default:"10"`
+	// MaxComplexity наибольшая оценка стоимости запроса: поле стоит 1, списки умножают стоимость вложенных полей
+	MaxComplexity int `envconfig:"MAX_COMPLEXITY" default:"5000"`
+}
+
+// Health настройки /healthz и /readyz
+type Health struct {
+	// Timeout проверки одной зависимости
+	Timeout time.Duration `envconfig:"TIMEOUT" default:"2s"`
+	// ShutdownDelay сколько после сигнала остановки /readyz отвечает отказом, пока сервер ещё принимает
+	// запросы: за это время балансировщик успевает исключить экземпляр
+	ShutdownDelay time.Duration `envconfig:"SHUTDOWN_DELAY" default:"0s"`
+}
+
+// Metrics настройки /metrics
+type Metrics struct {
+	Enabled bool `envconfig:"ENABLED" default:"true"`
+	// TasksTimeout ограничение запроса к базе, которым при сборе метрик считаются задачи по статусам
+	TasksTimeout time.Duration `envconfig:"TASKS_TIMEOUT" default:"5s"`
+}
+
 func MustLoad() *Config {
 	var cfg Config
 
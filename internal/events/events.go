@@ -0,0 +1,204 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const (
+	// readBlock сколько ждать новых событий в одном чтении журнала
+	readBlock = 5 * time.Second
+	// retryDelay пауза после ошибки чтения журнала
+	retryDelay = time.Second
+)
+
+// Hub раздаёт события из общего журнала подписчикам этого экземпляра приложения: журнал читается
+// одним запросом на экземпляр, сколько бы ни было подписчиков. Подписчик, который не успевает
+// забирать события, отключается, и клиент продолжает поток с последнего полученного события.
+type Hub struct {
+	log    *slog.Logger
+	stream interfaces.EventStream
+	buffer int
+	replay int64
+
+	mu     sync.Mutex
+	subs   map[*Subscription]struct{}
+	closed bool
+}
+
+// Subscription подписка на события. C закрывается при отключении подписчика.
+// Replay - события после запрошенного ID, Last - ID последнего из них или запрошенный ID:
+// события C не позже Last уже есть в Replay. Complete = false - события после запрошенного ID
+// восстановить нельзя, клиенту нужно перечитать данные.
+type Subscription struct {
+	C        <-chan model.TaskEvent
+	Replay   []model.TaskEvent
+	Last     string
+	Complete bool
+
+	ch  chan model.TaskEvent
+	hub *Hub
+}
+
+// NewHub buffer - очередь событий подписчика, replay - сколько событий можно восстановить при переподключении
+func NewHub(log *slog.Logger, stream interfaces.EventStream, buffer int, replay int64) *Hub {
+	return &Hub{
+		log:    log.With(slog.String("component", "events/hub")),
+		stream: stream,
+		buffer: buffer,
+		replay: replay,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Run читает журнал и раздаёт новые события, пока не будет отменён ctx
+func (h *Hub) Run(ctx context.Context) {
+	h.log.Info("starting event hub")
+	last := ""
+	for ctx.Err() == nil {
+		var err error
+		if last == "" {
+			last, err = h.stream.LastEventID(ctx)
+		} else {
+			var events []model.TaskEvent
+			events, err = h.stream.ReadEvents(ctx, last, readBlock)
+			for _, event := range events {
+				h.broadcast(event)
+				last = event.ID
+			}
+		}
+		if err != nil && ctx.Err() == nil {
+			h.log.Error("failed to read events", sl.Err(err))
+			select {
+			case <-ctx.Done():
+			case <-time.After(retryDelay):
+			}
+		}
+	}
+	h.Close()
+	h.log.Info("event hub stopped")
+}
+
+// Subscribe подписывает на новые события. lastID != "" - сначала восстанавливаются события после него.
+func (h *Hub) Subscribe(ctx context.Context, lastID string) (*Subscription, error) {
+	ch := make(chan model.TaskEvent, h.buffer)
+	sub := &Subscription{C: ch, Last: lastID, Complete: true, ch: ch, hub: h}
+
+	// подписка до чтения журнала: события, опубликованные во время чтения, не теряются
+	h.mu.Lock()
+	if h.closed {
+		close(ch)
+	} else {
+		h.subs[sub] = struct{}{}
+	}
+	h.mu.Unlock()
+
+	if lastID == "" {
+		return sub, nil
+	}
+	replay, complete, err := h.stream.EventsAfter(ctx, lastID, h.replay)
+	if err != nil {
+		sub.Close()
+		return nil, err
+	}
+	sub.Complete = complete
+	if !complete {
+		// с пропуском событий продолжать нет смысла, клиент перечитает данные и получит новые события
+		sub.Last = ""
+		return sub, nil
+	}
+	sub.Replay = replay
+	if len(replay) > 0 {
+		sub.Last = replay[len(replay)-1].ID
+	}
+	return sub, nil
+}
+
+// Close отключает все подписки и запрещает новые, чтобы потоки завершились при остановке сервера
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+func (h *Hub) broadcast(event model.TaskEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subs {
+		select {
+		case sub.ch <- event:
+		default:
+			h.log.Warn("subscriber is too slow, disconnecting", slog.String("event_id", event.ID))
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// Close отписывает от событий, повторный вызов ничего не делает
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subs[s]; ok {
+		delete(s.hub.subs, s)
+		close(s.ch)
+	}
+}
+
+// Visible видно ли событие пользователю с областью видимости scope
+func Visible(scope model.EventScope, event model.TaskEvent) bool {
+	if scope.Admin {
+		return true
+	}
+	if event.ProjectID != 0 && slices.Contains(scope.Projects, event.ProjectID) {
+		return true
+	}
+	for _, userID := range event.Assignees {
+		if userID == scope.UserID || slices.Contains(scope.Users, userID) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidID проверяет ID события: ID записи журнала вида "<миллисекунды>-<номер>"
+func ValidID(id string) bool {
+	_, _, ok := parseID(id)
+	return ok
+}
+
+// After true, если событие с ID id опубликовано позже события other. Пустой other раньше любого события.
+func After(id, other string) bool {
+	ms, seq, _ := parseID(id)
+	otherMs, otherSeq, _ := parseID(other)
+	return ms > otherMs || ms == otherMs && seq > otherSeq
+}
+
+func parseID(id string) (uint64, uint64, bool) {
+	msPart, seqPart, found := strings.Cut(id, "-")
+	if !found {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseUint(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseUint(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
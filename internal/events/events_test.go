@@ -0,0 +1,173 @@
+package events
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+)
+
+// memoryStream журнал событий в памяти, хранит не больше limit последних событий
+type memoryStream struct {
+	events []model.TaskEvent
+	limit  int
+	// trimmed - из журнала вытеснялись события
+	trimmed bool
+}
+
+func (m *memoryStream) PublishEvent(_ context.Context, event model.TaskEvent) error {
+	m.events = append(m.events, event)
+	if len(m.events) > m.limit {
+		m.events = m.events[len(m.events)-m.limit:]
+		m.trimmed = true
+	}
+	return nil
+}
+
+func (m *memoryStream) EventsAfter(_ context.Context, lastID string, count int64) ([]model.TaskEvent, bool, error) {
+	complete := len(m.events) == 0 || !After(m.events[0].ID, lastID) || !m.trimmed
+	var result []model.TaskEvent
+	for _, event := range m.events {
+		if After(event.ID, lastID) {
+			result = append(result, event)
+		}
+	}
+	if int64(len(result)) > count {
+		return result[:count], false, nil
+	}
+	return result, complete, nil
+}
+
+func (m *memoryStream) LastEventID(context.Context) (string, error) {
+	if len(m.events) == 0 {
+		return "0-0", nil
+	}
+	return m.events[len(m.events)-1].ID, nil
+}
+
+func (m *memoryStream) ReadEvents(ctx context.Context, _ string, _ time.Duration) ([]model.TaskEvent, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func event(id string) model.TaskEvent {
+	return model.TaskEvent{ID: id, Type: model.TaskEventUpdated, TaskID: 1}
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	tests := []struct {
+		name     string
+		lastID   string
+		replay   []string
+		last     string
+		complete bool
+	}{
+		{name: "new subscriber", complete: true},
+		{name: "resume", lastID: "1-1", replay: []string{"2-0", "3-0"}, last: "3-0", complete: true},
+		{name: "up to date", lastID: "3-0", last: "3-0", complete: true},
+		{name: "missed events were trimmed", lastID: "0-5", complete: false},
+		{name: "too many missed events", lastID: "1-0", complete: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &memoryStream{limit: 3}
+			for _, id := range []string{"1-0", "1-1", "2-0", "3-0"} {
+				require.NoError(t, stream.PublishEvent(context.Background(), event(id)))
+			}
+			hub := NewHub(slogdiscard.NewDiscardLogger(), stream, 1, 2)
+
+			sub, err := hub.Subscribe(context.Background(), tt.lastID)
+			require.NoError(t, err)
+			defer sub.Close()
+
+			var replay []string
+			for _, e := range sub.Replay {
+				replay = append(replay, e.ID)
+			}
+			require.Equal(t, tt.replay, replay)
+			require.Equal(t, tt.last, sub.Last)
+			require.Equal(t, tt.complete, sub.Complete)
+		})
+	}
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	hub := NewHub(slogdiscard.NewDiscardLogger(), &memoryStream{limit: 10}, 1, 10)
+	slow, err := hub.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	fast, err := hub.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	defer fast.Close()
+
+	hub.broadcast(event("1-0"))
+	require.Equal(t, "1-0", (<-fast.C).ID)
+	hub.broadcast(event("2-0"))
+
+	// очередь медленного подписчика переполнена: он получает то, что успело попасть в очередь, и отключается
+	require.Equal(t, "1-0", (<-slow.C).ID)
+	_, ok := <-slow.C
+	require.False(t, ok)
+	require.Equal(t, "2-0", (<-fast.C).ID)
+	slow.Close()
+}
+
+func TestHub_Close(t *testing.T) {
+	hub := NewHub(slogdiscard.NewDiscardLogger(), &memoryStream{limit: 10}, 1, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx)
+		close(done)
+	}()
+
+	sub, err := hub.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	cancel()
+	<-done
+
+	_, ok := <-sub.C
+	require.False(t, ok)
+	late, err := hub.Subscribe(context.Background(), "")
+	require.NoError(t, err)
+	_, ok = <-late.C
+	require.False(t, ok)
+}
+
+func TestVisible(t *testing.T) {
+	event := model.TaskEvent{TaskID: 1, ProjectID: 4, Assignees: []int{7}}
+	tests := []struct {
+		name    string
+		scope   model.EventScope
+		visible bool
+	}{
+		{name: "admin", scope: model.EventScope{UserID: 1, Admin: true}, visible: true},
+		{name: "assignee", scope: model.EventScope{UserID: 7}, visible: true},
+		{name: "teammate of assignee", scope: model.EventScope{UserID: 2, Users: []int{2, 7}}, visible: true},
+		{name: "project manager", scope: model.EventScope{UserID: 3, Projects: []int{4}}, visible: true},
+		{name: "stranger", scope: model.EventScope{UserID: 5, Users: []int{5, 6}, Projects: []int{8}}, visible: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.visible, Visible(tt.scope, event))
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	require.True(t, After("2-0", "1-9"))
+	require.True(t, After("10-0", "9-0"))
+	require.True(t, After("1-1", "1-0"))
+	require.False(t, After("1-0", "1-0"))
+	require.True(t, After("1-0", ""))
+	require.True(t, ValidID("1700000000000-3"))
+	require.False(t, ValidID("abc"))
+	require.False(t, ValidID("1-x"))
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/model"
+)
+
+// Оценка стоимости запроса: каждое поле стоит 1, поля списков умножают стоимость вложенных полей
+// на ожидаемое число элементов. Для tasks это аргумент first (по умолчанию 50 для Query.tasks
+// и 10 для User.tasks, не больше model.MaxPageLimit), у задачи обычно несколько исполнителей.
+// Стоимость не переполняется: суммы и произведения ограничены math.MaxInt.
+const (
+	defaultRootTasks = 50
+	defaultUserTasks = 10
+	assigneesPerTask = 5
+)
+
+// complexity стоимость операции operationName; запрос, который не разбирается, стоит 0 -
+// синтаксическую ошибку вернёт graphql-go
+func complexity(query, operationName string, variables map[string]interface{}) int {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0
+	}
+	op := doc.Operations.ForName(operationName)
+	if op == nil {
+		return 0
+	}
+	c := costing{doc: doc, vars: variables, op: op}
+	return c.selectionSet(op.SelectionSet, true, map[string]bool{})
+}
+
+type costing struct {
+	doc  *ast.QueryDocument
+	vars map[string]interface{}
+	op   *ast.OperationDefinition
+}
+
+// selectionSet visited - фрагменты на текущем пути, чтобы циклические фрагменты не зациклили оценку
+func (c costing) selectionSet(set ast.SelectionSet, root bool, visited map[string]bool) int {
+	total := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			total = addCost(total, addCost(1, mulCost(c.multiplier(sel, root), c.selectionSet(sel.SelectionSet, false, visited))))
+		case *ast.InlineFragment:
+			total = addCost(total, c.selectionSet(sel.SelectionSet, root, visited))
+		case *ast.FragmentSpread:
+			fragment := c.doc.Fragments.ForName(sel.Name)
+			if fragment == nil || visited[sel.Name] {
+				continue
+			}
+			visited[sel.Name] = true
+			total = addCost(total, c.selectionSet(fragment.SelectionSet, root, visited))
+			delete(visited, sel.Name)
+		}
+	}
+	return total
+}
+
+func (c costing) multiplier(field *ast.Field, root bool) int {
+	switch field.Name {
+	case "tasks":
+		if root {
+			return c.intArg(field, "first", defaultRootTasks)
+		}
+		return c.intArg(field, "first", defaultUserTasks)
+	case "assignees":
+		return assigneesPerTask
+	}
+	return 1
+}
+
+// intArg значение аргумента-числа: литерал, переменная или её значение по умолчанию.
+// Значение больше model.MaxPageLimit считается равным ему: больше страница не бывает.
+func (c costing) intArg(field *ast.Field, name string, def int) int {
+	arg := field.Arguments.ForName(name)
+	if arg == nil || arg.Value == nil {
+		return def
+	}
+	value := arg.Value
+	if value.Kind == ast.Variable {
+		if v, ok := c.vars[value.Raw].(float64); ok && v > 0 {
+			return int(math.Min(v, model.MaxPageLimit))
+		}
+		definition := c.op.VariableDefinitions.ForName(value.Raw)
+		if definition == nil || definition.DefaultValue == nil {
+			return def
+		}
+		value = definition.DefaultValue
+	}
+	n, err := strconv.Atoi(value.Raw)
+	// при переполнении Atoi возвращает границу int и ErrRange
+	if (err != nil && !errors.Is(err, strconv.ErrRange)) || n <= 0 {
+		return def
+	}
+	return min(n, model.MaxPageLimit)
+}
+
+// addCost сумма неотрицательных стоимостей, не больше math.MaxInt
+func addCost(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// mulCost произведение неотрицательных стоимостей, не больше math.MaxInt
+func mulCost(a, b int) int {
+	if a != 0 && b > math.MaxInt/a {
+		return math.MaxInt
+	}
+	return a * b
+}
+
+// errTooComplex ошибка запроса дороже лимита
+func errTooComplex(cost, limit int) error {
+	return resp.BadRequest("query complexity %d exceeds the limit of %d", cost, limit)
+}
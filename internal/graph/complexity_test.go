@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexity(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]interface{}
+		want      int
+	}{
+		{
+			name:  "scalar fields",
+			query: `{ me { id login } }`,
+			want:  3,
+		},
+		{
+			name:  "default page size",
+			query: `{ tasks { nodes { id } } }`,
+			// tasks + 50 * (nodes + id)
+			want: 101,
+		},
+		{
+			name:  "first literal",
+			query: `{ tasks(first: 10) { nodes { id assignees { login } } } }`,
+			// tasks + 10 * (nodes + id + assignees + 5 * login)
+			want: 81,
+		},
+		{
+			name:      "first from variable",
+			query:     `query Q($n: Int) { tasks(first: $n) { nodes { id } } }`,
+			variables: map[string]interface{}{"n": float64(2)},
+			want:      5,
+		},
+		{
+			name:  "variable default",
+			query: `query Q($n: Int = 3) { tasks(first: $n) { nodes { id } } }`,
+			want:  7,
+		},
+		{
+			name:  "first above the page limit",
+			query: `{ tasks(first: 2147483647) { nodes { id } } }`,
+			// tasks + 200 * (nodes + id)
+			want: 401,
+		},
+		{
+			name:      "variable above the page limit",
+			query:     `query Q($n: Int) { tasks(first: $n) { nodes { id } } }`,
+			variables: map[string]interface{}{"n": float64(1e300)},
+			want:      401,
+		},
+		{
+			name: "nested connections with huge first",
+			query: `{ tasks(first: 2147483647) { nodes { assignees { tasks(first: 2147483647) { nodes {
+				assignees { tasks(first: 2147483647) { nodes { id } } } } } } } } }`,
+			// tasks + 200 * (nodes + assignees + 5 * (tasks + 200 * (nodes + assignees + 5 * (tasks + 200 * (nodes + id)))))
+			want: 1 + 200*(2+5*(1+200*(2+5*(1+200*2)))),
+		},
+		{
+			name: "cost saturates instead of overflowing",
+			// каждый уровень умножает стоимость на 200 * 5
+			query: "{" + strings.Repeat(" tasks(first: 200) { nodes { assignees {", 8) + " id" + strings.Repeat(" } } }", 8) + " }",
+			want:  math.MaxInt,
+		},
+		{
+			name:  "nested user tasks",
+			query: `{ user(id: "1") { tasks { id } } }`,
+			// user + tasks + 10 * id
+			want: 12,
+		},
+		{
+			name:  "fragments",
+			query: `query { me { ...U } } fragment U on User { id ... on User { login } }`,
+			want:  3,
+		},
+		{
+			name:  "cyclic fragments",
+			query: `{ me { ...A } } fragment A on User { id ...B } fragment B on User { login ...A }`,
+			want:  3,
+		},
+		{
+			name:      "named operation",
+			query:     `query A { me { id } } query B { tasks(first: 1) { nodes { id } } }`,
+			operation: "B",
+			want:      3,
+		},
+		{
+			name:  "unknown operation",
+			query: `query A { me { id } } query B { me { id } }`,
+			want:  0,
+		},
+		{
+			name:  "syntax error",
+			query: `{ me {`,
+			want:  0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, complexity(tt.query, tt.operation, tt.variables))
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package graph GraphQL API поверх задач, пользователей, проектов и назначений.
+// Связанные сущности загружаются пакетно через DataLoader, стоимость запроса ограничена.
+package graph
+
+import (
+	_ "embed"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+	graphql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/service"
+)
+
+//go:embed schema.graphql
+var schemaSDL string
+
+// maxParallelism сколько полей одного запроса разрешается одновременно
+const maxParallelism = 20
+
+type Config struct {
+	// MaxDepth наибольшая вложенность полей запроса
+	MaxDepth int
+	// MaxComplexity наибольшая стоимость запроса, см. complexity
+	MaxComplexity int
+}
+
+// Request тело запроса POST /graphql
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type Handler struct {
+	schema  *graphql.Schema
+	service *service.Service
+	log     *slog.Logger
+	cfg     Config
+}
+
+func New(log *slog.Logger, svc *service.Service, cfg Config) *Handler {
+	root := &resolver{service: svc, log: log}
+	return &Handler{
+		schema: graphql.MustParseSchema(schemaSDL, root,
+			graphql.MaxDepth(cfg.MaxDepth),
+			graphql.MaxParallelism(maxParallelism),
+			graphql.UseStringDescriptions(),
+		),
+		service: svc,
+		log:     log,
+		cfg:     cfg,
+	}
+}
+
+// ServeHTTP выполняет запрос. Ошибки разбора тела и превышение стоимости возвращаются
+// со статусом 400 в формате ответа GraphQL, ошибки полей - в errors ответа со статусом 200.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	log := h.log.With(slog.String("op", "graph.ServeHTTP"))
+	ctx := r.Context()
+
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		log.Info("failed to decode request body", sl.Err(err))
+		h.reject(w, r, resp.BadRequest("failed to decode request"))
+		return
+	}
+	if req.Query == "" {
+		h.reject(w, r, resp.BadRequest("invalid value for field %s", "query"))
+		return
+	}
+	if h.cfg.MaxComplexity > 0 {
+		if cost := complexity(req.Query, req.OperationName, req.Variables); cost > h.cfg.MaxComplexity {
+			log.Info("query rejected", slog.Int("complexity", cost))
+			h.reject(w, r, errTooComplex(cost, h.cfg.MaxComplexity))
+			return
+		}
+	}
+
+	ctx = withLoaders(ctx, newLoaders(h.service))
+	render.JSON(w, r, h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables))
+}
+
+// reject ответ GraphQL с одной ошибкой запроса
+func (h *Handler) reject(w http.ResponseWriter, r *http.Request, err error) {
+	e := newError(i18n.FromContext(r.Context()), err)
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, &graphql.Response{Errors: []*gqlerrors.QueryError{{
+		Message:    e.Error(),
+		Extensions: e.Extensions(),
+	}}})
+}
@@ -0,0 +1,157 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+	mockery "Tasks/internal/service/mocks"
+)
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// do выполняет запрос пользователя 7 на языке lang
+func do(t *testing.T, h *Handler, lang string, req Request) (int, response) {
+	t.Helper()
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	ctx := i18n.WithLanguage(auth.WithUserID(context.Background(), 7), lang)
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body)).WithContext(ctx)
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+
+	var out response
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &out))
+	return rr.Code, out
+}
+
+func newHandler(t *testing.T, storage *mockery.StorageRepository) *Handler {
+	svc := service.NewService(slogdiscard.NewDiscardLogger(), storage, mockery.NewCacheRepository(t), nil, nil)
+	return New(slogdiscard.NewDiscardLogger(), svc, Config{MaxDepth: 10, MaxComplexity: 1000})
+}
+
+// TestHandler_Batching исполнители и проекты всех задач страницы загружаются одним запросом каждые
+func TestHandler_Batching(t *testing.T) {
+	deadline := time.Date(2030, 1, 15, 10, 0, 0, 0, time.UTC)
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("ListTasks", mock.Anything, model.TaskFilter{VisibleTo: 7, Statuses: []string{"todo"}},
+		model.PageRequest{Limit: 3, Sort: model.SortDeadline}).
+		Return(model.Page[model.Task]{Items: []model.Task{
+			{ID: 1, NameTask: "a", Status: "todo", Deadline: deadline, ProjectID: 10},
+			{ID: 2, NameTask: "b", Status: "todo", Deadline: deadline, ProjectID: 10, Labels: []string{"bug"}},
+			{ID: 3, NameTask: "c", Status: "todo", Deadline: deadline},
+		}}, nil).Once()
+	storageMock.On("AssigneesByTaskIDs", mock.Anything, mock.MatchedBy(func(ids []int) bool {
+		return sameIDs(ids, 1, 2, 3)
+	})).Return(map[int][]model.User{
+		1: {{ID: 7, Login: "me"}},
+		2: {{ID: 7, Login: "me"}, {ID: 8, Login: "other"}},
+	}, nil).Once()
+	storageMock.On("ProjectsByIDs", mock.Anything, []int{10}).
+		Return([]model.Project{{ID: 10, Name: "project", ManagerID: 8}}, nil).Once()
+	storageMock.On("UsersByIDs", mock.Anything, []int{8}).
+		Return([]model.User{{ID: 8, Login: "other"}}, nil).Once()
+
+	code, res := do(t, newHandler(t, storageMock), "en", Request{
+		Query: `query($first: Int) {
+			tasks(statuses: ["todo"], first: $first) {
+				nodes { id title labels deadline assignees { login } project { name manager { login } } }
+				nextCursor
+			}
+		}`,
+		Variables: map[string]interface{}{"first": 3},
+	})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"tasks": {"nextCursor": null, "nodes": [
+		{"id": "1", "title": "a", "labels": [], "deadline": "2030-01-15T10:00:00Z",
+		 "assignees": [{"login": "me"}], "project": {"name": "project", "manager": {"login": "other"}}},
+		{"id": "2", "title": "b", "labels": ["bug"], "deadline": "2030-01-15T10:00:00Z",
+		 "assignees": [{"login": "me"}, {"login": "other"}], "project": {"name": "project", "manager": {"login": "other"}}},
+		{"id": "3", "title": "c", "labels": [], "deadline": "2030-01-15T10:00:00Z", "assignees": [], "project": null}
+	]}}`, string(res.Data))
+}
+
+func sameIDs(got []int, want ...int) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	seen := make(map[int]bool, len(got))
+	for _, id := range got {
+		seen[id] = true
+	}
+	for _, id := range want {
+		if !seen[id] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHandler_Errors(t *testing.T) {
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("TasksByIDs", mock.Anything, []int{404}).Return([]model.Task{}, nil).Once()
+	storageMock.On("TaskUpdateStatus", mock.Anything, "done", 1, 2).
+		Return(model.Task{}, model.PreconditionFailed("task %d has been modified, current version is %d", 1, 3)).Once()
+	h := newHandler(t, storageMock)
+
+	// ненайденная задача - null без ошибки
+	code, res := do(t, h, "en", Request{Query: `{ task(id: "404") { id } }`})
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{"task": null}`, string(res.Data))
+
+	code, res = do(t, h, "ru", Request{Query: `mutation { updateTaskStatus(id: "1", status: "done", version: 2) { id } }`})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "задача 1 изменилась, текущая версия 3", res.Errors[0].Message)
+	require.Equal(t, "precondition_failed", res.Errors[0].Extensions["code"])
+
+	code, res = do(t, h, "en", Request{Query: `mutation { createTask(input: {title: "", description: "d"}) { id } }`})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "validation_failed", res.Errors[0].Extensions["code"])
+	fields := res.Errors[0].Extensions["errors"].([]interface{})
+	require.Len(t, fields, 2)
+	require.Equal(t, "title", fields[0].(map[string]interface{})["field"])
+	require.Equal(t, "deadline", fields[1].(map[string]interface{})["field"])
+}
+
+func TestHandler_Limits(t *testing.T) {
+	h := newHandler(t, mockery.NewStorageRepository(t))
+
+	code, res := do(t, h, "ru", Request{Query: `{ tasks(first: 200) { nodes { id assignees { id login } } } }`})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "сложность запроса 2601 превышает предел 1000", res.Errors[0].Message)
+	require.Equal(t, "bad_request", res.Errors[0].Extensions["code"])
+
+	code, res = do(t, h, "en", Request{Query: `{ me { tasks(first: 1) { project { manager { tasks(first: 1) {
+		project { manager { tasks(first: 1) { project { manager { tasks(first: 1) { id } } } } } } } } } } } }`})
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Errors, 1)
+	require.Contains(t, res.Errors[0].Message, "exceeds max depth 10")
+
+	code, res = do(t, h, "en", Request{})
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Equal(t, "invalid value for field query", res.Errors[0].Message)
+}
@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/graph-gophers/dataloader"
+
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+)
+
+// loaderWait сколько загрузчик собирает ключи перед запросом к базе. Поля одного уровня запроса
+// разрешаются параллельно, поэтому за это время успевают прийти ключи всех элементов списка.
+const loaderWait = 2 * time.Millisecond
+
+// loaders загрузчики одного запроса: каждый собирает ID, запрошенные разными полями,
+// и загружает их одним запросом к базе. Результаты кэшируются до конца запроса.
+type loaders struct {
+	tasks     *dataloader.Loader
+	users     *dataloader.Loader
+	projects  *dataloader.Loader
+	assignees *dataloader.Loader
+	userTasks *dataloader.Loader
+}
+
+func newLoaders(svc *service.Service) *loaders {
+	opts := []dataloader.Option{dataloader.WithWait(loaderWait), dataloader.WithBatchCapacity(model.MaxPageLimit)}
+	return &loaders{
+		tasks: dataloader.NewBatchedLoader(batchByID(svc.TasksByIDs, func(t model.Task) int { return t.ID },
+			"task with ID %d not found"), opts...),
+		users: dataloader.NewBatchedLoader(batchByID(svc.UsersByIDs, func(u model.User) int { return u.ID },
+			"user with ID %d not found"), opts...),
+		projects: dataloader.NewBatchedLoader(batchByID(svc.ProjectsByIDs, func(p model.Project) int { return p.ID },
+			"project with ID %d not found"), opts...),
+		assignees: dataloader.NewBatchedLoader(batchAssignees(svc), opts...),
+		userTasks: dataloader.NewBatchedLoader(batchUserTasks(svc), opts...),
+	}
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
+
+// idKey ключ загрузчика - ID сущности
+type idKey int
+
+func (k idKey) String() string   { return strconv.Itoa(int(k)) }
+func (k idKey) Raw() interface{} { return int(k) }
+
+// userTasksKey задачи пользователя: запросы с разным limit загружаются отдельно
+type userTasksKey struct {
+	userID int
+	limit  int
+}
+
+func (k userTasksKey) String() string   { return strconv.Itoa(k.userID) + "/" + strconv.Itoa(k.limit) }
+func (k userTasksKey) Raw() interface{} { return k }
+
+func ids(keys dataloader.Keys) []int {
+	out := make([]int, len(keys))
+	for i, key := range keys {
+		out[i] = key.Raw().(int)
+	}
+	return out
+}
+
+func failAll(n int, err error) []*dataloader.Result {
+	results := make([]*dataloader.Result, n)
+	for i := range results {
+		results[i] = &dataloader.Result{Error: err}
+	}
+	return results
+}
+
+// batchByID загрузка сущностей по ID; ID, которых нет в базе, получают ошибку NotFound с форматом notFound
+func batchByID[T any](load func(context.Context, []int) ([]T, error), id func(T) int, notFound string) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		items, err := load(ctx, ids(keys))
+		if err != nil {
+			return failAll(len(keys), err)
+		}
+		byID := make(map[int]T, len(items))
+		for _, item := range items {
+			byID[id(item)] = item
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			item, ok := byID[key.Raw().(int)]
+			if !ok {
+				results[i] = &dataloader.Result{Error: model.NotFound(notFound, key.Raw())}
+				continue
+			}
+			results[i] = &dataloader.Result{Data: item}
+		}
+		return results
+	}
+}
+
+func batchAssignees(svc *service.Service) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		assignees, err := svc.AssigneesByTaskIDs(ctx, ids(keys))
+		if err != nil {
+			return failAll(len(keys), err)
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: assignees[key.Raw().(int)]}
+		}
+		return results
+	}
+}
+
+// batchUserTasks один запрос на каждое значение limit, обычно оно у всех ключей одно
+func batchUserTasks(svc *service.Service) dataloader.BatchFunc {
+	return func(ctx context.Context, keys dataloader.Keys) []*dataloader.Result {
+		byLimit := make(map[int][]int)
+		for _, key := range keys {
+			k := key.Raw().(userTasksKey)
+			byLimit[k.limit] = append(byLimit[k.limit], k.userID)
+		}
+		tasks := make(map[userTasksKey][]model.Task, len(keys))
+		for limit, userIDs := range byLimit {
+			loaded, err := svc.TasksByAssignees(ctx, userIDs, limit)
+			if err != nil {
+				return failAll(len(keys), err)
+			}
+			for _, userID := range userIDs {
+				tasks[userTasksKey{userID: userID, limit: limit}] = loaded[userID]
+			}
+		}
+		results := make([]*dataloader.Result, len(keys))
+		for i, key := range keys {
+			results[i] = &dataloader.Result{Data: tasks[key.Raw().(userTasksKey)]}
+		}
+		return results
+	}
+}
+
+func (l *loaders) task(ctx context.Context, id int) (model.Task, error) {
+	v, err := l.tasks.Load(ctx, idKey(id))()
+	if err != nil {
+		return model.Task{}, err
+	}
+	return v.(model.Task), nil
+}
+
+func (l *loaders) user(ctx context.Context, id int) (model.User, error) {
+	v, err := l.users.Load(ctx, idKey(id))()
+	if err != nil {
+		return model.User{}, err
+	}
+	return v.(model.User), nil
+}
+
+func (l *loaders) project(ctx context.Context, id int) (model.Project, error) {
+	v, err := l.projects.Load(ctx, idKey(id))()
+	if err != nil {
+		return model.Project{}, err
+	}
+	return v.(model.Project), nil
+}
+
+func (l *loaders) taskAssignees(ctx context.Context, taskID int) ([]model.User, error) {
+	v, err := l.assignees.Load(ctx, idKey(taskID))()
+	if err != nil {
+		return nil, err
+	}
+	return v.([]model.User), nil
+}
+
+func (l *loaders) tasksOf(ctx context.Context, userID int, limit int) ([]model.Task, error) {
+	v, err := l.userTasks.Load(ctx, userTasksKey{userID: userID, limit: limit})()
+	if err != nil {
+		return nil, err
+	}
+	return v.([]model.Task), nil
+}
+
+// forgetTask сбрасывает загруженную задачу, её исполнителей и задачи пользователей после изменения
+func (l *loaders) forgetTask(ctx context.Context, taskID int) {
+	l.tasks.Clear(ctx, idKey(taskID))
+	l.assignees.Clear(ctx, idKey(taskID))
+	l.userTasks.ClearAll()
+}
@@ -0,0 +1,488 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+
+	"Tasks/internal/http-server/middleware/auth"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/lib/validation"
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+)
+
+// validate правила те же, что у запросов HTTP API; поля в ошибках называются как в схеме
+var validate = validation.New()
+
+// newTask проверяемые поля CreateTaskInput
+type newTask struct {
+	Title        string    `json:"title" validate:"required,max=255"`
+	Description  string    `json:"description" validate:"required"`
+	Deadline     time.Time `json:"deadline" validate:"required_without=DeadlineExpr,future"`
+	DeadlineExpr string    `json:"deadlineExpr" validate:"excluded_with=Deadline"`
+	Priority     string    `json:"priority" validate:"priority"`
+}
+
+// newProject проверяемые поля createProject
+type newProject struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+// gqlError ошибка поля: сообщение на языке запроса, в extensions - код и ошибки полей, как в HTTP API
+type gqlError struct {
+	message string
+	body    resp.Response
+}
+
+func newError(lang string, err error) *gqlError {
+	_, body := resp.FromError(err, lang)
+	return &gqlError{message: body.Error, body: body}
+}
+
+func (e *gqlError) Error() string { return e.message }
+
+func (e *gqlError) Extensions() map[string]interface{} {
+	ext := map[string]interface{}{"code": e.body.Code}
+	if len(e.body.Errors) > 0 {
+		ext["errors"] = e.body.Errors
+	}
+	return ext
+}
+
+type resolver struct {
+	service *service.Service
+	log     *slog.Logger
+}
+
+// fail пишет ошибку в лог: внутренние - как ошибки, ошибки клиента - как информацию
+func (r *resolver) fail(ctx context.Context, op string, msg string, err error) error {
+	log := r.log.With(slog.String("op", op))
+	if httpStatus, _ := resp.FromError(err, i18n.Default); httpStatus >= http.StatusInternalServerError {
+		log.Error(msg, sl.Err(err))
+	} else {
+		log.Info(msg, sl.Err(err))
+	}
+	return newError(i18n.FromContext(ctx), err)
+}
+
+// caller ID аутентифицированного пользователя; маршрут /graphql закрыт аутентификацией,
+// проверка защищает от вызова обработчика без неё
+func caller(ctx context.Context) (int, error) {
+	userID, ok := auth.UserID(ctx)
+	if !ok {
+		return 0, model.Unauthorized("authentication required")
+	}
+	return userID, nil
+}
+
+func parseID(id graphql.ID, field string) (int, error) {
+	n, err := strconv.Atoi(string(id))
+	if err != nil || n <= 0 {
+		return 0, resp.BadRequest("invalid value for field %s", field)
+	}
+	return n, nil
+}
+
+// optionalID ID необязательного аргумента, 0 - аргумент не задан
+func optionalID(id *graphql.ID, field string) (int, error) {
+	if id == nil {
+		return 0, nil
+	}
+	return parseID(*id, field)
+}
+
+// Query
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	const op = "graph.Me"
+	userID, err := caller(ctx)
+	if err != nil {
+		return nil, r.fail(ctx, op, "unauthenticated", err)
+	}
+	user, err := loadersFrom(ctx).user(ctx, userID)
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve user", err)
+	}
+	return &userResolver{r: r, user: user}, nil
+}
+
+func (r *resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	const op = "graph.Task"
+	taskID, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	task, err := loadersFrom(ctx).task(ctx, taskID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve task", err)
+	}
+	return &taskResolver{r: r, task: task}, nil
+}
+
+type tasksArgs struct {
+	AssigneeID *graphql.ID
+	ProjectID  *graphql.ID
+	Statuses   *[]string
+	First      int32
+	After      *string
+}
+
+// Tasks страница задач, видимых вызывающему пользователю, по дедлайну
+func (r *resolver) Tasks(ctx context.Context, args tasksArgs) (*connectionResolver, error) {
+	const op = "graph.Tasks"
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, r.fail(ctx, op, "unauthenticated", err)
+	}
+	filter := model.TaskFilter{VisibleTo: callerID}
+	if filter.UserID, err = optionalID(args.AssigneeID, "assigneeId"); err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	if filter.ProjectID, err = optionalID(args.ProjectID, "projectId"); err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	if args.Statuses != nil {
+		filter.Statuses = *args.Statuses
+	}
+
+	if args.First <= 0 || args.First > model.MaxPageLimit {
+		return nil, r.fail(ctx, op, "invalid request", resp.BadRequest("invalid value for field %s", "first"))
+	}
+	page := model.PageRequest{Limit: int(args.First)}
+	if args.After != nil {
+		if page.After, err = pagination.Decode(*args.After); err != nil {
+			return nil, r.fail(ctx, op, "invalid request", resp.BadRequest("invalid value for field %s", "after"))
+		}
+	}
+	// порядок сортировки продолжает курсор, первая страница - по дедлайну
+	if page.After != nil {
+		page.Sort, page.Desc = page.After.Sort, page.After.Desc
+	}
+
+	tasks, err := r.service.ListTasks(ctx, callerID, filter, model.DeadlineWindow{}, page)
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve tasks", err)
+	}
+	out := &connectionResolver{nodes: r.tasks(tasks.Items)}
+	if next := pagination.Encode(tasks.Next); next != "" {
+		out.next = &next
+	}
+	return out, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	const op = "graph.User"
+	userID, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	user, err := loadersFrom(ctx).user(ctx, userID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve user", err)
+	}
+	return &userResolver{r: r, user: user}, nil
+}
+
+func (r *resolver) Project(ctx context.Context, args struct{ ID graphql.ID }) (*projectResolver, error) {
+	const op = "graph.Project"
+	projectID, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	project, err := loadersFrom(ctx).project(ctx, projectID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve project", err)
+	}
+	return &projectResolver{r: r, project: project}, nil
+}
+
+// Mutation
+
+type createTaskInput struct {
+	Title        string
+	Description  string
+	Deadline     *graphql.Time
+	DeadlineExpr *string
+	Priority     *string
+	ProjectID    *graphql.ID
+}
+
+func (r *resolver) CreateTask(ctx context.Context, args struct{ Input createTaskInput }) (*taskResolver, error) {
+	const op = "graph.CreateTask"
+	in := newTask{Title: args.Input.Title, Description: args.Input.Description}
+	if args.Input.Deadline != nil {
+		in.Deadline = args.Input.Deadline.Time
+	}
+	if args.Input.DeadlineExpr != nil {
+		in.DeadlineExpr = *args.Input.DeadlineExpr
+	}
+	if args.Input.Priority != nil {
+		in.Priority = *args.Input.Priority
+	}
+	if err := validate.Struct(in); err != nil {
+		return nil, r.fail(ctx, op, "invalid request", fmt.Errorf("validation error: %w", err))
+	}
+	projectID, err := optionalID(args.Input.ProjectID, "projectId")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+
+	task := model.Task{
+		NameTask:    in.Title,
+		Description: in.Description,
+		Deadline:    in.Deadline,
+		Priority:    in.Priority,
+		ProjectID:   projectID,
+	}
+	if in.DeadlineExpr != "" {
+		task.Deadline, err = r.service.ResolveDeadline(ctx, task.ProjectID, in.DeadlineExpr)
+		if err != nil {
+			return nil, r.fail(ctx, op, "invalid request", err)
+		}
+	}
+	taskID, err := r.service.CreateTask(ctx, task)
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to create task", err)
+	}
+	r.log.Info("task created successfully", slog.String("op", op), slog.Int("task_id", taskID))
+	return r.reloadTask(ctx, op, taskID)
+}
+
+func (r *resolver) UpdateTaskStatus(ctx context.Context, args struct {
+	ID      graphql.ID
+	Status  string
+	Version *int32
+}) (*taskResolver, error) {
+	const op = "graph.UpdateTaskStatus"
+	taskID, err := parseID(args.ID, "id")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	task, err := r.service.TaskUpdateStatus(ctx, args.Status, taskID, version(args.Version))
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed update task status", err)
+	}
+	loadersFrom(ctx).forgetTask(ctx, taskID)
+	return &taskResolver{r: r, task: task}, nil
+}
+
+func (r *resolver) DeleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (graphql.ID, error) {
+	const op = "graph.DeleteTask"
+	taskID, err := parseID(args.ID, "id")
+	if err != nil {
+		return "", r.fail(ctx, op, "invalid request", err)
+	}
+	if err := r.service.DeleteTask(ctx, taskID, version(args.Version)); err != nil {
+		return "", r.fail(ctx, op, "failed to delete task", err)
+	}
+	loadersFrom(ctx).forgetTask(ctx, taskID)
+	r.log.Info("task deleted successfully", slog.String("op", op), slog.Int("task_id", taskID))
+	return args.ID, nil
+}
+
+type assignmentArgs struct {
+	TaskID graphql.ID
+	UserID graphql.ID
+}
+
+func (a assignmentArgs) ids() (taskID int, userID int, err error) {
+	if taskID, err = parseID(a.TaskID, "taskId"); err != nil {
+		return 0, 0, err
+	}
+	if userID, err = parseID(a.UserID, "userId"); err != nil {
+		return 0, 0, err
+	}
+	return taskID, userID, nil
+}
+
+func (r *resolver) AssignUser(ctx context.Context, args assignmentArgs) (*taskResolver, error) {
+	const op = "graph.AssignUser"
+	taskID, userID, err := args.ids()
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	if err := r.service.AddUser(ctx, userID, taskID); err != nil {
+		return nil, r.fail(ctx, op, "failed to add user to task", err)
+	}
+	r.log.Info("user added to task successfully", slog.String("op", op), slog.Int("user_id", userID), slog.Int("task_id", taskID))
+	return r.reloadTask(ctx, op, taskID)
+}
+
+func (r *resolver) UnassignUser(ctx context.Context, args assignmentArgs) (*taskResolver, error) {
+	const op = "graph.UnassignUser"
+	taskID, userID, err := args.ids()
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	if err := r.service.RemoveUserFromTask(ctx, userID, taskID); err != nil {
+		return nil, r.fail(ctx, op, "failed removing the user from the task", err)
+	}
+	return r.reloadTask(ctx, op, taskID)
+}
+
+func (r *resolver) CreateProject(ctx context.Context, args struct {
+	Name      string
+	ManagerID *graphql.ID
+}) (*projectResolver, error) {
+	const op = "graph.CreateProject"
+	if err := validate.Struct(newProject{Name: args.Name}); err != nil {
+		return nil, r.fail(ctx, op, "invalid request", fmt.Errorf("validation error: %w", err))
+	}
+	managerID, err := optionalID(args.ManagerID, "managerId")
+	if err != nil {
+		return nil, r.fail(ctx, op, "invalid request", err)
+	}
+	projectID, err := r.service.CreateProject(ctx, model.Project{Name: args.Name, ManagerID: managerID})
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to create project", err)
+	}
+	r.log.Info("project created successfully", slog.String("op", op), slog.Int("project_id", projectID))
+	project, err := loadersFrom(ctx).project(ctx, projectID)
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve project", err)
+	}
+	return &projectResolver{r: r, project: project}, nil
+}
+
+// reloadTask задача после изменения: загруженная ранее в этом запросе версия устарела
+func (r *resolver) reloadTask(ctx context.Context, op string, taskID int) (*taskResolver, error) {
+	l := loadersFrom(ctx)
+	l.forgetTask(ctx, taskID)
+	task, err := l.task(ctx, taskID)
+	if err != nil {
+		return nil, r.fail(ctx, op, "failed to retrieve task", err)
+	}
+	return &taskResolver{r: r, task: task}, nil
+}
+
+// version версия из аргумента, 0 - без проверки
+func version(v *int32) int {
+	if v == nil {
+		return 0
+	}
+	return int(*v)
+}
+
+func (r *resolver) tasks(tasks []model.Task) []*taskResolver {
+	out := make([]*taskResolver, 0, len(tasks))
+	for _, task := range tasks {
+		out = append(out, &taskResolver{r: r, task: task})
+	}
+	return out
+}
+
+// Типы
+
+type taskResolver struct {
+	r    *resolver
+	task model.Task
+}
+
+func (t *taskResolver) ID() graphql.ID          { return graphql.ID(strconv.Itoa(t.task.ID)) }
+func (t *taskResolver) Title() string           { return t.task.NameTask }
+func (t *taskResolver) Description() string     { return t.task.Description }
+func (t *taskResolver) Status() string          { return t.task.Status }
+func (t *taskResolver) Priority() string        { return t.task.Priority }
+func (t *taskResolver) Deadline() graphql.Time  { return graphql.Time{Time: t.task.Deadline} }
+func (t *taskResolver) CreatedAt() graphql.Time { return graphql.Time{Time: t.task.CreatedAt} }
+func (t *taskResolver) UpdatedAt() graphql.Time { return graphql.Time{Time: t.task.UpdatedAt} }
+func (t *taskResolver) Version() int32          { return int32(t.task.Version) }
+
+func (t *taskResolver) Labels() []string {
+	if t.task.Labels == nil {
+		return []string{}
+	}
+	return t.task.Labels
+}
+
+func (t *taskResolver) Project(ctx context.Context) (*projectResolver, error) {
+	if t.task.ProjectID == 0 {
+		return nil, nil
+	}
+	project, err := loadersFrom(ctx).project(ctx, t.task.ProjectID)
+	if err != nil {
+		return nil, t.r.fail(ctx, "graph.Task.Project", "failed to retrieve project", err)
+	}
+	return &projectResolver{r: t.r, project: project}, nil
+}
+
+func (t *taskResolver) Assignees(ctx context.Context) ([]*userResolver, error) {
+	users, err := loadersFrom(ctx).taskAssignees(ctx, t.task.ID)
+	if err != nil {
+		return nil, t.r.fail(ctx, "graph.Task.Assignees", "failed to retrieve users", err)
+	}
+	out := make([]*userResolver, 0, len(users))
+	for _, user := range users {
+		out = append(out, &userResolver{r: t.r, user: user})
+	}
+	return out, nil
+}
+
+type connectionResolver struct {
+	nodes []*taskResolver
+	next  *string
+}
+
+func (c *connectionResolver) Nodes() []*taskResolver { return c.nodes }
+func (c *connectionResolver) NextCursor() *string    { return c.next }
+
+type userResolver struct {
+	r    *resolver
+	user model.User
+}
+
+func (u *userResolver) ID() graphql.ID   { return graphql.ID(strconv.Itoa(u.user.ID)) }
+func (u *userResolver) Login() string    { return u.user.Login }
+func (u *userResolver) Timezone() string { return u.user.Timezone }
+func (u *userResolver) Language() string { return u.user.Language }
+
+func (u *userResolver) Tasks(ctx context.Context, args struct{ First int32 }) ([]*taskResolver, error) {
+	const op = "graph.User.Tasks"
+	tasks, err := loadersFrom(ctx).tasksOf(ctx, u.user.ID, int(args.First))
+	if err != nil {
+		return nil, u.r.fail(ctx, op, "failed to retrieve tasks", err)
+	}
+	return u.r.tasks(tasks), nil
+}
+
+type projectResolver struct {
+	r       *resolver
+	project model.Project
+}
+
+func (p *projectResolver) ID() graphql.ID          { return graphql.ID(strconv.Itoa(p.project.ID)) }
+func (p *projectResolver) Name() string            { return p.project.Name }
+func (p *projectResolver) CreatedAt() graphql.Time { return graphql.Time{Time: p.project.CreatedAt} }
+
+func (p *projectResolver) Manager(ctx context.Context) (*userResolver, error) {
+	if p.project.ManagerID == 0 {
+		return nil, nil
+	}
+	user, err := loadersFrom(ctx).user(ctx, p.project.ManagerID)
+	if err != nil {
+		return nil, p.r.fail(ctx, "graph.Project.Manager", "failed to retrieve user", err)
+	}
+	return &userResolver{r: p.r, user: user}, nil
+}
@@ -0,0 +1,94 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// publicMethods префиксы методов, доступных без токена: проверка состояния и reflection
+var publicMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
+// UnaryAuth аутентифицирует вызов по метаданным authorization: Bearer <JWT>, как auth.New для HTTP.
+// В отличие от HTTP, анонимные вызовы отклоняются: все методы API требуют пользователя.
+// Язык ошибок выбирается по метаданным accept-language, без них - по профилю пользователя.
+func UnaryAuth(log *slog.Logger, secret string, profile auth.ProfileLanguage) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, log, info.FullMethod, secret, profile)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuth то же, что UnaryAuth, для потоковых методов
+func StreamAuth(log *slog.Logger, secret string, profile auth.ProfileLanguage) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), log, info.FullMethod, secret, profile)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, log *slog.Logger, method string, secret string,
+	profile auth.ProfileLanguage) (context.Context, error) {
+	for _, prefix := range publicMethods {
+		if strings.HasPrefix(method, prefix) {
+			return ctx, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	lang, chosen := i18n.Match(first(md, "accept-language"))
+	if chosen {
+		ctx = i18n.WithLanguage(ctx, lang)
+	}
+	authorization := first(md, "authorization")
+	if authorization == "" {
+		return ctx, statusError(ctx, model.Unauthorized("authentication required"))
+	}
+	userID, err := auth.Authenticate(authorization, secret)
+	if err != nil {
+		log.Info("rejected call with invalid token", slog.String("method", method), sl.Err(err))
+		return ctx, statusError(ctx, model.Unauthorized("invalid or expired token"))
+	}
+
+	ctx = auth.WithUserID(ctx, userID)
+	if !chosen && profile != nil {
+		if lang := profile(ctx, userID); i18n.Supported(lang) {
+			ctx = i18n.WithLanguage(ctx, lang)
+		}
+	}
+	return ctx, nil
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// serverStream подменяет контекст потока контекстом с пользователем
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
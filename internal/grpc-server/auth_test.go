@@ -0,0 +1,98 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+)
+
+func TestUnaryAuth(t *testing.T) {
+	const secret = "secret"
+	token, err := jwt.NewToken(model.User{ID: 7, Login: "user"}, secret, time.Hour)
+	require.NoError(t, err)
+	expired, err := jwt.NewToken(model.User{ID: 7, Login: "user"}, secret, -time.Hour)
+	require.NoError(t, err)
+
+	profile := func(_ context.Context, userID int) string {
+		require.Equal(t, 7, userID)
+		return "ru"
+	}
+	interceptor := UnaryAuth(slogdiscard.NewDiscardLogger(), secret, profile)
+
+	tests := []struct {
+		name     string
+		method   string
+		metadata []string
+		code     codes.Code
+		userID   int
+		lang     string
+	}{
+		{name: "valid token", method: "/tasks.v1.TaskService/GetTask", metadata: []string{"authorization", "Bearer " + token},
+			code: codes.OK, userID: 7, lang: "ru"},
+		{name: "accept-language wins over profile", method: "/tasks.v1.TaskService/GetTask",
+			metadata: []string{"authorization", "Bearer " + token, "accept-language", "en"}, code: codes.OK, userID: 7, lang: "en"},
+		{name: "anonymous", method: "/tasks.v1.TaskService/GetTask", code: codes.Unauthenticated},
+		{name: "expired token", method: "/tasks.v1.TaskService/GetTask", metadata: []string{"authorization", "Bearer " + expired},
+			code: codes.Unauthenticated},
+		{name: "not a bearer token", method: "/tasks.v1.TaskService/GetTask", metadata: []string{"authorization", "Basic dXNlcjpwYXNz"},
+			code: codes.Unauthenticated},
+		{name: "anonymous health check", method: "/grpc.health.v1.Health/Check", code: codes.OK},
+		{name: "anonymous reflection", method: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo", code: codes.OK},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tt.metadata...))
+			var called bool
+			handler := func(ctx context.Context, _ any) (any, error) {
+				called = true
+				userID, ok := auth.UserID(ctx)
+				require.Equal(t, tt.userID != 0, ok)
+				require.Equal(t, tt.userID, userID)
+				if tt.lang != "" {
+					require.Equal(t, tt.lang, i18n.FromContext(ctx))
+				}
+				return nil, nil
+			}
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			require.Equal(t, tt.code, status.Code(err))
+			require.Equal(t, tt.code == codes.OK, called)
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+	}{
+		{name: "not found", err: model.NotFound("task with ID %d not found", 1), code: codes.NotFound},
+		{name: "invalid", err: model.Invalid("unknown task status %q", "archived"), code: codes.InvalidArgument},
+		{name: "conflict", err: model.Conflict("user %d is already assigned to task %d", 1, 2), code: codes.AlreadyExists},
+		{name: "forbidden", err: model.Forbidden("access denied"), code: codes.PermissionDenied},
+		{name: "version mismatch", err: model.PreconditionFailed("task %d has been modified", 1), code: codes.Aborted},
+		{name: "internal", err: context.DeadlineExceeded, code: codes.Internal},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(statusError(context.Background(), tt.err))
+			require.Equal(t, tt.code, st.Code())
+			require.NotEmpty(t, st.Message())
+			require.NotEmpty(t, st.Details())
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+)
+
+// errorDomain домен ErrorInfo в деталях ошибок
+const errorDomain = "tasks"
+
+// statusError переводит ошибку в статус gRPC с сообщением на языке вызова. Причина ErrorInfo - код
+// ошибки HTTP API (not_found, conflict, ...), ошибки полей передаются в BadRequest.
+func statusError(ctx context.Context, err error) error {
+	httpStatus, body := resp.FromError(err, i18n.FromContext(ctx))
+	st := status.New(grpcCode(httpStatus), body.Error)
+
+	details := []protoadapt.MessageV1{&errdetails.ErrorInfo{Reason: body.Code, Domain: errorDomain}}
+	if len(body.Errors) > 0 {
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(body.Errors))
+		for _, fe := range body.Errors {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{Field: fe.Field, Description: fe.Message})
+		}
+		details = append(details, &errdetails.BadRequest{FieldViolations: violations})
+	}
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// logError пишет ошибку в лог: внутренние - как ошибки, ошибки клиента - как информацию
+func logError(log *slog.Logger, msg string, err error) {
+	if httpStatus, _ := resp.FromError(err, i18n.Default); httpStatus >= http.StatusInternalServerError {
+		log.Error(msg, sl.Err(err))
+		return
+	}
+	log.Info(msg, sl.Err(err))
+}
+
+// grpcCode код gRPC, соответствующий статусу ответа HTTP API
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusPreconditionFailed:
+		// версия задачи изменилась: клиент перечитывает задачу и повторяет вызов
+		return codes.Aborted
+	}
+	return codes.Internal
+}
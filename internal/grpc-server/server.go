@@ -0,0 +1,83 @@
+// Package grpcserver gRPC API задач и исполнителей. Работает поверх того же сервиса, что и HTTP API,
+// и принимает те же токены доступа.
+package grpcserver
+
+//go:generate protoc -I ../../proto --go_out=gen --go_opt=paths=source_relative --go-grpc_out=gen --go-grpc_opt=paths=source_relative tasks/v1/tasks.proto
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	tasksv1 "Tasks/internal/grpc-server/gen/tasks/v1"
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/service"
+)
+
+type Server struct {
+	address string
+	log     *slog.Logger
+	server  *grpc.Server
+	health  *health.Server
+}
+
+// New регистрирует TaskService, проверку состояния grpc.health.v1 и reflection.
+// secret - ключ подписи токенов доступа, тот же, что у HTTP API.
+func New(log *slog.Logger, address string, secret string, svc *service.Service) *Server {
+	log = log.With(slog.String("component", "grpc-server"))
+	profile := auth.ProfileLanguage(svc.UserLanguage)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryAuth(log, secret, profile)),
+		grpc.ChainStreamInterceptor(StreamAuth(log, secret, profile)),
+	)
+	tasksv1.RegisterTaskServiceServer(server, &taskServer{service: svc, log: log})
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(tasksv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	return &Server{address: address, log: log, server: server, health: healthServer}
+}
+
+// Run слушает адрес сервера и обслуживает вызовы до остановки
+func (s *Server) Run() error {
+	lis, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.log.Info("starting grpc server", slog.String("address", s.address))
+	return s.Serve(lis)
+}
+
+func (s *Server) Serve(lis net.Listener) error {
+	if err := s.server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown переводит проверку состояния в NOT_SERVING, перестаёт принимать вызовы и дожидается
+// начатых. Если ctx завершился раньше, оставшиеся вызовы прерываются.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		return ctx.Err()
+	}
+}
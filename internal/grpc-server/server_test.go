@@ -0,0 +1,123 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	tasksv1 "Tasks/internal/grpc-server/gen/tasks/v1"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+	mockery "Tasks/internal/service/mocks"
+)
+
+const testSecret = "secret"
+
+// startServer запускает сервер в памяти и возвращает подключение к нему
+func startServer(t *testing.T, svc *service.Service) (*Server, *grpc.ClientConn) {
+	t.Helper()
+	server := New(slogdiscard.NewDiscardLogger(), "", testSecret, svc)
+	lis := bufconn.Listen(1 << 20)
+	go func() {
+		_ = server.Serve(lis)
+	}()
+	t.Cleanup(func() {
+		_ = server.Shutdown(context.Background())
+	})
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return server, conn
+}
+
+func withToken(t *testing.T, userID int, kv ...string) context.Context {
+	token, err := jwt.NewToken(model.User{ID: userID, Login: "user"}, testSecret, time.Hour)
+	require.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), append([]string{"authorization", "Bearer " + token}, kv...)...)
+}
+
+func TestServer_GetTask(t *testing.T) {
+	deadline := time.Date(2030, 1, 15, 10, 0, 0, 0, time.UTC)
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("UserLanguage", mock.Anything, 7).Return("en", nil)
+	storageMock.On("TaskByID", mock.Anything, 404).Return(model.Task{}, model.NotFound("task with ID %d not found", 404)).Once()
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("GetTaskFromCache", mock.Anything, 1).
+		Return(model.Task{ID: 1, NameTask: "task", Status: model.TaskStatusTodo, Deadline: deadline, Version: 3}, nil).Once()
+	cacheMock.On("GetTaskFromCache", mock.Anything, 404).Return(model.Task{}, redis.Nil).Once()
+
+	_, conn := startServer(t, service.NewService(slogdiscard.NewDiscardLogger(), storageMock, cacheMock, nil, nil))
+	client := tasksv1.NewTaskServiceClient(conn)
+
+	task, err := client.GetTask(withToken(t, 7), &tasksv1.GetTaskRequest{TaskId: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), task.GetId())
+	require.Equal(t, "task", task.GetTitle())
+	require.Equal(t, int32(3), task.GetVersion())
+	require.True(t, deadline.Equal(task.GetDeadline().AsTime()))
+	require.Nil(t, task.GetCreatedAt())
+
+	_, err = client.GetTask(withToken(t, 7, "accept-language", "ru"), &tasksv1.GetTaskRequest{TaskId: 404})
+	st := status.Convert(err)
+	require.Equal(t, codes.NotFound, st.Code())
+	require.Equal(t, "задача с ID 404 не найдена", st.Message())
+	require.Len(t, st.Details(), 1)
+	require.Equal(t, "not_found", st.Details()[0].(*errdetails.ErrorInfo).GetReason())
+
+	_, err = client.GetTask(context.Background(), &tasksv1.GetTaskRequest{TaskId: 1})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestServer_CreateTaskValidation(t *testing.T) {
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("UserLanguage", mock.Anything, 7).Return("en", nil)
+	_, conn := startServer(t, service.NewService(slogdiscard.NewDiscardLogger(), storageMock, mockery.NewCacheRepository(t), nil, nil))
+
+	_, err := tasksv1.NewTaskServiceClient(conn).CreateTask(withToken(t, 7), &tasksv1.CreateTaskRequest{Description: "no title"})
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 2)
+	violations := st.Details()[1].(*errdetails.BadRequest).GetFieldViolations()
+	require.Len(t, violations, 2)
+	require.Equal(t, "title", violations[0].GetField())
+	require.Equal(t, "deadline", violations[1].GetField())
+}
+
+func TestServer_Health(t *testing.T) {
+	server, conn := startServer(t, &service.Service{})
+	client := healthpb.NewHealthClient(conn)
+
+	for _, name := range []string{"", tasksv1.TaskService_ServiceDesc.ServiceName} {
+		res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: name})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+	}
+
+	// проверка состояния не требует токена; после остановки сервер отклоняет вызовы
+	require.NoError(t, server.Shutdown(context.Background()))
+	_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.Equal(t, codes.Unavailable, status.Code(err))
+}
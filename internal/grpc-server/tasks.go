@@ -0,0 +1,219 @@
+package grpcserver
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	tasksv1 "Tasks/internal/grpc-server/gen/tasks/v1"
+	"Tasks/internal/http-server/middleware/auth"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/lib/validation"
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+)
+
+// validate правила те же, что у запросов HTTP API; поля в ошибках называются как в proto
+var validate = validation.New()
+
+// newTask проверяемые поля CreateTaskRequest
+type newTask struct {
+	Title        string    `json:"title" validate:"required,max=255"`
+	Description  string    `json:"description" validate:"required"`
+	Deadline     time.Time `json:"deadline" validate:"required_without=DeadlineExpr,future"`
+	DeadlineExpr string    `json:"deadline_expr" validate:"excluded_with=Deadline"`
+	Priority     string    `json:"priority" validate:"priority"`
+}
+
+type taskServer struct {
+	tasksv1.UnimplementedTaskServiceServer
+	service *service.Service
+	log     *slog.Logger
+}
+
+func (s *taskServer) CreateTask(ctx context.Context, req *tasksv1.CreateTaskRequest) (*tasksv1.CreateTaskResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.CreateTask"))
+	in := newTask{
+		Title:        req.GetTitle(),
+		Description:  req.GetDescription(),
+		DeadlineExpr: req.GetDeadlineExpr(),
+		Priority:     req.GetPriority(),
+	}
+	if req.GetDeadline() != nil {
+		in.Deadline = req.GetDeadline().AsTime()
+	}
+	if err := validate.Struct(in); err != nil {
+		return nil, s.fail(ctx, log, "invalid request", fmt.Errorf("validation error: %w", err))
+	}
+
+	task := model.Task{
+		NameTask:    in.Title,
+		Description: in.Description,
+		Deadline:    in.Deadline,
+		Priority:    in.Priority,
+		ProjectID:   int(req.GetProjectId()),
+	}
+	if in.DeadlineExpr != "" {
+		var err error
+		task.Deadline, err = s.service.ResolveDeadline(ctx, task.ProjectID, in.DeadlineExpr)
+		if err != nil {
+			return nil, s.fail(ctx, log, "invalid request", err)
+		}
+	}
+	taskID, err := s.service.CreateTask(ctx, task)
+	if err != nil {
+		return nil, s.fail(ctx, log, "failed to create task", err)
+	}
+	log.Info("task created successfully", slog.Int("task_id", taskID))
+	return &tasksv1.CreateTaskResponse{TaskId: int64(taskID)}, nil
+}
+
+func (s *taskServer) GetTask(ctx context.Context, req *tasksv1.GetTaskRequest) (*tasksv1.Task, error) {
+	log := s.log.With(slog.String("op", "grpc.GetTask"))
+	task, err := s.service.TaskByID(ctx, int(req.GetTaskId()))
+	if err != nil {
+		return nil, s.fail(ctx, log, "failed to retrieve task", err)
+	}
+	return taskMessage(task), nil
+}
+
+func (s *taskServer) UpdateTaskStatus(ctx context.Context, req *tasksv1.UpdateTaskStatusRequest) (*tasksv1.Task, error) {
+	log := s.log.With(slog.String("op", "grpc.UpdateTaskStatus"))
+	task, err := s.service.TaskUpdateStatus(ctx, req.GetStatus(), int(req.GetTaskId()), int(req.GetVersion()))
+	if err != nil {
+		return nil, s.fail(ctx, log, "failed update task status", err)
+	}
+	return taskMessage(task), nil
+}
+
+func (s *taskServer) DeleteTask(ctx context.Context, req *tasksv1.DeleteTaskRequest) (*tasksv1.DeleteTaskResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.DeleteTask"))
+	if err := s.service.DeleteTask(ctx, int(req.GetTaskId()), int(req.GetVersion())); err != nil {
+		return nil, s.fail(ctx, log, "failed to delete task", err)
+	}
+	log.Info("task deleted successfully", slog.Int64("task_id", req.GetTaskId()))
+	return &tasksv1.DeleteTaskResponse{}, nil
+}
+
+// ListUserTasks user_id = 0 - задачи вызывающего пользователя
+func (s *taskServer) ListUserTasks(ctx context.Context, req *tasksv1.ListUserTasksRequest) (*tasksv1.ListTasksResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.ListUserTasks"))
+	callerID, _ := auth.UserID(ctx)
+	page, err := pageRequest(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, s.fail(ctx, log, "invalid request", err)
+	}
+	// порядок сортировки продолжает курсор, первая страница - по дедлайну
+	if page.After != nil {
+		page.Sort, page.Desc = page.After.Sort, page.After.Desc
+	}
+	filter := model.TaskFilter{UserID: int(req.GetUserId()), Statuses: req.GetStatuses()}
+	if filter.UserID == 0 {
+		filter.UserID = callerID
+	}
+	tasks, err := s.service.ListTasks(ctx, callerID, filter, model.DeadlineWindow{}, page)
+	if err != nil {
+		return nil, s.fail(ctx, log, "failed to retrieve tasks", err)
+	}
+	out := &tasksv1.ListTasksResponse{
+		Tasks:         make([]*tasksv1.Task, 0, len(tasks.Items)),
+		NextPageToken: pagination.Encode(tasks.Next),
+	}
+	for _, task := range tasks.Items {
+		out.Tasks = append(out.Tasks, taskMessage(task))
+	}
+	return out, nil
+}
+
+func (s *taskServer) ListAssignees(ctx context.Context, req *tasksv1.ListAssigneesRequest) (*tasksv1.ListAssigneesResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.ListAssignees"))
+	page, err := pageRequest(req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, s.fail(ctx, log, "invalid request", err)
+	}
+	users, err := s.service.AllUsersWorkTask(ctx, int(req.GetTaskId()), page)
+	if err != nil {
+		return nil, s.fail(ctx, log, "failed to retrieve users", err)
+	}
+	out := &tasksv1.ListAssigneesResponse{
+		Users:         make([]*tasksv1.User, 0, len(users.Items)),
+		NextPageToken: pagination.Encode(users.Next),
+	}
+	for _, user := range users.Items {
+		out.Users = append(out.Users, &tasksv1.User{
+			Id:          int64(user.ID),
+			Login:       user.Login,
+			AccessLevel: int32(user.Level),
+			Timezone:    user.Timezone,
+			Language:    user.Language,
+		})
+	}
+	return out, nil
+}
+
+func (s *taskServer) AssignUser(ctx context.Context, req *tasksv1.AssignUserRequest) (*tasksv1.AssignUserResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.AssignUser"))
+	if err := s.service.AddUser(ctx, int(req.GetUserId()), int(req.GetTaskId())); err != nil {
+		return nil, s.fail(ctx, log, "failed to add user to task", err)
+	}
+	log.Info("user added to task successfully", slog.Int64("user_id", req.GetUserId()), slog.Int64("task_id", req.GetTaskId()))
+	return &tasksv1.AssignUserResponse{}, nil
+}
+
+func (s *taskServer) UnassignUser(ctx context.Context, req *tasksv1.UnassignUserRequest) (*tasksv1.UnassignUserResponse, error) {
+	log := s.log.With(slog.String("op", "grpc.UnassignUser"))
+	if err := s.service.RemoveUserFromTask(ctx, int(req.GetUserId()), int(req.GetTaskId())); err != nil {
+		return nil, s.fail(ctx, log, "failed removing the user from the task", err)
+	}
+	return &tasksv1.UnassignUserResponse{}, nil
+}
+
+// fail пишет ошибку в лог и возвращает её статусом gRPC
+func (s *taskServer) fail(ctx context.Context, log *slog.Logger, msg string, err error) error {
+	logError(log, msg, err)
+	return statusError(ctx, err)
+}
+
+// pageRequest страница по page_size и page_token, как параметры limit и cursor HTTP API
+func pageRequest(size int32, token string) (model.PageRequest, error) {
+	page := model.PageRequest{Limit: model.DefaultPageLimit}
+	if size < 0 || size > model.MaxPageLimit {
+		return page, resp.BadRequest("invalid value for field %s", "page_size")
+	}
+	if size > 0 {
+		page.Limit = int(size)
+	}
+	after, err := pagination.Decode(token)
+	if err != nil {
+		return page, resp.BadRequest("invalid value for field %s", "page_token")
+	}
+	page.After = after
+	return page, nil
+}
+
+func taskMessage(task model.Task) *tasksv1.Task {
+	return &tasksv1.Task{
+		Id:          int64(task.ID),
+		Title:       task.NameTask,
+		Description: task.Description,
+		Status:      task.Status,
+		Priority:    task.Priority,
+		ProjectId:   int64(task.ProjectID),
+		Deadline:    timestamp(task.Deadline),
+		CreatedAt:   timestamp(task.CreatedAt),
+		UpdatedAt:   timestamp(task.UpdatedAt),
+		Version:     int32(task.Version),
+		Labels:      task.Labels,
+	}
+}
+
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
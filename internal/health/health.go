@@ -0,0 +1,140 @@
+// Package health проверки живости и готовности приложения для Docker Compose и оркестраторов.
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/render"
+)
+
+// Статусы проверок
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Check проверка одной зависимости; должна вернуться, когда ctx завершён
+type Check func(ctx context.Context) error
+
+// errShuttingDown готовность после начала остановки
+var errShuttingDown = errors.New("server is shutting down")
+
+// DependencyStatus результат проверки зависимости
+type DependencyStatus struct {
+	Status string `json:"status"`
+	// LatencyMS время проверки в миллисекундах
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report ответ /readyz: общий статус и статус каждой зависимости
+type Report struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies,omitempty"`
+	Error        string                      `json:"error,omitempty"`
+}
+
+type named struct {
+	name  string
+	check Check
+}
+
+// Checker проверяет зависимости параллельно, каждую не дольше timeout.
+// После Shutdown приложение считается неготовым, зависимости больше не проверяются.
+type Checker struct {
+	log      *slog.Logger
+	timeout  time.Duration
+	checks   []named
+	shutdown atomic.Bool
+}
+
+func New(log *slog.Logger, timeout time.Duration) *Checker {
+	return &Checker{
+		log:     log.With(slog.String("component", "health")),
+		timeout: timeout,
+	}
+}
+
+// Add регистрирует проверку зависимости name; вызывается до начала обслуживания запросов
+func (c *Checker) Add(name string, check Check) {
+	c.checks = append(c.checks, named{name: name, check: check})
+}
+
+// Shutdown переводит готовность в отказ: балансировщик перестаёт направлять новые запросы
+func (c *Checker) Shutdown() {
+	c.shutdown.Store(true)
+}
+
+// Ready проверяет все зависимости
+func (c *Checker) Ready(ctx context.Context) Report {
+	if c.shutdown.Load() {
+		return Report{Status: StatusUnavailable, Error: errShuttingDown.Error()}
+	}
+
+	report := Report{Status: StatusOK, Dependencies: make(map[string]DependencyStatus, len(c.checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, nc := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := c.run(ctx, nc.check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[nc.name] = status
+			if status.Status != StatusOK {
+				report.Status = StatusUnavailable
+				c.log.Warn("dependency is unavailable", slog.String("dependency", nc.name), slog.String("error", status.Error))
+			}
+		}()
+	}
+	wg.Wait()
+	return report
+}
+
+// run выполняет проверку с таймаутом. Проверка, которая не реагирует на ctx, не задерживает ответ:
+// её результат после таймаута отбрасывается.
+func (c *Checker) run(ctx context.Context, check Check) DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	status := DependencyStatus{Status: StatusOK, LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		status.Status = StatusUnavailable
+		status.Error = err.Error()
+	}
+	return status
+}
+
+// LiveHandler отвечает, пока процесс обслуживает запросы; зависимости не проверяются,
+// чтобы сбой базы не приводил к перезапуску приложения
+func (c *Checker) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, Report{Status: StatusOK})
+}
+
+// ReadyHandler статус зависимостей; 503, если хотя бы одна недоступна или сервер останавливается
+func (c *Checker) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	report := c.Ready(r.Context())
+	if report.Status != StatusOK {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+	render.JSON(w, r, report)
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+)
+
+func ok(context.Context) error { return nil }
+
+func TestChecker_ReadyHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		checks   map[string]Check
+		shutdown bool
+		wantCode int
+		want     map[string]string
+	}{
+		{
+			name:     "all available",
+			checks:   map[string]Check{"postgres": ok, "redis": ok},
+			wantCode: http.StatusOK,
+			want:     map[string]string{"postgres": StatusOK, "redis": StatusOK},
+		},
+		{
+			name: "dependency fails",
+			checks: map[string]Check{"postgres": ok, "kafka": func(context.Context) error {
+				return errors.New("no brokers")
+			}},
+			wantCode: http.StatusServiceUnavailable,
+			want:     map[string]string{"postgres": StatusOK, "kafka": StatusUnavailable},
+		},
+		{
+			// проверка, которая не реагирует на ctx, не задерживает ответ дольше таймаута
+			name: "dependency hangs",
+			checks: map[string]Check{"redis": func(context.Context) error {
+				time.Sleep(300 * time.Millisecond)
+				return nil
+			}},
+			wantCode: http.StatusServiceUnavailable,
+			want:     map[string]string{"redis": StatusUnavailable},
+		},
+		{
+			name:     "shutting down",
+			checks:   map[string]Check{"postgres": ok},
+			shutdown: true,
+			wantCode: http.StatusServiceUnavailable,
+			want:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(slogdiscard.NewDiscardLogger(), 50*time.Millisecond)
+			for name, check := range tt.checks {
+				c.Add(name, check)
+			}
+			if tt.shutdown {
+				c.Shutdown()
+			}
+
+			start := time.Now()
+			rr := httptest.NewRecorder()
+			c.ReadyHandler(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			require.Less(t, time.Since(start), 250*time.Millisecond)
+			require.Equal(t, tt.wantCode, rr.Code)
+
+			var report Report
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &report))
+			got := make(map[string]string, len(report.Dependencies))
+			for name, dep := range report.Dependencies {
+				got[name] = dep.Status
+				if dep.Status != StatusOK {
+					require.NotEmpty(t, dep.Error)
+				}
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestChecker_LiveHandler(t *testing.T) {
+	c := New(slogdiscard.NewDiscardLogger(), time.Second)
+	c.Add("postgres", func(context.Context) error { return errors.New("down") })
+	c.Shutdown()
+
+	// живость не зависит ни от зависимостей, ни от остановки
+	rr := httptest.NewRecorder()
+	c.LiveHandler(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.JSONEq(t, `{"status":"ok"}`, rr.Body.String())
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+
+// RequestBatch одно изменение для группы задач. Labels заменяет метки задач, пустой список снимает все метки.
+// Delete не сочетается с другими изменениями.
+type RequestBatch struct {
+	TaskIDs         []int     `json:"task_ids" validate:"required,min=1,max=100,dive,min=1"`
+	Status          string    `json:"status" validate:"omitempty,task_status"`
+	AddAssignees    []int     `json:"add_assignees" validate:"max=50,dive,min=1"`
+	RemoveAssignees []int     `json:"remove_assignees" validate:"max=50,dive,min=1"`
+	Labels          *[]string `json:"labels" validate:"omitempty,max=20,dive,required,max=50"`
+	Delete          bool      `json:"delete"`
+}
+
+func (req RequestBatch) batch() model.TaskBatch {
+	batch := model.TaskBatch{
+		TaskIDs:         req.TaskIDs,
+		Status:          req.Status,
+		AddAssignees:    req.AddAssignees,
+		RemoveAssignees: req.RemoveAssignees,
+		Delete:          req.Delete,
+	}
+	if req.Labels != nil {
+		batch.Labels = append([]string{}, *req.Labels...)
+	}
+	return batch
+}
+
+// Ответы
+
+// BatchResult результат для одной задачи: status OK или ERROR с кодом и текстом ошибки, как в ответах API.
+// Version - новая версия задачи, если изменилась сама задача.
+type BatchResult struct {
+	TaskID  int    `json:"task_id"`
+	Status  string `json:"status"`
+	Version int    `json:"version,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+type ResponseBatch struct {
+	Results []BatchResult `json:"results"`
+	resp.Response
+}
+
+// Обработчики
+
+// BatchTasksV1 Applies one change to a list of tasks in a single transaction. A failed task does not
+// roll back the others, its error is reported in its own result.
+func (h *Handler) BatchTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.BatchTasksV1"
+	log := h.log.With(slog.String("op", op))
+	req, err := decodeAndValidate[RequestBatch](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	results, err := h.service.BatchTasks(r.Context(), req.batch())
+	if err != nil {
+		errorHandler(log, "failed to apply batch", err, w, r)
+		return
+	}
+
+	lang := i18n.FromContext(r.Context())
+	out := make([]BatchResult, 0, len(results))
+	failed := 0
+	for _, res := range results {
+		item := BatchResult{TaskID: res.TaskID, Status: resp.StatusOK, Version: res.Task.Version}
+		if res.Err != nil {
+			_, body := resp.FromError(res.Err, lang)
+			item = BatchResult{TaskID: res.TaskID, Status: resp.StatusError, Code: body.Code, Error: body.Error}
+			failed++
+		}
+		out = append(out, item)
+	}
+	log.Info("batch applied", slog.Int("tasks", len(results)), slog.Int("failed", failed))
+	render.JSON(w, r, ResponseBatch{
+		Results:  out,
+		Response: resp.OK(),
+	})
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/websocket"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/logger/sl"
+)
+
+// upgrader принимает соединения с любых источников: доска аутентифицирует по токену,
+// а не по cookie, поэтому чужая страница не может воспользоваться сессией пользователя
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     func(*http.Request) bool { return true },
+}
+
+// Обработчики
+
+// BoardV1 WebSocket channel of a project board: task change events, presence and typing indicators.
+// The token is taken from the Authorization header or from the first auth message.
+func (h *Handler) BoardV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.BoardV1"
+	log := h.log.With(slog.String("op", op))
+	projectID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		if _, err := callerID(r); err != nil {
+			errorHandler(log, "failed to open board", err, w, r)
+			return
+		}
+		errorHandler(log, invalid, resp.BadRequest("websocket upgrade required"), w, r)
+		return
+	}
+
+	// заголовок уже проверен middleware auth, здесь нужен сам токен: доска следит за сроком его действия
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	ws, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade сам отвечает клиенту ошибкой
+		log.Info("failed to upgrade connection", sl.Err(err))
+		return
+	}
+	h.board.Serve(r.Context(), ws, projectID, strings.TrimSpace(token))
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+
+// RequestNewCalendar WorkStart и WorkEnd в формате "09:00", Weekends - номера дней недели (0 - воскресенье).
+// Незаполненные поля принимают значения по умолчанию: UTC, 09:00-18:00, суббота и воскресенье.
+type RequestNewCalendar struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	Timezone  string `json:"timezone" validate:"max=64"`
+	WorkStart string `json:"work_start"`
+	WorkEnd   string `json:"work_end"`
+	Weekends  []int  `json:"weekends" validate:"omitempty,dive,min=0,max=6"`
+}
+
+// RequestImportHolidays ICal - содержимое файла iCalendar (.ics)
+type RequestImportHolidays struct {
+	CalendarID int    `json:"calendar_id" validate:"required"`
+	ICal       string `json:"ical" validate:"required"`
+}
+
+type RequestCalendarID struct {
+	CalendarID int `json:"calendar_id" validate:"required"`
+}
+
+// RequestProjectCalendar CalendarID = 0 снимает календарь с проекта
+type RequestProjectCalendar struct {
+	ProjectID  int `json:"project_id" validate:"required"`
+	CalendarID int `json:"calendar_id"`
+}
+
+// RequestUserCalendar CalendarID = 0 снимает календарь с пользователя
+type RequestUserCalendar struct {
+	UserID     int `json:"user_id" validate:"required"`
+	CalendarID int `json:"calendar_id"`
+}
+
+// Ответы
+type ResponseNewCalendar struct {
+	resp.Response
+	CalendarID int `json:"calendar_id"`
+}
+
+type ResponseImportHolidays struct {
+	resp.Response
+	Imported int `json:"imported"`
+}
+
+type ResponseCalendar struct {
+	Calendar model.Calendar `json:"calendar"`
+	resp.Response
+}
+
+// Обработчики
+func (h *Handler) CreateCalendar(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateCalendar"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewCalendar](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	calendarID, err := h.service.CreateCalendar(ctx, model.Calendar{
+		Name:      req.Name,
+		Timezone:  req.Timezone,
+		WorkStart: req.WorkStart,
+		WorkEnd:   req.WorkEnd,
+		Weekends:  req.Weekends,
+	})
+	if err != nil {
+		errorHandler(log, "failed to create calendar", err, w, r)
+		return
+	}
+	log.Info("calendar created successfully", slog.Int("calendar_id", calendarID))
+	render.JSON(w, r, ResponseNewCalendar{
+		Response:   resp.OK(),
+		CalendarID: calendarID,
+	})
+}
+
+// ImportHolidays Imports holidays into the calendar from an iCalendar file
+func (h *Handler) ImportHolidays(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ImportHolidays"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestImportHolidays](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	imported, err := h.service.ImportHolidays(ctx, req.CalendarID, strings.NewReader(req.ICal))
+	if err != nil {
+		errorHandler(log, "failed to import holidays", err, w, r)
+		return
+	}
+	log.Info("holidays imported successfully", slog.Int("calendar_id", req.CalendarID), slog.Int("imported", imported))
+	render.JSON(w, r, ResponseImportHolidays{
+		Response: resp.OK(),
+		Imported: imported,
+	})
+}
+
+func (h *Handler) GetCalendar(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetCalendar"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestCalendarID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	calendar, err := h.service.CalendarByID(ctx, req.CalendarID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve calendar", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseCalendar{
+		Calendar: calendar,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetProjectCalendar(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetProjectCalendar"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestProjectCalendar](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetProjectCalendar(ctx, req.ProjectID, req.CalendarID); err != nil {
+		errorHandler(log, "failed to assign calendar to project", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetUserCalendar(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetUserCalendar"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestUserCalendar](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetUserCalendar(ctx, req.UserID, req.CalendarID); err != nil {
+		errorHandler(log, "failed to assign calendar to user", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
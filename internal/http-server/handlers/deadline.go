@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	"Tasks/internal/http-server/middleware/auth"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/model"
+)
+
+// RequestDeadlineWindow окно дедлайнов: именованное (overdue, today, tomorrow, this_week, next_n_days)
+// или произвольный диапазон from-to. Days используется только для next_n_days.
+type RequestDeadlineWindow struct {
+	Window string     `json:"window" validate:"omitempty,oneof=overdue today tomorrow this_week next_n_days"`
+	Days   int        `json:"days" validate:"omitempty,min=1"`
+	From   *time.Time `json:"from"`
+	To     *time.Time `json:"to"`
+}
+
+// Поступающие запросы
+type RequestUserDeadline struct {
+	UserID int `json:"user_id" validate:"required"`
+	RequestDeadlineWindow
+}
+
+// RequestProjectTasks UserID - вызывающий пользователь, в его часовом поясе вычисляется окно
+type RequestProjectTasks struct {
+	ProjectID int `json:"project_id" validate:"required"`
+	UserID    int `json:"user_id"`
+	RequestDeadlineWindow
+}
+
+type RequestTeamTasks struct {
+	TeamID int `json:"team_id" validate:"required"`
+	UserID int `json:"user_id"`
+	RequestDeadlineWindow
+}
+
+type RequestNewTeam struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type RequestTeamMember struct {
+	TeamID int `json:"team_id" validate:"required"`
+	UserID int `json:"user_id" validate:"required"`
+}
+
+type RequestUserTimezone struct {
+	UserID   int    `json:"user_id" validate:"required"`
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
+
+// Ответы
+type ResponseNewTeam struct {
+	resp.Response
+	TeamID int `json:"team_id"`
+}
+
+// Обработчики
+
+// DeadlineTasks Returns the user's tasks whose deadline falls into the window, evaluated in the user's timezone
+func (h *Handler) DeadlineTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeadlineTasks"
+	log := h.log.With(slog.String("op", op))
+	req, err := decodeAndValidate[RequestUserDeadline](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, req.UserID, model.TaskFilter{UserID: req.UserID}, req.RequestDeadlineWindow, model.PageRequest{})
+}
+
+// ProjectTasks Returns the project's tasks, optionally filtered by a deadline window
+func (h *Handler) ProjectTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ProjectTasks"
+	log := h.log.With(slog.String("op", op))
+	req, err := decodeAndValidate[RequestProjectTasks](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, req.UserID, model.TaskFilter{ProjectID: req.ProjectID}, req.RequestDeadlineWindow, model.PageRequest{})
+}
+
+// TeamTasks Returns the tasks assigned to the team members, optionally filtered by a deadline window
+func (h *Handler) TeamTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.TeamTasks"
+	log := h.log.With(slog.String("op", op))
+	req, err := decodeAndValidate[RequestTeamTasks](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, req.UserID, model.TaskFilter{TeamID: req.TeamID}, req.RequestDeadlineWindow, model.PageRequest{})
+}
+
+func (h *Handler) CreateTeam(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateTeam"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewTeam](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	teamID, err := h.service.CreateTeam(ctx, model.Team{Name: req.Name})
+	if err != nil {
+		errorHandler(log, "failed to create team", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseNewTeam{
+		Response: resp.OK(),
+		TeamID:   teamID,
+	})
+}
+
+func (h *Handler) AddTeamMember(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AddTeamMember"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestTeamMember](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.AddTeamMember(ctx, req.TeamID, req.UserID); err != nil {
+		errorHandler(log, "failed to add user to team", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetUserTimezone(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetUserTimezone"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestUserTimezone](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetUserTimezone(ctx, req.UserID, req.Timezone); err != nil {
+		errorHandler(log, "failed to update user timezone", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// windowCaller пользователь из токена, в часовом поясе которого вычисляются окна дедлайнов /v1.
+// У анонимного запроса окна вычисляются в UTC.
+func windowCaller(r *http.Request) int {
+	userID, _ := auth.UserID(r.Context())
+	return userID
+}
+
+// listTasks отдаёт страницу задач. Устаревшие маршруты передают пустой page и получают весь список.
+func (h *Handler) listTasks(w http.ResponseWriter, r *http.Request, log *slog.Logger,
+	callerID int, filter model.TaskFilter, req RequestDeadlineWindow, page model.PageRequest) {
+	window := model.DeadlineWindow{Name: req.Window, Days: req.Days}
+	if req.From != nil {
+		window.From = *req.From
+	}
+	if req.To != nil {
+		window.To = *req.To
+	}
+	tasks, err := h.service.ListTasks(r.Context(), callerID, filter, window, page)
+	if err != nil {
+		errorHandler(log, "failed to retrieve tasks", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTasks{
+		Tasks:      tasks.Items,
+		NextCursor: pagination.Encode(tasks.Next),
+		Total:      tasks.Total,
+		Response:   resp.OK(),
+	})
+}
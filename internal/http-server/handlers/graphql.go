@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"Tasks/internal/model"
+)
+
+// GraphQL executes a GraphQL query over tasks, users, projects and assignments.
+func (h *Handler) GraphQL(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GraphQL"
+	log := h.log.With(slog.String("op", op))
+	if _, err := callerID(r); err != nil {
+		errorHandler(log, "failed to execute query", err, w, r)
+		return
+	}
+	if h.graphql == nil {
+		errorHandler(log, "failed to execute query", model.NotFound("graphql endpoint is disabled"), w, r)
+		return
+	}
+	h.graphql.ServeHTTP(w, r)
+}
@@ -1,44 +1,85 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
 	"github.com/go-chi/render"
-	"github.com/go-playground/validator/v10"
 
+	"Tasks/internal/board"
+	"Tasks/internal/events"
+	"Tasks/internal/health"
 	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/etag"
+	"Tasks/internal/lib/i18n"
 	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/lib/validation"
 	"Tasks/internal/model"
 	"Tasks/internal/service"
 )
 
 const invalid = "invalid request"
 
+// validate общий для всех обработчиков: кэширует разбор тегов структур запросов
+var validate = validation.New()
+
 type Handler struct {
 	service service.Service
 	log     slog.Logger
+	events  *events.Hub
+	board   *board.Hub
+	graphql http.Handler
+	health  *health.Checker
+	metrics http.Handler
 }
 
+// Dependencies Events - подписки на поток событий задач, Board - соединения досок проектов,
+// GraphQL - исполнитель запросов /graphql, Health - проверки зависимостей для /readyz,
+// Metrics - выдача метрик Prometheus
 type Dependencies struct {
 	Service *service.Service
 	Log     *slog.Logger
+	Events  *events.Hub
+	Board   *board.Hub
+	GraphQL http.Handler
+	Health  *health.Checker
+	Metrics http.Handler
 }
 
 func NewHandler(deps *Dependencies) *Handler {
+	checker := deps.Health
+	if checker == nil {
+		checker = health.New(deps.Log, 0)
+	}
 	return &Handler{
 		service: *deps.Service,
 		log:     *deps.Log,
+		events:  deps.Events,
+		board:   deps.Board,
+		graphql: deps.GraphQL,
+		health:  checker,
+		metrics: deps.Metrics,
 	}
 }
 
+// UserLanguage язык из профиля пользователя, используется, когда клиент не прислал Accept-Language
+func (h *Handler) UserLanguage(ctx context.Context, userID int) string {
+	return h.service.UserLanguage(ctx, userID)
+}
+
 // Поступающие запросы
+// RequestNewTask дедлайн задаётся либо абсолютно (Deadline), либо относительно (DeadlineExpr, например "+3 business days"),
+// но не обоими способами сразу
 type RequestNewTask struct {
-	TaskText    string    `json:"task_text" validate:"required"`
-	Description string    `json:"description" validate:"required"`
-	Deadline    time.Time `json:"deadline" validate:"required"`
+	TaskText     string    `json:"task_text" validate:"required,max=255"`
+	Description  string    `json:"description" validate:"required"`
+	Deadline     time.Time `json:"deadline" validate:"required_without=DeadlineExpr,future"`
+	DeadlineExpr string    `json:"deadline_expr" validate:"excluded_with=Deadline"`
+	Priority     string    `json:"priority" validate:"priority"`
+	ProjectID    int       `json:"project_id"`
 }
 
 type RequestID struct {
@@ -56,7 +97,7 @@ type RequestTaskID struct {
 
 type RequestNewStatus struct {
 	TaskID    int    `json:"task_id" validate:"required"`
-	NewStatus string `json:"new_status" validate:"required"`
+	NewStatus string `json:"new_status" validate:"required,task_status"`
 }
 
 // Ответы
@@ -69,8 +110,12 @@ type Response struct {
 	resp.Response
 }
 
+// ResponseTasks NextCursor передаётся в параметре cursor для получения следующей страницы,
+// Total заполняется при запросе с total=true
 type ResponseTasks struct {
-	Tasks []model.Task `json:"tasks"`
+	Tasks      []model.Task `json:"tasks"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      *int         `json:"total,omitempty"`
 	resp.Response
 }
 
@@ -80,7 +125,9 @@ type ResponseTask struct {
 }
 
 type ResponseUsers struct {
-	Users []model.User `json:"users"`
+	Users      []model.User `json:"users"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      *int         `json:"total,omitempty"`
 	resp.Response
 }
 
@@ -99,6 +146,15 @@ func (h *Handler) CreateNewTask(w http.ResponseWriter, r *http.Request) {
 	task.NameTask = req.TaskText
 	task.Description = req.Description
 	task.Deadline = req.Deadline
+	task.Priority = req.Priority
+	task.ProjectID = req.ProjectID
+	if req.DeadlineExpr != "" {
+		task.Deadline, err = h.service.ResolveDeadline(ctx, req.ProjectID, req.DeadlineExpr)
+		if err != nil {
+			errorHandler(log, invalid, err, w, r)
+			return
+		}
+	}
 	taskID, err := h.service.CreateTask(ctx, task)
 	if err != nil {
 		errorHandler(log, "failed to create task", err, w, r)
@@ -121,9 +177,7 @@ func (h *Handler) AddUserFromTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if err := h.service.AddUser(ctx, req.UserID, req.TaskID); err != nil {
-		log.Error("failed to add user to task", sl.Err(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, r, resp.Error("failed to add user to task"))
+		errorHandler(log, "failed to add user to task", err, w, r)
 		return
 	}
 	log.Info("user added to task successfully", slog.Int("user_id", req.UserID), slog.Int("task_id", req.TaskID))
@@ -145,9 +199,7 @@ func (h *Handler) AllTasks(w http.ResponseWriter, r *http.Request) {
 	}
 	tasks, err := h.service.AllTasks(ctx, req.UserID)
 	if err != nil {
-		log.Error("failed to retrieve tasks", sl.Err(err))
-		w.WriteHeader(http.StatusInternalServerError)
-		render.JSON(w, r, resp.Error("failed to retrieve tasks"))
+		errorHandler(log, "failed to retrieve tasks", err, w, r)
 		return
 	}
 	log.Info("tasks retrieved successfully", slog.Int("user_id", req.UserID), slog.Int("task_count", len(tasks)))
@@ -167,14 +219,14 @@ func (h *Handler) AllUsers(w http.ResponseWriter, r *http.Request) {
 		errorHandler(log, invalid, err, w, r)
 		return
 	}
-	users, err := h.service.AllUsersWorkTask(ctx, req.TaskID)
+	users, err := h.service.AllUsersWorkTask(ctx, req.TaskID, model.PageRequest{})
 	if err != nil {
 		errorHandler(log, "failed to retrieve users", err, w, r)
 		return
 	}
-	log.Info("users retrieved successfully", slog.Int("task_id", req.TaskID), slog.Int("user_count", len(users)))
+	log.Info("users retrieved successfully", slog.Int("task_id", req.TaskID), slog.Int("user_count", len(users.Items)))
 	render.JSON(w, r, ResponseUsers{
-		Users:    users,
+		Users:    users.Items,
 		Response: resp.OK(),
 	})
 }
@@ -188,7 +240,7 @@ func (h *Handler) DeleteTask(w http.ResponseWriter, r *http.Request) {
 		errorHandler(log, invalid, err, w, r)
 		return
 	}
-	if err := h.service.DeleteTask(ctx, req.TaskID); err != nil {
+	if err := h.service.DeleteTask(ctx, req.TaskID, etag.IfMatch(r.Header.Get("If-Match"))); err != nil {
 		errorHandler(log, "failed to delete task", err, w, r)
 		return
 	}
@@ -246,10 +298,12 @@ func (h *Handler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
 		errorHandler(log, invalid, err, w, r)
 		return
 	}
-	if err := h.service.TaskUpdateStatus(ctx, req.NewStatus, req.TaskID); err != nil {
+	task, err := h.service.TaskUpdateStatus(ctx, req.NewStatus, req.TaskID, etag.IfMatch(r.Header.Get("If-Match")))
+	if err != nil {
 		errorHandler(log, "failed update task status", err, w, r)
 		return
 	}
+	w.Header().Set("ETag", etag.Format(task.Version))
 	render.JSON(w, r, Response{
 		Response: resp.OK(),
 	})
@@ -269,30 +323,46 @@ func (h *Handler) GetTaskByID(w http.ResponseWriter, r *http.Request) {
 		errorHandler(log, "failed update task status", err, w, r)
 		return
 	}
+	writeTask(w, r, task)
+}
 
+// Вспомогательные функции
+
+// writeTask отдаёт задачу с ETag её версии. Если клиент уже видел эту версию (If-None-Match),
+// отвечает 304 без тела.
+func writeTask(w http.ResponseWriter, r *http.Request, task model.Task) {
+	w.Header().Set("ETag", etag.Format(task.Version))
+	if etag.NoneMatch(r.Header.Get("If-None-Match"), task.Version) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	render.JSON(w, r, ResponseTask{
 		Task:     task,
 		Response: resp.OK(),
 	})
 }
 
-// Вспомогательные функции
 func decodeAndValidate[T any](r *http.Request, log slog.Logger) (*T, error) {
 	var req T
 	if err := render.DecodeJSON(r.Body, &req); err != nil {
 		log.Error("failed to decode request body", sl.Err(err))
-		return nil, fmt.Errorf("failed to decode request")
+		return nil, resp.BadRequest("failed to decode request")
 	}
-	if err := validator.New().Struct(req); err != nil {
+	if err := validate.Struct(req); err != nil {
 		log.Error("invalid request", sl.Err(err))
 		return nil, fmt.Errorf("validation error: %w", err)
 	}
 	return &req, nil
 }
 
+// errorHandler пишет ошибку в лог и отвечает статусом, соответствующим её виду
 func errorHandler(log *slog.Logger, msg string, err error, w http.ResponseWriter, r *http.Request) {
-	log.Error(msg, sl.Err(err))
-	w.WriteHeader(http.StatusBadRequest)
-	render.JSON(w, r, resp.Error(err.Error()))
-	return
+	status, body := resp.FromError(err, i18n.FromContext(r.Context()))
+	if status >= http.StatusInternalServerError {
+		log.Error(msg, sl.Err(err))
+	} else {
+		log.Info(msg, sl.Err(err))
+	}
+	render.Status(r, status)
+	render.JSON(w, r, body)
 }
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"Tasks/internal/model"
+)
+
+// Healthz Liveness probe: the process is up and serving requests.
+func (h *Handler) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.health.LiveHandler(w, r)
+}
+
+// Readyz Readiness probe: status of PostgreSQL, Redis and Kafka; 503 if any of them is unavailable
+// or the server is shutting down.
+func (h *Handler) Readyz(w http.ResponseWriter, r *http.Request) {
+	h.health.ReadyHandler(w, r)
+}
+
+// Metrics Prometheus metrics in the text exposition format.
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	if h.metrics == nil {
+		log := h.log.With(slog.String("op", "handlers.Metrics"))
+		errorHandler(log, "failed to collect metrics", model.NotFound("metrics are disabled"), w, r)
+		return
+	}
+	h.metrics.ServeHTTP(w, r)
+}
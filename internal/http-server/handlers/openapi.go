@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	_ "embed"
+	"net/http"
+	"sync"
+
+	"github.com/go-chi/render"
+
+	"Tasks/internal/graph"
+	"Tasks/internal/health"
+	"Tasks/internal/http-server/middleware/idempotency"
+	"Tasks/internal/http-server/openapi"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/model"
+)
+
+// Описание API. Каждый маршрут из SetupRouter должен быть перечислен здесь,
+// это проверяет тест роутера.
+
+var (
+	windowQuery = []openapi.Parameter{
+		{Name: "window", Description: "Именованное окно дедлайнов", Schema: &openapi.Schema{Type: "string", Enum: []string{"overdue", "today", "tomorrow", "this_week", "next_n_days"}}},
+		{Name: "days", Description: "Число дней для next_n_days", Schema: &openapi.Schema{Type: "integer"}},
+		{Name: "from", Description: "Начало диапазона дедлайнов, RFC 3339", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+		{Name: "to", Description: "Конец диапазона дедлайнов, RFC 3339", Schema: &openapi.Schema{Type: "string", Format: "date-time"}},
+	}
+	pageQuery = []openapi.Parameter{
+		{Name: "limit", Description: "Размер страницы, по умолчанию 50", Schema: &openapi.Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(model.MaxPageLimit)}},
+		{Name: "cursor", Description: "Значение next_cursor из предыдущей страницы", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "total", Description: "Вернуть общее число записей", Schema: &openapi.Schema{Type: "boolean"}},
+	}
+	taskListQuery = concat(windowQuery, pageQuery, []openapi.Parameter{
+		{Name: "sort", Description: "Поле сортировки, с префиксом \"-\" - по убыванию", Schema: &openapi.Schema{Type: "string", Enum: sortValues()}},
+		{Name: "status", Description: "Статусы задачи через запятую", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "text", Description: "Подстрока названия или описания", Schema: &openapi.Schema{Type: "string"}},
+		{Name: "q", Description: "Запрос на языке фильтров, например status != done AND deadline < now()+7d ORDER BY deadline", Schema: &openapi.Schema{Type: "string"}},
+	})
+	idempotencyHeader = openapi.Parameter{
+		Name:        idempotency.Header,
+		Description: "Ключ идемпотентности: повтор запроса с тем же ключом возвращает первый ответ с заголовком " + idempotency.ReplayedHeader,
+		Schema:      &openapi.Schema{Type: "string", MaxLength: intPtr(255)},
+	}
+	// условные запросы к задаче по её версии
+	ifNoneMatch = []openapi.Parameter{{Name: "If-None-Match", Description: "ETag из прошлого ответа; если задача не изменилась, ответ 304 без тела", Schema: &openapi.Schema{Type: "string"}}}
+	ifMatch     = []openapi.Parameter{{Name: "If-Match", Description: "ETag задачи; если задача изменилась, ответ 412", Schema: &openapi.Schema{Type: "string"}}}
+	notModified = map[string]openapi.Response{"304": {Description: "Задача не изменилась"}}
+	modified    = map[string]openapi.Response{"412": {Description: "Задача изменилась после чтения, версия не совпала с If-Match"}}
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func concat(groups ...[]openapi.Parameter) []openapi.Parameter {
+	var all []openapi.Parameter
+	for _, g := range groups {
+		all = append(all, g...)
+	}
+	return all
+}
+
+func sortValues() []string {
+	var values []string
+	for _, f := range model.TaskSortFields {
+		values = append(values, f, "-"+f)
+	}
+	return values
+}
+
+func v1Routes() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "/v1/tasks", Tag: "tasks", Summary: "Создать задачу", Request: RequestNewTask{}, Response: ResponseNewTask{}},
+		{Method: http.MethodPost, Path: "/v1/tasks:batch", Tag: "tasks", Summary: "Групповое изменение задач",
+			Description: "Меняет статус, исполнителей или метки либо удаляет задачи из списка в одной транзакции. " +
+				"Ошибка одной задачи не отменяет изменения остальных и возвращается в её результате. " +
+				"Каждый затронутый пользователь получает одно уведомление.",
+			Request: RequestBatch{}, Response: ResponseBatch{}},
+		{Method: http.MethodGet, Path: "/v1/tasks/{id}", Tag: "tasks", Summary: "Получить задачу", Headers: ifNoneMatch, Response: ResponseTask{}, Responses: notModified},
+		{Method: http.MethodDelete, Path: "/v1/tasks/{id}", Tag: "tasks", Summary: "Удалить задачу", Headers: ifMatch, Response: Response{}, Responses: modified},
+		{Method: http.MethodPut, Path: "/v1/tasks/{id}/status", Tag: "tasks", Summary: "Изменить статус задачи", Headers: ifMatch, Request: RequestStatus{}, Response: Response{}, Responses: modified},
+		{Method: http.MethodGet, Path: "/v1/tasks/{id}/history", Tag: "tasks", Summary: "История задачи", Response: ResponseTaskHistory{}},
+		{Method: http.MethodGet, Path: "/v1/tasks/{id}/assignees", Tag: "tasks", Summary: "Исполнители задачи", Query: pageQuery, Response: ResponseUsers{}},
+		{Method: http.MethodPut, Path: "/v1/tasks/{id}/assignees/{userID}", Tag: "tasks", Summary: "Назначить исполнителя", Response: Response{}},
+		{Method: http.MethodDelete, Path: "/v1/tasks/{id}/assignees/{userID}", Tag: "tasks", Summary: "Снять исполнителя", Response: Response{}},
+
+		{Method: http.MethodGet, Path: "/v1/users/{id}/tasks", Tag: "users", Summary: "Задачи пользователя", Query: taskListQuery, Response: ResponseTasks{}},
+		{Method: http.MethodGet, Path: "/v1/users/{id}/tasks/milestone-overrun", Tag: "users", Summary: "Задачи с дедлайном позже вехи", Response: ResponseTasks{}},
+		{Method: http.MethodPut, Path: "/v1/users/{id}/timezone", Tag: "users", Summary: "Изменить часовой пояс", Request: RequestTimezone{}, Response: Response{}},
+		{Method: http.MethodPut, Path: "/v1/users/{id}/language", Tag: "users", Summary: "Изменить язык уведомлений", Request: RequestLanguage{}, Response: Response{}},
+		{Method: http.MethodPut, Path: "/v1/users/{id}/calendar", Tag: "users", Summary: "Назначить календарь пользователю", Request: RequestCalendar{}, Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/v1/projects", Tag: "projects", Summary: "Создать проект", Request: RequestNewProject{}, Response: ResponseNewProject{}},
+		{Method: http.MethodGet, Path: "/v1/projects/{id}/tasks", Tag: "projects", Summary: "Задачи проекта", Query: taskListQuery, Response: ResponseTasks{}},
+		{Method: http.MethodGet, Path: "/v1/projects/{id}/escalation-rules", Tag: "projects", Summary: "Правила эскалации проекта", Response: ResponseEscalationRules{}},
+		{Method: http.MethodPut, Path: "/v1/projects/{id}/calendar", Tag: "projects", Summary: "Назначить календарь проекту", Request: RequestCalendar{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/v1/projects/{id}/board", Tag: "projects", Summary: "Канал доски проекта (WebSocket)", Auth: true,
+			Description: "Соединение WebSocket для участников проекта. Токен передаётся в заголовке Authorization " +
+				"или первым сообщением {\"type\": \"auth\", \"token\": \"...\"}. Клиент отправляет view (какую задачу открыл) " +
+				"и typing (набирает комментарий), сервер - ready, task_event, presence, typing и error.",
+			Responses: map[string]openapi.Response{"101": {Description: "Соединение переключено на WebSocket"}}},
+		{Method: http.MethodPost, Path: "/v1/escalation-rules", Tag: "projects", Summary: "Создать правило эскалации", Request: RequestNewEscalationRule{}, Response: ResponseNewEscalationRule{}},
+		{Method: http.MethodDelete, Path: "/v1/escalation-rules/{id}", Tag: "projects", Summary: "Удалить правило эскалации", Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/v1/teams", Tag: "teams", Summary: "Создать команду", Request: RequestNewTeam{}, Response: ResponseNewTeam{}},
+		{Method: http.MethodGet, Path: "/v1/teams/{id}/tasks", Tag: "teams", Summary: "Задачи команды", Query: taskListQuery, Response: ResponseTasks{}},
+		{Method: http.MethodPut, Path: "/v1/teams/{id}/members/{userID}", Tag: "teams", Summary: "Добавить участника", Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/v1/sprints", Tag: "sprints", Summary: "Создать спринт", Request: RequestNewSprint{}, Response: ResponseNewSprint{}},
+		{Method: http.MethodGet, Path: "/v1/sprints/{id}/tasks", Tag: "sprints", Summary: "Задачи спринта", Query: taskListQuery, Response: ResponseTasks{}},
+		{Method: http.MethodPut, Path: "/v1/sprints/{id}/tasks/{taskID}", Tag: "sprints", Summary: "Добавить задачу в спринт", Response: Response{}},
+		{Method: http.MethodPost, Path: "/v1/sprints/{id}/start", Tag: "sprints", Summary: "Запустить спринт", Response: Response{}},
+		{Method: http.MethodPost, Path: "/v1/sprints/{id}/complete", Tag: "sprints", Summary: "Завершить спринт",
+			Query:    []openapi.Parameter{{Name: "next_sprint_id", Description: "Спринт для незавершённых задач", Schema: &openapi.Schema{Type: "integer"}}},
+			Response: ResponseCompleteSprint{}},
+		{Method: http.MethodPost, Path: "/v1/milestones", Tag: "sprints", Summary: "Создать веху", Request: RequestNewMilestone{}, Response: ResponseNewMilestone{}},
+		{Method: http.MethodPut, Path: "/v1/milestones/{id}/tasks/{taskID}", Tag: "sprints", Summary: "Привязать задачу к вехе", Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/v1/calendars", Tag: "calendars", Summary: "Создать календарь", Request: RequestNewCalendar{}, Response: ResponseNewCalendar{}},
+		{Method: http.MethodGet, Path: "/v1/calendars/{id}", Tag: "calendars", Summary: "Получить календарь", Response: ResponseCalendar{}},
+		{Method: http.MethodPost, Path: "/v1/calendars/{id}/holidays", Tag: "calendars", Summary: "Импортировать праздники из iCal",
+			Request: &openapi.Schema{Type: "string"}, RequestContentType: "text/calendar", Response: ResponseImportHolidays{}},
+
+		{Method: http.MethodPost, Path: "/v1/views", Tag: "views", Summary: "Сохранить представление", Auth: true,
+			Description: "Условие задаётся на языке фильтров, как параметр q списков задач. Новое представление по умолчанию заменяет прежнее.",
+			Request:     RequestView{}, Response: ResponseNewView{}},
+		{Method: http.MethodGet, Path: "/v1/views", Tag: "views", Summary: "Представления пользователя и его проектов", Auth: true, Response: ResponseViews{}},
+		{Method: http.MethodGet, Path: "/v1/views/{id}", Tag: "views", Summary: "Получить представление", Auth: true, Response: ResponseView{}},
+		{Method: http.MethodPut, Path: "/v1/views/{id}", Tag: "views", Summary: "Изменить представление", Auth: true, Request: RequestView{}, Response: Response{}},
+		{Method: http.MethodDelete, Path: "/v1/views/{id}", Tag: "views", Summary: "Удалить представление", Auth: true, Response: Response{}},
+		{Method: http.MethodGet, Path: "/v1/views/{id}/tasks", Tag: "views", Summary: "Задачи представления", Auth: true,
+			Description: "Условие вычисляется для текущего пользователя: me - это он, в выборку попадают только видимые ему задачи.",
+			Query:       pageQuery, Response: ResponseViewTasks{}},
+		{Method: http.MethodPut, Path: "/v1/views/{id}/subscription", Tag: "views", Summary: "Подписаться на представление", Auth: true,
+			Description: "Планировщик уведомляет подписчика о задачах, попавших в представление после подписки.",
+			Response:    Response{}},
+		{Method: http.MethodDelete, Path: "/v1/views/{id}/subscription", Tag: "views", Summary: "Отписаться от представления", Auth: true, Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/v1/projects/{id}/webhooks", Tag: "webhooks", Summary: "Создать вебхук проекта", Auth: true,
+			Description: "Доступно руководителю проекта и администраторам. События задач проекта отправляются POST-запросом " +
+				"на url с заголовками X-Webhook-Event, X-Webhook-Timestamp и подписью X-Webhook-Signature: " +
+				"sha256=HMAC-SHA256(secret, \"<timestamp>.<тело>\"). Ключ подписи возвращается только в этом ответе.",
+			Request: RequestWebhook{}, Response: ResponseNewWebhook{}},
+		{Method: http.MethodGet, Path: "/v1/projects/{id}/webhooks", Tag: "webhooks", Summary: "Вебхуки проекта", Auth: true, Response: ResponseWebhooks{}},
+		{Method: http.MethodGet, Path: "/v1/webhooks/{id}", Tag: "webhooks", Summary: "Получить вебхук", Auth: true, Response: ResponseWebhook{}},
+		{Method: http.MethodPut, Path: "/v1/webhooks/{id}", Tag: "webhooks", Summary: "Изменить вебхук", Auth: true,
+			Description: "active: true включает вебхук, отключённый после неудачных доставок, и обнуляет счётчик неудач.",
+			Request:     RequestWebhook{}, Response: Response{}},
+		{Method: http.MethodDelete, Path: "/v1/webhooks/{id}", Tag: "webhooks", Summary: "Удалить вебхук", Auth: true, Response: Response{}},
+		{Method: http.MethodGet, Path: "/v1/webhooks/{id}/deliveries", Tag: "webhooks", Summary: "Журнал доставок вебхука", Auth: true,
+			Description: "Доставки от новых к старым: статус, число попыток, время следующей попытки и ответ получателя.",
+			Query:       pageQuery, Response: ResponseWebhookDeliveries{}},
+		{Method: http.MethodPost, Path: "/v1/webhooks/{id}/deliveries/{deliveryID}/replay", Tag: "webhooks", Summary: "Повторить доставку", Auth: true,
+			Description: "Отправляет событие доставки ещё раз новой доставкой.",
+			Response:    ResponseReplayDelivery{}},
+
+		{Method: http.MethodGet, Path: "/v1/sync", Tag: "sync", Summary: "Изменения задач с прошлой синхронизации", Auth: true,
+			Description: "Задачи, их исполнители и надгробия удалённых или ставших невидимыми задач, изменённые после токена since, " +
+				"в порядке изменений, и токен следующей синхронизации. Без since - все видимые пользователю задачи; " +
+				"reset: true - клиент заменяет локальные задачи ответом. При more: true следующий запрос делается сразу.",
+			Query: []openapi.Parameter{
+				{Name: "since", Description: "Значение token из прошлой синхронизации", Schema: &openapi.Schema{Type: "string"}},
+				{Name: "limit", Description: "Изменений в ответе, по умолчанию 500", Schema: &openapi.Schema{Type: "integer", Minimum: intPtr(1), Maximum: intPtr(model.MaxSyncLimit)}},
+			},
+			Response: ResponseSync{}},
+		{Method: http.MethodPost, Path: "/v1/sync", Tag: "sync", Summary: "Загрузить изменения, сделанные без связи", Auth: true,
+			Description: "Изменения применяются по порядку. Результат каждого - applied, conflict (состояние на сервере разошлось " +
+				"с тем, что видел клиент; task - текущая задача, без неё задача удалена) или error. " +
+				"Повтор запроса после обрыва связи защищается заголовком Idempotency-Key.",
+			Request: RequestSync{}, Response: ResponseSyncMutations{}},
+
+		{Method: http.MethodGet, Path: "/v1/search", Tag: "search", Summary: "Полнотекстовый поиск задач", Auth: true,
+			Description: "Ищет по названию и описанию задач, видимых пользователю. Слова ищутся по префиксу с учётом русской и английской морфологии.",
+			Query: concat([]openapi.Parameter{
+				{Name: "q", Description: "Поисковый запрос", Required: true, Schema: &openapi.Schema{Type: "string"}},
+			}, pageQuery),
+			Response: ResponseSearch{}},
+
+		{Method: http.MethodGet, Path: "/v1/stream", Tag: "events", Summary: "Поток изменений задач (Server-Sent Events)", Auth: true,
+			Description: "События task_created, task_updated, task_status_changed, task_assigned, task_unassigned и task_deleted " +
+				"по задачам, видимым пользователю; поле id события - его ID, data - событие в JSON. После переподключения " +
+				"поток продолжается с Last-Event-ID. Если пропущенные события уже не хранятся, приходит событие reset: " +
+				"данные нужно перечитать.",
+			Query:               []openapi.Parameter{{Name: "last_event_id", Description: "ID последнего полученного события, если клиент не может передать заголовок", Schema: &openapi.Schema{Type: "string"}}},
+			Headers:             []openapi.Parameter{{Name: "Last-Event-ID", Description: "ID последнего полученного события", Schema: &openapi.Schema{Type: "string"}}},
+			Response:            model.TaskEvent{},
+			ResponseContentType: "text/event-stream"},
+	}
+}
+
+// legacyRoutes устаревшие маршруты с идентификаторами в теле запроса
+func legacyRoutes() []openapi.Route {
+	routes := []openapi.Route{
+		{Method: http.MethodPost, Path: "/task", Summary: "Создать задачу", Request: RequestNewTask{}, Response: ResponseNewTask{}},
+		{Method: http.MethodPost, Path: "/adduser", Summary: "Назначить исполнителя", Request: RequestID{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/users", Summary: "Исполнители задачи", Request: RequestTaskID{}, Response: ResponseUsers{}},
+		{Method: http.MethodGet, Path: "/tasks", Summary: "Задачи пользователя", Request: RequestUserID{}, Response: ResponseTasks{}},
+		{Method: http.MethodGet, Path: "/shortdeadline", Summary: "Задачи с дедлайном в ближайшие 3 дня", Request: RequestUserID{}, Response: ResponseTasks{}},
+		{Method: http.MethodGet, Path: "/taskbyid", Summary: "Получить задачу", Headers: ifNoneMatch, Request: RequestTaskID{}, Response: ResponseTask{}, Responses: notModified},
+		{Method: http.MethodPut, Path: "/status", Summary: "Изменить статус задачи", Headers: ifMatch, Request: RequestNewStatus{}, Response: Response{}, Responses: modified},
+		{Method: http.MethodDelete, Path: "/task", Summary: "Удалить задачу", Headers: ifMatch, Request: RequestTaskID{}, Response: Response{}, Responses: modified},
+		{Method: http.MethodDelete, Path: "/user", Summary: "Снять исполнителя", Request: RequestID{}, Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/sprint", Summary: "Создать спринт", Request: RequestNewSprint{}, Response: ResponseNewSprint{}},
+		{Method: http.MethodPost, Path: "/sprint/task", Summary: "Добавить задачу в спринт", Request: RequestSprintTask{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/sprint/tasks", Summary: "Задачи спринта", Request: RequestSprintID{}, Response: ResponseTasks{}},
+		{Method: http.MethodPut, Path: "/sprint/start", Summary: "Запустить спринт", Request: RequestSprintID{}, Response: Response{}},
+		{Method: http.MethodPut, Path: "/sprint/complete", Summary: "Завершить спринт", Request: RequestCompleteSprint{}, Response: ResponseCompleteSprint{}},
+		{Method: http.MethodPost, Path: "/milestone", Summary: "Создать веху", Request: RequestNewMilestone{}, Response: ResponseNewMilestone{}},
+		{Method: http.MethodPut, Path: "/milestone/task", Summary: "Привязать задачу к вехе", Request: RequestMilestoneTask{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/milestoneoverrun", Summary: "Задачи с дедлайном позже вехи", Request: RequestUserID{}, Response: ResponseTasks{}},
+
+		{Method: http.MethodPost, Path: "/project", Summary: "Создать проект", Request: RequestNewProject{}, Response: ResponseNewProject{}},
+		{Method: http.MethodPost, Path: "/project/rule", Summary: "Создать правило эскалации", Request: RequestNewEscalationRule{}, Response: ResponseNewEscalationRule{}},
+		{Method: http.MethodGet, Path: "/project/rules", Summary: "Правила эскалации проекта", Request: RequestProjectID{}, Response: ResponseEscalationRules{}},
+		{Method: http.MethodDelete, Path: "/project/rule", Summary: "Удалить правило эскалации", Request: RequestRuleID{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/task/history", Summary: "История задачи", Request: RequestTaskID{}, Response: ResponseTaskHistory{}},
+
+		{Method: http.MethodGet, Path: "/tasks/deadline", Summary: "Задачи пользователя по окну дедлайнов", Request: RequestUserDeadline{}, Response: ResponseTasks{}},
+		{Method: http.MethodGet, Path: "/project/tasks", Summary: "Задачи проекта", Request: RequestProjectTasks{}, Response: ResponseTasks{}},
+		{Method: http.MethodPost, Path: "/team", Summary: "Создать команду", Request: RequestNewTeam{}, Response: ResponseNewTeam{}},
+		{Method: http.MethodPost, Path: "/team/member", Summary: "Добавить участника", Request: RequestTeamMember{}, Response: Response{}},
+		{Method: http.MethodGet, Path: "/team/tasks", Summary: "Задачи команды", Request: RequestTeamTasks{}, Response: ResponseTasks{}},
+		{Method: http.MethodPut, Path: "/user/timezone", Summary: "Изменить часовой пояс", Request: RequestUserTimezone{}, Response: Response{}},
+
+		{Method: http.MethodPost, Path: "/calendar", Summary: "Создать календарь", Request: RequestNewCalendar{}, Response: ResponseNewCalendar{}},
+		{Method: http.MethodPost, Path: "/calendar/holidays", Summary: "Импортировать праздники из iCal", Request: RequestImportHolidays{}, Response: ResponseImportHolidays{}},
+		{Method: http.MethodGet, Path: "/calendar", Summary: "Получить календарь", Request: RequestCalendarID{}, Response: ResponseCalendar{}},
+		{Method: http.MethodPut, Path: "/project/calendar", Summary: "Назначить календарь проекту", Request: RequestProjectCalendar{}, Response: Response{}},
+		{Method: http.MethodPut, Path: "/user/calendar", Summary: "Назначить календарь пользователю", Request: RequestUserCalendar{}, Response: Response{}},
+	}
+	for i := range routes {
+		routes[i].Tag = "legacy"
+		routes[i].Deprecated = true
+	}
+	return routes
+}
+
+func docsRoutes() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/openapi.json", Tag: "docs", Summary: "Описание API в формате OpenAPI 3", Response: &openapi.Schema{Type: "object"}},
+		{Method: http.MethodGet, Path: "/docs", Tag: "docs", Summary: "Документация API (Redoc)"},
+	}
+}
+
+func graphqlRoutes() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodPost, Path: "/graphql", Tag: "graphql", Summary: "Запрос GraphQL", Auth: true,
+			Description: "Задачи, пользователи, проекты и назначения одним запросом. Схема - internal/graph/schema.graphql. " +
+				"Ошибки полей возвращаются в errors со статусом 200, запрос дороже лимита сложности - со статусом 400.",
+			Request: graph.Request{}, Response: &openapi.Schema{Type: "object"}},
+	}
+}
+
+func healthRoutes() []openapi.Route {
+	return []openapi.Route{
+		{Method: http.MethodGet, Path: "/healthz", Tag: "health", Summary: "Проверка живости",
+			Description: "Отвечает, пока процесс обслуживает запросы. Зависимости не проверяются.",
+			Response:    health.Report{}},
+		{Method: http.MethodGet, Path: "/readyz", Tag: "health", Summary: "Проверка готовности",
+			Description: "Проверяет PostgreSQL, Redis и Kafka, каждую зависимость с таймаутом, и возвращает статус каждой. " +
+				"Со статусом 503, если хотя бы одна недоступна или сервер останавливается.",
+			Response: health.Report{}, Responses: map[string]openapi.Response{"503": {Description: "Приложение не готово принимать запросы"}}},
+		{Method: http.MethodGet, Path: "/metrics", Tag: "health", Summary: "Метрики Prometheus",
+			Description:         "HTTP-запросы по шаблону маршрута, пул PostgreSQL, кэш задач, отправка в Kafka, незавершённые и просроченные задачи.",
+			ResponseContentType: "text/plain"},
+	}
+}
+
+// OpenAPI возвращает описание всех маршрутов API
+var OpenAPI = sync.OnceValue(func() *openapi.Document {
+	b := openapi.New(openapi.Info{
+		Title:       "Tasks API",
+		Version:     "1.0.0",
+		Description: "API для управления задачами. Маршруты без префикса /v1 устарели.",
+	}, resp.Response{})
+	for _, group := range [][]openapi.Route{v1Routes(), legacyRoutes(), graphqlRoutes(), healthRoutes(), docsRoutes()} {
+		for _, r := range group {
+			switch r.Method {
+			case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+				r.Headers = append(r.Headers, idempotencyHeader)
+			}
+			b.Add(r)
+		}
+	}
+	return b.Document()
+})
+
+//go:embed docs.html
+var docsPage []byte
+
+// OpenAPISpec Returns the OpenAPI document
+func (h *Handler) OpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, r, OpenAPI())
+}
+
+// Docs Returns the Redoc page rendering /openapi.json
+func (h *Handler) Docs(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(docsPage)
+}
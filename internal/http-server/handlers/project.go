@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+type RequestNewProject struct {
+	Name      string `json:"name" validate:"required,max=255"`
+	ManagerID int    `json:"manager_id"`
+}
+
+type RequestProjectID struct {
+	ProjectID int `json:"project_id" validate:"required"`
+}
+
+// RequestNewEscalationRule OverdueBy задаётся в формате time.ParseDuration, например "4h".
+// AssignManager и NotifyManager по умолчанию включены.
+type RequestNewEscalationRule struct {
+	ProjectID     int    `json:"project_id" validate:"required"`
+	Priority      string `json:"priority" validate:"required,priority"`
+	OverdueBy     string `json:"overdue_by"`
+	AssignManager *bool  `json:"assign_manager"`
+	NotifyManager *bool  `json:"notify_manager"`
+}
+
+type RequestRuleID struct {
+	RuleID int `json:"rule_id" validate:"required"`
+}
+
+// Ответы
+type ResponseNewProject struct {
+	resp.Response
+	ProjectID int `json:"project_id"`
+}
+
+type ResponseNewEscalationRule struct {
+	resp.Response
+	RuleID int `json:"rule_id"`
+}
+
+type ResponseEscalationRules struct {
+	Rules []model.EscalationRule `json:"rules"`
+	resp.Response
+}
+
+type ResponseTaskHistory struct {
+	History []model.TaskHistory `json:"history"`
+	resp.Response
+}
+
+// Обработчики
+func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateProject"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewProject](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	projectID, err := h.service.CreateProject(ctx, model.Project{Name: req.Name, ManagerID: req.ManagerID})
+	if err != nil {
+		errorHandler(log, "failed to create project", err, w, r)
+		return
+	}
+	log.Info("project created successfully", slog.Int("project_id", projectID))
+	render.JSON(w, r, ResponseNewProject{
+		Response:  resp.OK(),
+		ProjectID: projectID,
+	})
+}
+
+func (h *Handler) CreateEscalationRule(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateEscalationRule"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewEscalationRule](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	var overdueBy time.Duration
+	if req.OverdueBy != "" {
+		overdueBy, err = time.ParseDuration(req.OverdueBy)
+		if err != nil {
+			errorHandler(log, invalid, model.Invalid("invalid overdue_by: %v", err), w, r)
+			return
+		}
+	}
+	rule := model.EscalationRule{
+		ProjectID:     req.ProjectID,
+		Priority:      req.Priority,
+		OverdueBy:     overdueBy,
+		AssignManager: req.AssignManager == nil || *req.AssignManager,
+		NotifyManager: req.NotifyManager == nil || *req.NotifyManager,
+		Enabled:       true,
+	}
+	ruleID, err := h.service.CreateEscalationRule(ctx, rule)
+	if err != nil {
+		errorHandler(log, "failed to create escalation rule", err, w, r)
+		return
+	}
+	log.Info("escalation rule created successfully", slog.Int("rule_id", ruleID))
+	render.JSON(w, r, ResponseNewEscalationRule{
+		Response: resp.OK(),
+		RuleID:   ruleID,
+	})
+}
+
+// EscalationRules Returns all escalation rules of the project by projectID
+func (h *Handler) EscalationRules(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.EscalationRules"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestProjectID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	rules, err := h.service.EscalationRules(ctx, req.ProjectID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve escalation rules", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseEscalationRules{
+		Rules:    rules,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) DeleteEscalationRule(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeleteEscalationRule"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestRuleID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.DeleteEscalationRule(ctx, req.RuleID); err != nil {
+		errorHandler(log, "failed to delete escalation rule", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// TaskHistory Returns the history of the task by taskID
+func (h *Handler) TaskHistory(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.TaskHistory"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestTaskID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	history, err := h.service.TaskHistory(ctx, req.TaskID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve task history", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTaskHistory{
+		History:  history,
+		Response: resp.OK(),
+	})
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/model"
+)
+
+// Ответы
+type ResponseSearch struct {
+	Results    []model.SearchResult `json:"results"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+	Total      *int                 `json:"total,omitempty"`
+	resp.Response
+}
+
+// Обработчики
+
+// SearchV1 Full-text search over the tasks visible to the authenticated user, ordered by relevance
+func (h *Handler) SearchV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SearchV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := callerID(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	page, err := queryPage(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	results, err := h.service.Search(r.Context(), userID, r.URL.Query().Get("q"), page)
+	if err != nil {
+		errorHandler(log, "failed to search tasks", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseSearch{
+		Results:    results.Items,
+		NextCursor: pagination.Encode(results.Next),
+		Total:      results.Total,
+		Response:   resp.OK(),
+	})
+}
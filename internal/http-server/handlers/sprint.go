@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+type RequestNewSprint struct {
+	Title     string    `json:"title" validate:"required,max=255"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+type RequestSprintID struct {
+	SprintID int `json:"sprint_id" validate:"required"`
+}
+
+type RequestSprintTask struct {
+	SprintID int `json:"sprint_id" validate:"required"`
+	TaskID   int `json:"task_id" validate:"required"`
+}
+
+type RequestCompleteSprint struct {
+	SprintID     int `json:"sprint_id" validate:"required"`
+	NextSprintID int `json:"next_sprint_id"`
+}
+
+type RequestNewMilestone struct {
+	Title     string    `json:"title" validate:"required,max=255"`
+	StartDate time.Time `json:"start_date" validate:"required"`
+	EndDate   time.Time `json:"end_date" validate:"required"`
+}
+
+type RequestMilestoneTask struct {
+	MilestoneID int `json:"milestone_id" validate:"required"`
+	TaskID      int `json:"task_id" validate:"required"`
+}
+
+// Ответы
+type ResponseNewSprint struct {
+	resp.Response
+	SprintID int `json:"sprint_id"`
+}
+
+type ResponseCompleteSprint struct {
+	resp.Response
+	CarriedOver []int `json:"carried_over"`
+}
+
+type ResponseNewMilestone struct {
+	resp.Response
+	MilestoneID int `json:"milestone_id"`
+}
+
+// Обработчики
+func (h *Handler) CreateSprint(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateSprint"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewSprint](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	sprintID, err := h.service.CreateSprint(ctx, model.Sprint{
+		Title:     req.Title,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if err != nil {
+		errorHandler(log, "failed to create sprint", err, w, r)
+		return
+	}
+	log.Info("sprint created successfully", slog.Int("sprint_id", sprintID))
+	render.JSON(w, r, ResponseNewSprint{
+		Response: resp.OK(),
+		SprintID: sprintID,
+	})
+}
+
+func (h *Handler) AddTaskToSprint(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AddTaskToSprint"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestSprintTask](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.AddTaskToSprint(ctx, req.SprintID, req.TaskID); err != nil {
+		errorHandler(log, "failed to add task to sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// SprintTasks Returns all tasks of the sprint by sprintID
+func (h *Handler) SprintTasks(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SprintTasks"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestSprintID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	tasks, err := h.service.SprintTasks(ctx, req.SprintID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve sprint tasks", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTasks{
+		Tasks:    tasks,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) StartSprint(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.StartSprint"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestSprintID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.StartSprint(ctx, req.SprintID); err != nil {
+		errorHandler(log, "failed to start sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// CompleteSprint Completes the sprint and carries unfinished tasks over into the next one
+func (h *Handler) CompleteSprint(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CompleteSprint"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestCompleteSprint](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	carried, err := h.service.CompleteSprint(ctx, req.SprintID, req.NextSprintID)
+	if err != nil {
+		errorHandler(log, "failed to complete sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseCompleteSprint{
+		Response:    resp.OK(),
+		CarriedOver: carried,
+	})
+}
+
+func (h *Handler) CreateMilestone(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateMilestone"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestNewMilestone](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	milestoneID, err := h.service.CreateMilestone(ctx, model.Milestone{
+		Title:     req.Title,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+	})
+	if err != nil {
+		errorHandler(log, "failed to create milestone", err, w, r)
+		return
+	}
+	log.Info("milestone created successfully", slog.Int("milestone_id", milestoneID))
+	render.JSON(w, r, ResponseNewMilestone{
+		Response:    resp.OK(),
+		MilestoneID: milestoneID,
+	})
+}
+
+func (h *Handler) SetTaskMilestone(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetTaskMilestone"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestMilestoneTask](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetTaskMilestone(ctx, req.TaskID, req.MilestoneID); err != nil {
+		errorHandler(log, "failed to attach task to milestone", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// MilestoneOverrun Returns the user's tasks whose deadline falls after their milestone's end date
+func (h *Handler) MilestoneOverrun(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.MilestoneOverrun"
+	log := h.log.With(slog.String("op", op))
+	ctx := r.Context()
+	req, err := decodeAndValidate[RequestUserID](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	tasks, err := h.service.TaskMilestoneOverrun(ctx, req.UserID)
+	if err != nil {
+		errorHandler(log, "failed gets tasks overrunning their milestone", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTasks{
+		Tasks:    tasks,
+		Response: resp.OK(),
+	})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"Tasks/internal/events"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const (
+	// streamHeartbeat интервал комментариев, по которым клиент и прокси видят, что соединение живо
+	streamHeartbeat = 15 * time.Second
+	// streamScopeRefresh как часто перечитывается область видимости: команды и проекты пользователя меняются
+	streamScopeRefresh = time.Minute
+	// streamRetry через сколько миллисекунд клиент переподключается после обрыва
+	streamRetry = 3000
+	// streamReset событие о том, что пропущенные события восстановить нельзя и данные нужно перечитать
+	streamReset = "reset"
+)
+
+// Обработчики
+
+// StreamV1 Server-Sent Events stream of changes of the tasks visible to the caller. After a reconnect
+// the stream resumes after Last-Event-ID; if the missed events are no longer kept, a reset event
+// tells the client to reload its data.
+func (h *Handler) StreamV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.StreamV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := callerID(r)
+	if err != nil {
+		errorHandler(log, "failed to open event stream", err, w, r)
+		return
+	}
+	// EventSource присылает Last-Event-ID сам, параметр - для первого подключения
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("last_event_id")
+	}
+	if lastID != "" && !events.ValidID(lastID) {
+		errorHandler(log, invalid, resp.BadRequest("invalid %s header", "Last-Event-ID"), w, r)
+		return
+	}
+
+	ctx := r.Context()
+	scope, err := h.service.EventScope(ctx, userID)
+	if err != nil {
+		errorHandler(log, "failed to get event scope", err, w, r)
+		return
+	}
+	sub, err := h.events.Subscribe(ctx, lastID)
+	if err != nil {
+		errorHandler(log, "failed to subscribe to events", err, w, r)
+		return
+	}
+	defer sub.Close()
+
+	rc := http.NewResponseController(w)
+	// поток открыт дольше WriteTimeout сервера
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to disable write deadline", sl.Err(err))
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if !sub.Complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", streamReset)
+	}
+
+	last := sub.Last
+	for _, event := range sub.Replay {
+		if events.Visible(scope, event) {
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+		}
+	}
+	if err := rc.Flush(); err != nil {
+		log.Error("failed to flush event stream", sl.Err(err))
+		return
+	}
+	log.Info("event stream opened", slog.Int("user_id", userID), slog.Int("replayed", len(sub.Replay)))
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	refresh := time.NewTicker(streamScopeRefresh)
+	defer refresh.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				// подписка отключена: клиент не успевал или сервер останавливается, клиент переподключится
+				log.Info("event stream closed by server", slog.Int("user_id", userID))
+				return
+			}
+			// событие уже отправлено при восстановлении
+			if !events.After(event.ID, last) {
+				continue
+			}
+			last = event.ID
+			if !events.Visible(scope, event) {
+				continue
+			}
+			if err := writeStreamEvent(w, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-refresh.C:
+			if updated, err := h.service.EventScope(ctx, userID); err != nil {
+				log.Warn("failed to refresh event scope", sl.Err(err))
+			} else {
+				scope = updated
+			}
+			continue
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeStreamEvent(w io.Writer, event model.TaskEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
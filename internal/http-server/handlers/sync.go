@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/synctoken"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+
+// RequestSync изменения, накопленные клиентом без связи, в порядке их выполнения
+type RequestSync struct {
+	Mutations []RequestMutation `json:"mutations" validate:"required,min=1,max=100,unique=ID,dive"`
+}
+
+// RequestMutation задача указывается task_id или task_ref - id изменения create_task из того же запроса.
+// Task нужна для create_task, Status - для set_status, UserID - для assign и unassign.
+// BaseVersion - версия задачи, которую видел клиент, для set_status и delete_task; 0 - без проверки.
+type RequestMutation struct {
+	ID          string           `json:"id" validate:"required,max=100"`
+	Type        string           `json:"type" validate:"required,oneof=create_task set_status assign unassign delete_task"`
+	TaskID      int              `json:"task_id" validate:"min=0"`
+	TaskRef     string           `json:"task_ref" validate:"max=100"`
+	Task        *RequestSyncTask `json:"task" validate:"required_if=Type create_task"`
+	Status      string           `json:"status" validate:"omitempty,task_status"`
+	UserID      int              `json:"user_id" validate:"min=0"`
+	BaseVersion int              `json:"base_version" validate:"min=0"`
+}
+
+type RequestSyncTask struct {
+	TaskText    string    `json:"task_text" validate:"required,max=255"`
+	Description string    `json:"description" validate:"required"`
+	Deadline    time.Time `json:"deadline" validate:"required"`
+	Priority    string    `json:"priority" validate:"priority"`
+	ProjectID   int       `json:"project_id"`
+}
+
+func (req RequestMutation) mutation() model.SyncMutation {
+	m := model.SyncMutation{
+		ID:          req.ID,
+		Type:        req.Type,
+		TaskID:      req.TaskID,
+		TaskRef:     req.TaskRef,
+		Status:      req.Status,
+		UserID:      req.UserID,
+		BaseVersion: req.BaseVersion,
+	}
+	if req.Task != nil {
+		m.Task = model.Task{
+			NameTask:    req.Task.TaskText,
+			Description: req.Task.Description,
+			Deadline:    req.Task.Deadline,
+			Priority:    req.Task.Priority,
+			ProjectID:   req.Task.ProjectID,
+		}
+	}
+	return m
+}
+
+// Ответы
+
+// ResponseSync Assignments - текущие исполнители каждой задачи из Tasks. Token передаётся в параметре since
+// следующей синхронизации. More - изменения не поместились в ответ, следующий запрос нужно сделать сразу.
+// Reset - клиент должен заменить локальные задачи ответом (и следующими страницами при More).
+type ResponseSync struct {
+	Tasks       []model.Task          `json:"tasks"`
+	Assignments []model.TaskAssignees `json:"assignments"`
+	Tombstones  []model.Tombstone     `json:"tombstones"`
+	Token       string                `json:"token"`
+	More        bool                  `json:"more"`
+	Reset       bool                  `json:"reset"`
+	resp.Response
+}
+
+// SyncMutationResult status applied, conflict или error. Task - текущая задача на сервере при конфликте,
+// без неё - задача удалена. Code и Error - как в ответах API.
+type SyncMutationResult struct {
+	ID      string      `json:"id"`
+	Status  string      `json:"status"`
+	TaskID  int         `json:"task_id,omitempty"`
+	Version int         `json:"version,omitempty"`
+	Task    *model.Task `json:"task,omitempty"`
+	Code    string      `json:"code,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type ResponseSyncMutations struct {
+	Results []SyncMutationResult `json:"results"`
+	resp.Response
+}
+
+// Обработчики
+
+// SyncV1 Returns tasks, assignments and tombstones visible to the authenticated user that changed since
+// the token, and a token for the next sync. Without a token returns all visible tasks.
+func (h *Handler) SyncV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SyncV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := callerID(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	since, err := synctoken.Decode(r.URL.Query().Get("since"))
+	if err != nil {
+		errorHandler(log, invalid, resp.BadRequest("invalid query parameter %s", "since"), w, r)
+		return
+	}
+	limit, err := queryInt(r, "limit")
+	if err == nil && limit > model.MaxSyncLimit {
+		err = resp.BadRequest("invalid query parameter %s", "limit")
+	}
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	changes, err := h.service.SyncChanges(r.Context(), userID, since, limit)
+	if err != nil {
+		errorHandler(log, "failed to retrieve sync changes", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseSync{
+		Tasks:       changes.Tasks,
+		Assignments: changes.Assignments,
+		Tombstones:  changes.Tombstones,
+		Token:       synctoken.Encode(changes.Seq),
+		More:        changes.More,
+		Reset:       changes.Reset,
+		Response:    resp.OK(),
+	})
+}
+
+// SyncMutationsV1 Applies changes made by the client while offline, in order. A failed or conflicting change
+// does not stop the others, its outcome is reported in its own result.
+func (h *Handler) SyncMutationsV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SyncMutationsV1"
+	log := h.log.With(slog.String("op", op))
+	if _, err := callerID(r); err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestSync](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	mutations := make([]model.SyncMutation, 0, len(req.Mutations))
+	for _, m := range req.Mutations {
+		mutations = append(mutations, m.mutation())
+	}
+	results, err := h.service.ApplySyncMutations(r.Context(), mutations)
+	if err != nil {
+		errorHandler(log, "failed to apply sync mutations", err, w, r)
+		return
+	}
+
+	lang := i18n.FromContext(r.Context())
+	out := make([]SyncMutationResult, 0, len(results))
+	applied := 0
+	for _, res := range results {
+		item := SyncMutationResult{ID: res.MutationID, Status: res.Status, TaskID: res.TaskID, Version: res.Version, Task: res.Task}
+		if res.Err != nil {
+			_, body := resp.FromError(res.Err, lang)
+			item.Code, item.Error = body.Code, body.Error
+		} else {
+			applied++
+		}
+		out = append(out, item)
+	}
+	log.Info("sync mutations applied", slog.Int("mutations", len(results)), slog.Int("applied", applied))
+	render.JSON(w, r, ResponseSyncMutations{
+		Results:  out,
+		Response: resp.OK(),
+	})
+}
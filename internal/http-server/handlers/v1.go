@@ -0,0 +1,669 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+
+	"Tasks/internal/http-server/middleware/auth"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/etag"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/lib/query"
+	"Tasks/internal/model"
+)
+
+// Обработчики /v1: идентификаторы ресурсов передаются в пути, параметры выборки - в строке запроса.
+// Тело запроса используется только для создаваемых и изменяемых данных.
+
+// Поступающие запросы
+type RequestStatus struct {
+	Status string `json:"status" validate:"required,task_status"`
+}
+
+type RequestTimezone struct {
+	Timezone string `json:"timezone" validate:"required,max=64"`
+}
+
+type RequestLanguage struct {
+	Language string `json:"language" validate:"required,oneof=en ru"`
+}
+
+// RequestCalendar CalendarID = 0 снимает календарь
+type RequestCalendar struct {
+	CalendarID int `json:"calendar_id"`
+}
+
+// Обработчики
+func (h *Handler) GetTaskV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetTaskV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	task, err := h.service.TaskByID(r.Context(), taskID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve task", err, w, r)
+		return
+	}
+	writeTask(w, r, task)
+}
+
+func (h *Handler) DeleteTaskV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeleteTaskV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.DeleteTask(r.Context(), taskID, etag.IfMatch(r.Header.Get("If-Match"))); err != nil {
+		errorHandler(log, "failed to delete task", err, w, r)
+		return
+	}
+	log.Info("task deleted successfully", slog.Int("task_id", taskID))
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) UpdateStatusV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.UpdateStatusV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestStatus](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	task, err := h.service.TaskUpdateStatus(r.Context(), req.Status, taskID, etag.IfMatch(r.Header.Get("If-Match")))
+	if err != nil {
+		errorHandler(log, "failed update task status", err, w, r)
+		return
+	}
+	w.Header().Set("ETag", etag.Format(task.Version))
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) TaskHistoryV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.TaskHistoryV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	history, err := h.service.TaskHistory(r.Context(), taskID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve task history", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTaskHistory{
+		History:  history,
+		Response: resp.OK(),
+	})
+}
+
+// AllUsersV1 Returns a page of users working on the task, ordered by ID
+func (h *Handler) AllUsersV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AllUsersV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	page, err := queryPage(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	users, err := h.service.AllUsersWorkTask(r.Context(), taskID, page)
+	if err != nil {
+		errorHandler(log, "failed to retrieve users", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseUsers{
+		Users:      users.Items,
+		NextCursor: pagination.Encode(users.Next),
+		Total:      users.Total,
+		Response:   resp.OK(),
+	})
+}
+
+func (h *Handler) AddUserV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AddUserV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, userID, err := urlParamPair(r, "id", "userID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.AddUser(r.Context(), userID, taskID); err != nil {
+		errorHandler(log, "failed to add user to task", err, w, r)
+		return
+	}
+	log.Info("user added to task successfully", slog.Int("user_id", userID), slog.Int("task_id", taskID))
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) RemoveUserV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.RemoveUserV1"
+	log := h.log.With(slog.String("op", op))
+	taskID, userID, err := urlParamPair(r, "id", "userID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.RemoveUserFromTask(r.Context(), userID, taskID); err != nil {
+		errorHandler(log, "failed removing the user from the task", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// UserTasksV1 Returns the user's tasks, optionally filtered by a deadline window in the caller's timezone
+func (h *Handler) UserTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.UserTasksV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	filter := model.TaskFilter{UserID: userID}
+	window, page, err := queryTaskList(r, &filter)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, windowCaller(r), filter, window, page)
+}
+
+func (h *Handler) MilestoneOverrunV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.MilestoneOverrunV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	tasks, err := h.service.TaskMilestoneOverrun(r.Context(), userID)
+	if err != nil {
+		errorHandler(log, "failed gets tasks overrunning their milestone", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseTasks{
+		Tasks:    tasks,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetUserTimezoneV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetUserTimezoneV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestTimezone](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetUserTimezone(r.Context(), userID, req.Timezone); err != nil {
+		errorHandler(log, "failed to update user timezone", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// SetUserLanguageV1 Sets the language of the user's notifications
+func (h *Handler) SetUserLanguageV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetUserLanguageV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestLanguage](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetUserLanguage(r.Context(), userID, req.Language); err != nil {
+		errorHandler(log, "failed to update user language", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetUserCalendarV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetUserCalendarV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestCalendar](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetUserCalendar(r.Context(), userID, req.CalendarID); err != nil {
+		errorHandler(log, "failed to assign calendar to user", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// ProjectTasksV1 Returns the project's tasks. The deadline window is evaluated in the caller's timezone
+func (h *Handler) ProjectTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ProjectTasksV1"
+	log := h.log.With(slog.String("op", op))
+	projectID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	filter := model.TaskFilter{ProjectID: projectID}
+	window, page, err := queryTaskList(r, &filter)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, windowCaller(r), filter, window, page)
+}
+
+func (h *Handler) EscalationRulesV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.EscalationRulesV1"
+	log := h.log.With(slog.String("op", op))
+	projectID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	rules, err := h.service.EscalationRules(r.Context(), projectID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve escalation rules", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseEscalationRules{
+		Rules:    rules,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SetProjectCalendarV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetProjectCalendarV1"
+	log := h.log.With(slog.String("op", op))
+	projectID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestCalendar](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetProjectCalendar(r.Context(), projectID, req.CalendarID); err != nil {
+		errorHandler(log, "failed to assign calendar to project", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) DeleteEscalationRuleV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeleteEscalationRuleV1"
+	log := h.log.With(slog.String("op", op))
+	ruleID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.DeleteEscalationRule(r.Context(), ruleID); err != nil {
+		errorHandler(log, "failed to delete escalation rule", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// TeamTasksV1 Returns the tasks of the team members. The deadline window is evaluated in the caller's timezone
+func (h *Handler) TeamTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.TeamTasksV1"
+	log := h.log.With(slog.String("op", op))
+	teamID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	filter := model.TaskFilter{TeamID: teamID}
+	window, page, err := queryTaskList(r, &filter)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, windowCaller(r), filter, window, page)
+}
+
+func (h *Handler) AddTeamMemberV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AddTeamMemberV1"
+	log := h.log.With(slog.String("op", op))
+	teamID, userID, err := urlParamPair(r, "id", "userID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.AddTeamMember(r.Context(), teamID, userID); err != nil {
+		errorHandler(log, "failed to add user to team", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) SprintTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SprintTasksV1"
+	log := h.log.With(slog.String("op", op))
+	sprintID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	filter := model.TaskFilter{SprintID: sprintID}
+	window, page, err := queryTaskList(r, &filter)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	h.listTasks(w, r, log, windowCaller(r), filter, window, page)
+}
+
+func (h *Handler) AddTaskToSprintV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.AddTaskToSprintV1"
+	log := h.log.With(slog.String("op", op))
+	sprintID, taskID, err := urlParamPair(r, "id", "taskID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.AddTaskToSprint(r.Context(), sprintID, taskID); err != nil {
+		errorHandler(log, "failed to add task to sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) StartSprintV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.StartSprintV1"
+	log := h.log.With(slog.String("op", op))
+	sprintID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.StartSprint(r.Context(), sprintID); err != nil {
+		errorHandler(log, "failed to start sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+// CompleteSprintV1 Completes the sprint; unfinished tasks go to next_sprint_id or the nearest planned sprint
+func (h *Handler) CompleteSprintV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CompleteSprintV1"
+	log := h.log.With(slog.String("op", op))
+	sprintID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	nextSprintID, err := queryInt(r, "next_sprint_id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	carried, err := h.service.CompleteSprint(r.Context(), sprintID, nextSprintID)
+	if err != nil {
+		errorHandler(log, "failed to complete sprint", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseCompleteSprint{
+		Response:    resp.OK(),
+		CarriedOver: carried,
+	})
+}
+
+func (h *Handler) SetTaskMilestoneV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SetTaskMilestoneV1"
+	log := h.log.With(slog.String("op", op))
+	milestoneID, taskID, err := urlParamPair(r, "id", "taskID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SetTaskMilestone(r.Context(), taskID, milestoneID); err != nil {
+		errorHandler(log, "failed to attach task to milestone", err, w, r)
+		return
+	}
+	render.JSON(w, r, Response{
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) GetCalendarV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetCalendarV1"
+	log := h.log.With(slog.String("op", op))
+	calendarID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	calendar, err := h.service.CalendarByID(r.Context(), calendarID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve calendar", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseCalendar{
+		Calendar: calendar,
+		Response: resp.OK(),
+	})
+}
+
+// ImportHolidaysV1 Imports holidays from an iCalendar file sent as the request body (text/calendar)
+func (h *Handler) ImportHolidaysV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ImportHolidaysV1"
+	log := h.log.With(slog.String("op", op))
+	calendarID, err := urlParamInt(r, "id")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	imported, err := h.service.ImportHolidays(r.Context(), calendarID, http.MaxBytesReader(w, r.Body, maxICalSize))
+	if err != nil {
+		errorHandler(log, "failed to import holidays", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseImportHolidays{
+		Response: resp.OK(),
+		Imported: imported,
+	})
+}
+
+// максимальный размер загружаемого файла iCalendar
+const maxICalSize = 1 << 20
+
+// Вспомогательные функции
+
+// urlParamInt читает положительный целочисленный параметр пути
+func urlParamInt(r *http.Request, name string) (int, error) {
+	v, err := strconv.Atoi(chi.URLParam(r, name))
+	if err != nil || v <= 0 {
+		return 0, resp.BadRequest("invalid path parameter %s", name)
+	}
+	return v, nil
+}
+
+func urlParamPair(r *http.Request, first, second string) (int, int, error) {
+	a, err := urlParamInt(r, first)
+	if err != nil {
+		return 0, 0, err
+	}
+	b, err := urlParamInt(r, second)
+	if err != nil {
+		return 0, 0, err
+	}
+	return a, b, nil
+}
+
+// queryInt читает необязательный целочисленный параметр строки запроса, отсутствующий параметр - 0
+func queryInt(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return 0, resp.BadRequest("invalid query parameter %s", name)
+	}
+	return v, nil
+}
+
+// queryDeadlineWindow читает окно дедлайнов из параметров window, days, from и to (RFC 3339)
+func queryDeadlineWindow(r *http.Request) (RequestDeadlineWindow, error) {
+	q := r.URL.Query()
+	req := RequestDeadlineWindow{Window: q.Get("window")}
+
+	days, err := queryInt(r, "days")
+	if err != nil {
+		return req, err
+	}
+	req.Days = days
+
+	for name, dst := range map[string]**time.Time{"from": &req.From, "to": &req.To} {
+		raw := q.Get(name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return req, resp.BadRequest("invalid query parameter %s", name)
+		}
+		*dst = &t
+	}
+
+	if err := validate.Struct(req); err != nil {
+		return req, fmt.Errorf("validation error: %w", err)
+	}
+	return req, nil
+}
+
+// queryPage читает параметры страницы: limit, cursor, sort (с префиксом "-" - по убыванию) и total
+func queryPage(r *http.Request) (model.PageRequest, error) {
+	q := r.URL.Query()
+	page := model.PageRequest{Limit: model.DefaultPageLimit}
+
+	limit, err := queryInt(r, "limit")
+	if err != nil {
+		return page, err
+	}
+	if limit > model.MaxPageLimit {
+		return page, resp.BadRequest("invalid query parameter %s", "limit")
+	}
+	if limit > 0 {
+		page.Limit = limit
+	}
+
+	sort := q.Get("sort")
+	page.Sort, page.Desc = strings.TrimPrefix(sort, "-"), strings.HasPrefix(sort, "-")
+
+	page.After, err = pagination.Decode(q.Get("cursor"))
+	if err != nil {
+		return page, resp.BadRequest("invalid query parameter %s", "cursor")
+	}
+
+	if raw := q.Get("total"); raw != "" {
+		page.WithTotal, err = strconv.ParseBool(raw)
+		if err != nil {
+			return page, resp.BadRequest("invalid query parameter %s", "total")
+		}
+	}
+	return page, nil
+}
+
+// queryTaskList читает параметры списка задач: окно дедлайнов, фильтры status, text и q и параметры страницы.
+// Статусы передаются через запятую или повтором параметра. ORDER BY из q задаёт сортировку
+// и не сочетается с параметром sort.
+func queryTaskList(r *http.Request, filter *model.TaskFilter) (RequestDeadlineWindow, model.PageRequest, error) {
+	window, err := queryDeadlineWindow(r)
+	if err != nil {
+		return window, model.PageRequest{}, err
+	}
+	page, err := queryPage(r)
+	if err != nil {
+		return window, page, err
+	}
+
+	q := r.URL.Query()
+	for _, raw := range q["status"] {
+		for _, status := range strings.Split(raw, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, status)
+			}
+		}
+	}
+	filter.Text = strings.TrimSpace(q.Get("text"))
+
+	if raw := strings.TrimSpace(q.Get("q")); raw != "" {
+		userID, _ := auth.UserID(r.Context())
+		parsed, err := query.Parse(raw, query.Env{Now: time.Now(), UserID: userID})
+		if err != nil {
+			return window, page, err
+		}
+		if parsed.Sort != "" {
+			if page.Sort != "" {
+				return window, page, resp.BadRequest("query parameters %s and %s cannot be combined", "sort", "q ORDER BY")
+			}
+			page.Sort, page.Desc = parsed.Sort, parsed.Desc
+		}
+		filter.Query = &parsed
+	}
+	return window, page, nil
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"Tasks/internal/http-server/middleware/auth"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+
+// RequestView Query - условие на языке фильтров, Sort - поле сортировки, с префиксом "-" - по убыванию.
+// ProjectID открывает представление участникам проекта.
+type RequestView struct {
+	Name      string   `json:"name" validate:"required,max=255"`
+	Query     string   `json:"query" validate:"max=2000"`
+	Sort      string   `json:"sort"`
+	Columns   []string `json:"columns" validate:"max=20"`
+	Default   bool     `json:"default"`
+	ProjectID int      `json:"project_id"`
+}
+
+func (req RequestView) view(ownerID int) model.View {
+	return model.View{
+		OwnerID:   ownerID,
+		ProjectID: req.ProjectID,
+		Name:      req.Name,
+		Query:     req.Query,
+		Sort:      strings.TrimPrefix(req.Sort, "-"),
+		Desc:      strings.HasPrefix(req.Sort, "-"),
+		Columns:   req.Columns,
+		IsDefault: req.Default,
+	}
+}
+
+// Ответы
+type ResponseNewView struct {
+	resp.Response
+	ViewID int `json:"view_id"`
+}
+
+type ResponseView struct {
+	View model.View `json:"view"`
+	resp.Response
+}
+
+type ResponseViews struct {
+	Views []model.View `json:"views"`
+	resp.Response
+}
+
+// ResponseViewTasks задачи представления; колонки для отображения - в view.Columns
+type ResponseViewTasks struct {
+	View       model.View   `json:"view"`
+	Tasks      []model.Task `json:"tasks"`
+	NextCursor string       `json:"next_cursor,omitempty"`
+	Total      *int         `json:"total,omitempty"`
+	resp.Response
+}
+
+// callerID аутентифицированный пользователь запроса
+func callerID(r *http.Request) (int, error) {
+	userID, ok := auth.UserID(r.Context())
+	if !ok {
+		return 0, model.Unauthorized("authentication required")
+	}
+	return userID, nil
+}
+
+// Обработчики
+
+// CreateViewV1 Saves a named task filter of the authenticated user
+func (h *Handler) CreateViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := callerID(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestView](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	viewID, err := h.service.CreateView(r.Context(), req.view(userID))
+	if err != nil {
+		errorHandler(log, "failed to create view", err, w, r)
+		return
+	}
+	log.Info("view created successfully", slog.Int("view_id", viewID))
+	render.JSON(w, r, ResponseNewView{
+		Response: resp.OK(),
+		ViewID:   viewID,
+	})
+}
+
+// ViewsV1 Returns the user's own views and the views shared with their projects, the default view first
+func (h *Handler) ViewsV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ViewsV1"
+	log := h.log.With(slog.String("op", op))
+	userID, err := callerID(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	views, err := h.service.Views(r.Context(), userID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve views", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseViews{
+		Views:    views,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) GetViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	view, err := h.service.View(r.Context(), userID, viewID)
+	if err != nil {
+		errorHandler(log, "failed to get view", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseView{
+		View:     view,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) UpdateViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.UpdateViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestView](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	view := req.view(userID)
+	view.ID = viewID
+	if err := h.service.UpdateView(r.Context(), userID, view); err != nil {
+		errorHandler(log, "failed to update view", err, w, r)
+		return
+	}
+	log.Info("view updated successfully", slog.Int("view_id", viewID))
+	render.JSON(w, r, resp.OK())
+}
+
+func (h *Handler) DeleteViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeleteViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.DeleteView(r.Context(), userID, viewID); err != nil {
+		errorHandler(log, "failed to delete view", err, w, r)
+		return
+	}
+	log.Info("view deleted successfully", slog.Int("view_id", viewID))
+	render.JSON(w, r, resp.OK())
+}
+
+// ViewTasksV1 Runs the saved view for the authenticated user. The order is defined by the view,
+// so the sort parameter is not accepted.
+func (h *Handler) ViewTasksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ViewTasksV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	page, err := queryPage(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if page.Sort != "" {
+		errorHandler(log, invalid, resp.BadRequest("invalid query parameter %s", "sort"), w, r)
+		return
+	}
+	view, tasks, err := h.service.ViewTasks(r.Context(), userID, viewID, page)
+	if err != nil {
+		errorHandler(log, "failed to retrieve view tasks", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseViewTasks{
+		View:       view,
+		Tasks:      tasks.Items,
+		NextCursor: pagination.Encode(tasks.Next),
+		Total:      tasks.Total,
+		Response:   resp.OK(),
+	})
+}
+
+func (h *Handler) SubscribeViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.SubscribeViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.SubscribeView(r.Context(), userID, viewID); err != nil {
+		errorHandler(log, "failed to subscribe to view", err, w, r)
+		return
+	}
+	log.Info("subscribed to view", slog.Int("view_id", viewID), slog.Int("user_id", userID))
+	render.JSON(w, r, resp.OK())
+}
+
+func (h *Handler) UnsubscribeViewV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.UnsubscribeViewV1"
+	log := h.log.With(slog.String("op", op))
+	userID, viewID, err := viewParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.UnsubscribeView(r.Context(), userID, viewID); err != nil {
+		errorHandler(log, "failed to unsubscribe from view", err, w, r)
+		return
+	}
+	log.Info("unsubscribed from view", slog.Int("view_id", viewID), slog.Int("user_id", userID))
+	render.JSON(w, r, resp.OK())
+}
+
+// viewParams аутентифицированный пользователь и ID представления из пути
+func viewParams(r *http.Request) (int, int, error) {
+	userID, err := callerID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	viewID, err := urlParamInt(r, "id")
+	return userID, viewID, err
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/pagination"
+	"Tasks/internal/model"
+)
+
+// Поступающие запросы
+
+// RequestWebhook Events - типы событий задач, пустой список - все события. Secret - ключ подписи доставок,
+// при создании без него ключ создаётся случайным, при изменении без него не меняется.
+// Active, по умолчанию true, включает отключённый после неудач вебхук или отключает его вручную:
+// PUT заменяет вебхук целиком.
+type RequestWebhook struct {
+	URL    string   `json:"url" validate:"required,max=2000"`
+	Events []string `json:"events" validate:"max=10"`
+	Secret string   `json:"secret" validate:"omitempty,min=16,max=255"`
+	Active *bool    `json:"active"`
+}
+
+func (req RequestWebhook) webhook() model.Webhook {
+	return model.Webhook{
+		URL:    req.URL,
+		Events: req.Events,
+		Secret: req.Secret,
+		Active: req.Active == nil || *req.Active,
+	}
+}
+
+// Ответы
+
+// ResponseNewWebhook ключ подписи отдаётся только при создании вебхука
+type ResponseNewWebhook struct {
+	resp.Response
+	WebhookID int    `json:"webhook_id"`
+	Secret    string `json:"secret"`
+}
+
+type ResponseWebhook struct {
+	Webhook model.Webhook `json:"webhook"`
+	resp.Response
+}
+
+type ResponseWebhooks struct {
+	Webhooks []model.Webhook `json:"webhooks"`
+	resp.Response
+}
+
+type ResponseWebhookDeliveries struct {
+	Deliveries []model.WebhookDelivery `json:"deliveries"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	Total      *int                    `json:"total,omitempty"`
+	resp.Response
+}
+
+type ResponseReplayDelivery struct {
+	resp.Response
+	DeliveryID int `json:"delivery_id"`
+}
+
+// Обработчики
+
+// CreateWebhookV1 Subscribes an external system to task events of the project
+func (h *Handler) CreateWebhookV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.CreateWebhookV1"
+	log := h.log.With(slog.String("op", op))
+	userID, projectID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestWebhook](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	webhook := req.webhook()
+	webhook.ProjectID = projectID
+	webhook, err = h.service.CreateWebhook(r.Context(), userID, webhook)
+	if err != nil {
+		errorHandler(log, "failed to create webhook", err, w, r)
+		return
+	}
+	log.Info("webhook created successfully", slog.Int("webhook_id", webhook.ID))
+	render.JSON(w, r, ResponseNewWebhook{
+		Response:  resp.OK(),
+		WebhookID: webhook.ID,
+		Secret:    webhook.Secret,
+	})
+}
+
+func (h *Handler) WebhooksV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.WebhooksV1"
+	log := h.log.With(slog.String("op", op))
+	userID, projectID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	webhooks, err := h.service.Webhooks(r.Context(), userID, projectID)
+	if err != nil {
+		errorHandler(log, "failed to retrieve webhooks", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseWebhooks{
+		Webhooks: webhooks,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) GetWebhookV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.GetWebhookV1"
+	log := h.log.With(slog.String("op", op))
+	userID, webhookID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	webhook, err := h.service.Webhook(r.Context(), userID, webhookID)
+	if err != nil {
+		errorHandler(log, "failed to get webhook", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseWebhook{
+		Webhook:  webhook,
+		Response: resp.OK(),
+	})
+}
+
+func (h *Handler) UpdateWebhookV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.UpdateWebhookV1"
+	log := h.log.With(slog.String("op", op))
+	userID, webhookID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	req, err := decodeAndValidate[RequestWebhook](r, h.log)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	webhook := req.webhook()
+	webhook.ID = webhookID
+	if err := h.service.UpdateWebhook(r.Context(), userID, webhook); err != nil {
+		errorHandler(log, "failed to update webhook", err, w, r)
+		return
+	}
+	log.Info("webhook updated successfully", slog.Int("webhook_id", webhookID))
+	render.JSON(w, r, resp.OK())
+}
+
+func (h *Handler) DeleteWebhookV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.DeleteWebhookV1"
+	log := h.log.With(slog.String("op", op))
+	userID, webhookID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	if err := h.service.DeleteWebhook(r.Context(), userID, webhookID); err != nil {
+		errorHandler(log, "failed to delete webhook", err, w, r)
+		return
+	}
+	log.Info("webhook deleted successfully", slog.Int("webhook_id", webhookID))
+	render.JSON(w, r, resp.OK())
+}
+
+// WebhookDeliveriesV1 Returns the delivery log of the webhook, newest first
+func (h *Handler) WebhookDeliveriesV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.WebhookDeliveriesV1"
+	log := h.log.With(slog.String("op", op))
+	userID, webhookID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	page, err := queryPage(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	deliveries, err := h.service.WebhookDeliveries(r.Context(), userID, webhookID, page)
+	if err != nil {
+		errorHandler(log, "failed to retrieve webhook deliveries", err, w, r)
+		return
+	}
+	render.JSON(w, r, ResponseWebhookDeliveries{
+		Deliveries: deliveries.Items,
+		NextCursor: pagination.Encode(deliveries.Next),
+		Total:      deliveries.Total,
+		Response:   resp.OK(),
+	})
+}
+
+// ReplayWebhookDeliveryV1 Sends the event of the delivery to the webhook again as a new delivery
+func (h *Handler) ReplayWebhookDeliveryV1(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.ReplayWebhookDeliveryV1"
+	log := h.log.With(slog.String("op", op))
+	userID, webhookID, err := webhookParams(r)
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	deliveryID, err := urlParamInt(r, "deliveryID")
+	if err != nil {
+		errorHandler(log, invalid, err, w, r)
+		return
+	}
+	replayID, err := h.service.ReplayWebhookDelivery(r.Context(), userID, webhookID, deliveryID)
+	if err != nil {
+		errorHandler(log, "failed to replay webhook delivery", err, w, r)
+		return
+	}
+	log.Info("webhook delivery replayed", slog.Int("delivery_id", deliveryID), slog.Int("replay_id", replayID))
+	render.JSON(w, r, ResponseReplayDelivery{
+		Response:   resp.OK(),
+		DeliveryID: replayID,
+	})
+}
+
+// webhookParams аутентифицированный пользователь и ID из пути: проекта или вебхука
+func webhookParams(r *http.Request) (int, int, error) {
+	userID, err := callerID(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	id, err := urlParamInt(r, "id")
+	return userID, id, err
+}
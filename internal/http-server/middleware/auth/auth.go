@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/jwt"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// ProfileLanguage возвращает язык из профиля пользователя
+type ProfileLanguage func(ctx context.Context, userID int) string
+
+// New аутентифицирует вызывающего по заголовку Authorization: Bearer <JWT>.
+// Запрос без заголовка пропускается анонимным, обработчики сами решают, нужен ли им пользователь.
+// Недействительный токен отклоняется с 401. Если язык не выбран по Accept-Language,
+// ответ строится на языке из профиля пользователя.
+func New(log *slog.Logger, secret string, profile ProfileLanguage) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/auth"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get("Authorization")
+			if header == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID, err := Authenticate(header, secret)
+			if err != nil {
+				log.Info("rejected request with invalid token",
+					slog.String("request_id", middleware.GetReqID(r.Context())), sl.Err(err))
+				status, body := resp.FromError(model.Unauthorized("invalid or expired token"), i18n.FromContext(r.Context()))
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				render.Status(r, status)
+				render.JSON(w, r, body)
+				return
+			}
+
+			ctx := WithUserID(r.Context(), userID)
+			if _, chosen := i18n.Match(r.Header.Get("Accept-Language")); !chosen && profile != nil {
+				if lang := profile(ctx, userID); i18n.Supported(lang) {
+					ctx = i18n.WithLanguage(ctx, lang)
+					w.Header().Set("Content-Language", lang)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// ErrNotBearer значение заголовка Authorization - не токен Bearer
+var ErrNotBearer = errors.New("authorization is not a bearer token")
+
+// Authenticate возвращает ID пользователя из значения "Bearer <JWT>" заголовка Authorization
+// или метаданных authorization gRPC
+func Authenticate(authorization string, secret string) (int, error) {
+	token, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok {
+		return 0, ErrNotBearer
+	}
+	return jwt.ParseToken(strings.TrimSpace(token), secret)
+}
+
+type ctxKey struct{}
+
+// WithUserID сохраняет ID аутентифицированного пользователя в контексте запроса
+func WithUserID(ctx context.Context, userID int) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserID возвращает ID аутентифицированного пользователя, false - запрос анонимный
+func UserID(ctx context.Context) (int, bool) {
+	userID, ok := ctx.Value(ctxKey{}).(int)
+	return userID, ok
+}
@@ -0,0 +1,187 @@
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/interfaces"
+	resp "Tasks/internal/lib/api/response"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const (
+	Header         = "Idempotency-Key"
+	ReplayedHeader = "Idempotent-Replayed"
+
+	maxKeyLength = 255
+	// processingTTL срок, на который ключ занимается выполняющимся запросом.
+	// Если экземпляр упал, не дописав ответ, через это время повтор выполнится заново.
+	processingTTL = time.Minute
+	// maxStoredBody ответы больше этого размера не сохраняются
+	maxStoredBody = 1 << 20
+	// maxRequestBody тело запроса с ключом читается в память целиком, поэтому его размер ограничен
+	maxRequestBody = 1 << 20
+)
+
+// New делает изменяющие запросы (POST, PUT, PATCH, DELETE) с заголовком Idempotency-Key идемпотентными.
+// Первый ответ на ключ сохраняется на ttl и отдаётся на повторы с заголовком Idempotent-Replayed.
+// Ключи разделены по пользователям: у анонимных запросов своё пространство ключей.
+// Повтор с другим методом, путём или телом отклоняется с 422, повтор во время выполнения первого
+// запроса - с 409. Ответы 5xx не сохраняются, такой запрос можно повторить с тем же ключом.
+// При недоступности хранилища запрос выполняется без защиты от повторов.
+func New(log *slog.Logger, store interfaces.IdempotencyRepository, ttl time.Duration) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		log := log.With(
+			slog.String("component", "middleware/idempotency"),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if key == "" || !mutating(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			log := log.With(slog.String("request_id", middleware.GetReqID(r.Context())))
+			if len(key) > maxKeyLength || !printable(key) {
+				fail(w, r, resp.BadRequest("invalid %s header", Header))
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+			if err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					fail(w, r, resp.BadRequest("request body is too large"))
+					return
+				}
+				fail(w, r, resp.BadRequest("failed to read request body"))
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			ctx := r.Context()
+			scoped := scope(r) + ":" + key
+			fingerprint := fingerprint(r, body)
+
+			reserved, err := store.ReserveIdempotencyKey(ctx, scoped, fingerprint, processingTTL)
+			if err != nil {
+				log.Error("failed to reserve idempotency key", sl.Err(err))
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !reserved {
+				stored, found, err := store.IdempotentResponse(ctx, scoped)
+				switch {
+				case err != nil:
+					log.Error("failed to get idempotent response", sl.Err(err))
+					next.ServeHTTP(w, r)
+				case !found:
+					// ключ истёк между двумя обращениями к хранилищу
+					fail(w, r, model.Conflict("a request with this idempotency key is still being processed"))
+				case stored.Fingerprint != fingerprint:
+					fail(w, r, model.Invalid("idempotency key was already used for a different request"))
+				case stored.Status == 0:
+					fail(w, r, model.Conflict("a request with this idempotency key is still being processed"))
+				default:
+					log.Info("replaying idempotent response", slog.Int("status", stored.Status))
+					replay(w, stored)
+				}
+				return
+			}
+
+			// ответ сохраняется и после обрыва соединения: клиент повторит запрос с тем же ключом
+			storeCtx := context.WithoutCancel(ctx)
+			var buf bytes.Buffer
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			ww.Tee(&buf)
+			completed := false
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				// паника или 5xx: освобождаем ключ, чтобы повтор выполнился заново
+				if !completed || status >= http.StatusInternalServerError || buf.Len() > maxStoredBody {
+					if err := store.ReleaseIdempotencyKey(storeCtx, scoped); err != nil {
+						log.Error("failed to release idempotency key", sl.Err(err))
+					}
+					return
+				}
+				err := store.SaveIdempotentResponse(storeCtx, scoped, model.IdempotentResponse{
+					Fingerprint: fingerprint,
+					Status:      status,
+					Header:      w.Header().Clone(),
+					Body:        buf.Bytes(),
+				}, ttl)
+				if err != nil {
+					log.Error("failed to save idempotent response", sl.Err(err))
+				}
+			}()
+			next.ServeHTTP(ww, r)
+			completed = true
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+func mutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func printable(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// scope пространство ключей пользователя
+func scope(r *http.Request) string {
+	if userID, ok := auth.UserID(r.Context()); ok {
+		return strconv.Itoa(userID)
+	}
+	return "anonymous"
+}
+
+func fingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func replay(w http.ResponseWriter, stored model.IdempotentResponse) {
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	_, _ = w.Write(stored.Body)
+}
+
+func fail(w http.ResponseWriter, r *http.Request, err error) {
+	status, body := resp.FromError(err, i18n.FromContext(r.Context()))
+	render.Status(r, status)
+	render.JSON(w, r, body)
+}
@@ -0,0 +1,234 @@
+package idempotency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/http-server/middleware/auth"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+)
+
+// memoryStore хранилище ключей в памяти, TTL не учитывается. Как и Redis, не выполняет
+// сохранение и освобождение ключа по отменённому контексту.
+type memoryStore struct {
+	mu        sync.Mutex
+	responses map[string]model.IdempotentResponse
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{responses: make(map[string]model.IdempotentResponse)}
+}
+
+func (s *memoryStore) ReserveIdempotencyKey(_ context.Context, key string, fingerprint string, _ time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.responses[key]; ok {
+		return false, nil
+	}
+	s.responses[key] = model.IdempotentResponse{Fingerprint: fingerprint}
+	return true, nil
+}
+
+func (s *memoryStore) IdempotentResponse(_ context.Context, key string) (model.IdempotentResponse, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	response, ok := s.responses[key]
+	return response, ok, nil
+}
+
+func (s *memoryStore) SaveIdempotentResponse(ctx context.Context, key string, response model.IdempotentResponse, _ time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses[key] = response
+	return nil
+}
+
+func (s *memoryStore) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.responses, key)
+	return nil
+}
+
+type request struct {
+	method string
+	path   string
+	key    string
+	body   string
+	userID int
+}
+
+func TestIdempotency(t *testing.T) {
+	first := request{method: http.MethodPost, path: "/task", key: "k1", body: `{"title":"a"}`}
+
+	tests := []struct {
+		name       string
+		status     int
+		retry      request
+		wantStatus int
+		wantCalls  int
+		replayed   bool
+	}{
+		{name: "retry replays the first response", status: http.StatusOK, retry: first, wantStatus: http.StatusOK, wantCalls: 1, replayed: true},
+		{name: "client errors are replayed too", status: http.StatusBadRequest, retry: first, wantStatus: http.StatusBadRequest, wantCalls: 1, replayed: true},
+		{name: "server errors are not stored", status: http.StatusInternalServerError, retry: first, wantStatus: http.StatusInternalServerError, wantCalls: 2},
+		{
+			name:       "different body",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", key: "k1", body: `{"title":"b"}`},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "different path",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/adduser", key: "k1", body: `{"title":"a"}`},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCalls:  1,
+		},
+		{
+			name:       "keys of another user do not clash",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", key: "k1", body: `{"title":"a"}`, userID: 7},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "new key",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", key: "k2", body: `{"title":"a"}`},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "no key",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", body: `{"title":"a"}`},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "reads are not cached",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodGet, path: "/task", key: "k1"},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "invalid key",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", key: strings.Repeat("k", 256), body: `{"title":"a"}`},
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+		{
+			name:       "body too large",
+			status:     http.StatusOK,
+			retry:      request{method: http.MethodPost, path: "/task", key: "k2", body: strings.Repeat("a", maxRequestBody+1)},
+			wantStatus: http.StatusBadRequest,
+			wantCalls:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"task_id":` + strconv.Itoa(calls) + `}`))
+			})
+			mw := New(slogdiscard.NewDiscardLogger(), newMemoryStore(), time.Hour)(handler)
+
+			rr := serve(mw, first)
+			require.Equal(t, tt.status, rr.Code)
+			require.Equal(t, `{"task_id":1}`, rr.Body.String())
+
+			rr = serve(mw, tt.retry)
+			require.Equal(t, tt.wantStatus, rr.Code)
+			require.Equal(t, tt.wantCalls, calls)
+			if tt.replayed {
+				require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+				require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				require.Equal(t, `{"task_id":1}`, rr.Body.String())
+			} else {
+				require.Empty(t, rr.Header().Get(ReplayedHeader))
+			}
+		})
+	}
+}
+
+func TestIdempotency_InProgress(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusOK)
+	})
+	mw := New(slogdiscard.NewDiscardLogger(), newMemoryStore(), time.Hour)(handler)
+	req := request{method: http.MethodPost, path: "/task", key: "k1", body: `{}`}
+
+	done := make(chan int)
+	go func() { done <- serve(mw, req).Code }()
+	<-started
+
+	rr := serve(mw, req)
+	require.Equal(t, http.StatusConflict, rr.Code)
+
+	close(release)
+	require.Equal(t, http.StatusOK, <-done)
+}
+
+// TestIdempotency_ClientGone ответ сохраняется, даже если клиент оборвал соединение до его получения
+func TestIdempotency_ClientGone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			cancel()
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	mw := New(slogdiscard.NewDiscardLogger(), newMemoryStore(), time.Hour)(handler)
+	req := request{method: http.MethodPost, path: "/task", key: "k1", body: `{}`}
+
+	r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body)).WithContext(ctx)
+	r.Header.Set(Header, req.key)
+	mw.ServeHTTP(httptest.NewRecorder(), r)
+
+	rr := serve(mw, req)
+	require.Equal(t, http.StatusCreated, rr.Code)
+	require.Equal(t, "true", rr.Header().Get(ReplayedHeader))
+	require.Equal(t, 1, calls)
+}
+
+func serve(h http.Handler, req request) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(req.method, req.path, strings.NewReader(req.body))
+	if req.key != "" {
+		r.Header.Set(Header, req.key)
+	}
+	if req.userID != 0 {
+		r = r.WithContext(auth.WithUserID(r.Context(), req.userID))
+	}
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, r)
+	return rr
+}
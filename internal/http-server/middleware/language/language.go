@@ -0,0 +1,24 @@
+package language
+
+import (
+	"net/http"
+
+	"Tasks/internal/lib/i18n"
+)
+
+// New выбирает язык ответа по заголовку Accept-Language.
+// Если заголовка нет или язык не поддерживается, ответы остаются на языке по умолчанию.
+func New() func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Language")
+			if lang, ok := i18n.Match(r.Header.Get("Accept-Language")); ok {
+				r = r.WithContext(i18n.WithLanguage(r.Context(), lang))
+			}
+			w.Header().Set("Content-Language", i18n.FromContext(r.Context()))
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
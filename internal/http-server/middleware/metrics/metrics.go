@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"Tasks/internal/metrics"
+)
+
+// unmatched метка запросов, не подошедших ни к одному маршруту: путь в метке дал бы неограниченное число рядов
+const unmatched = "unmatched"
+
+// New считает запросы и их длительность по шаблону маршрута chi, например /v1/tasks/{id}
+func New(m *metrics.Metrics) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			start := time.Now()
+			defer func() {
+				// шаблон известен только после маршрутизации, то есть после next
+				route := unmatched
+				if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+					route = rctx.RoutePattern()
+				}
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+				labels := []string{route, r.Method, strconv.Itoa(status)}
+				m.HTTPRequests.WithLabelValues(labels...).Inc()
+				m.HTTPDuration.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+			}()
+
+			next.ServeHTTP(ww, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/metrics"
+)
+
+func TestNew(t *testing.T) {
+	m := metrics.New()
+	router := chi.NewRouter()
+	router.Use(New(m))
+	router.Route("/v1/tasks", func(r chi.Router) {
+		r.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "id") == "404" {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write([]byte("{}"))
+		})
+	})
+
+	for _, path := range []string{"/v1/tasks/1", "/v1/tasks/2", "/v1/tasks/404", "/unknown/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+
+	// ID задачи не попадает в метки: запросы разных задач считаются одним рядом
+	require.Contains(t, string(body), `tasks_http_requests_total{method="GET",route="/v1/tasks/{id}",status="200"} 2`)
+	require.Contains(t, string(body), `tasks_http_requests_total{method="GET",route="/v1/tasks/{id}",status="404"} 1`)
+	require.Contains(t, string(body), `tasks_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	require.Contains(t, string(body), `tasks_http_request_duration_seconds_count{method="GET",route="/v1/tasks/{id}",status="200"} 2`)
+	require.NotContains(t, string(body), "/v1/tasks/1")
+}
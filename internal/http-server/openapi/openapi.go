@@ -0,0 +1,381 @@
+// Package openapi собирает документ OpenAPI 3 из описаний маршрутов и Go-типов запросов и ответов.
+// Схемы строятся по тем же json- и validate-тегам, по которым обработчики разбирают запросы,
+// поэтому описание не расходится со структурами.
+package openapi
+
+import (
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"Tasks/internal/lib/validation"
+	"Tasks/internal/model"
+)
+
+const Version = "3.1.0"
+
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem операции пути по HTTP-методам в нижнем регистре
+type PathItem map[string]*Operation
+
+type Operation struct {
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Tags        []string              `json:"tags,omitempty"`
+	Deprecated  bool                  `json:"deprecated,omitempty"`
+	Security    []map[string][]string `json:"security,omitempty"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// BearerAuth схема аутентификации по JWT в заголовке Authorization
+const BearerAuth = "bearerAuth"
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *int               `json:"minimum,omitempty"`
+	Maximum              *int               `json:"maximum,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// Route описание маршрута. Request и Response - значения типов тела запроса и ответа, nil - без тела.
+// Параметры пути вида {id} добавляются автоматически как целые числа.
+type Route struct {
+	Method      string
+	Path        string
+	Summary     string
+	Description string
+	Tag         string
+	Deprecated  bool
+	// Auth операция требует токен доступа
+	Auth    bool
+	Query   []Parameter
+	Headers []Parameter
+	Request any
+	// RequestContentType тип тела запроса, по умолчанию application/json
+	RequestContentType string
+	Response           any
+	// ResponseContentType тип успешного ответа, по умолчанию application/json
+	ResponseContentType string
+	// Responses дополнительные ответы по кодам статуса, например 304 или 412
+	Responses map[string]Response
+}
+
+// ErrorResponses ответы с ошибками, общие для всех операций
+var ErrorResponses = map[string]string{
+	"400": "Некорректный запрос",
+	"401": "Токен доступа недействителен или отсутствует",
+	"404": "Ресурс не найден",
+	"409": "Конфликт с текущим состоянием ресурса",
+	"422": "Данные не прошли проверку",
+	"500": "Внутренняя ошибка",
+}
+
+// Builder собирает документ, регистрируя схемы типов в components
+type Builder struct {
+	doc         *Document
+	errorSchema *Schema
+}
+
+// New создаёт документ. errorBody - значение типа тела ответа с ошибкой.
+func New(info Info, errorBody any) *Builder {
+	b := &Builder{doc: &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]*PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}}
+	b.errorSchema = b.Schema(errorBody)
+	return b
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]+)\}`)
+
+// Add добавляет операцию по описанию маршрута
+func (b *Builder) Add(r Route) {
+	op := &Operation{
+		Summary:     r.Summary,
+		Description: r.Description,
+		Deprecated:  r.Deprecated,
+		Responses:   make(map[string]Response),
+	}
+	if r.Tag != "" {
+		op.Tags = []string{r.Tag}
+	}
+	if r.Auth {
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+	}
+	for _, m := range pathParam.FindAllStringSubmatch(r.Path, -1) {
+		op.Parameters = append(op.Parameters, Parameter{Name: m[1], In: "path", Required: true, Schema: &Schema{Type: "integer"}})
+	}
+	for _, p := range r.Query {
+		p.In = "query"
+		op.Parameters = append(op.Parameters, p)
+	}
+	for _, p := range r.Headers {
+		p.In = "header"
+		op.Parameters = append(op.Parameters, p)
+	}
+
+	if r.Request != nil {
+		contentType := r.RequestContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{contentType: {Schema: b.Schema(r.Request)}}}
+	}
+
+	success := Response{Description: "Успешный ответ"}
+	if r.Response != nil {
+		contentType := r.ResponseContentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		success.Content = map[string]MediaType{contentType: {Schema: b.Schema(r.Response)}}
+	}
+	op.Responses["200"] = success
+	for status, resp := range r.Responses {
+		op.Responses[status] = resp
+	}
+	for status, description := range ErrorResponses {
+		op.Responses[status] = Response{
+			Description: description,
+			Content:     map[string]MediaType{"application/json": {Schema: b.errorSchema}},
+		}
+	}
+
+	item, ok := b.doc.Paths[r.Path]
+	if !ok {
+		item = &PathItem{}
+		b.doc.Paths[r.Path] = item
+	}
+	(*item)[strings.ToLower(r.Method)] = op
+}
+
+// Has сообщает, описана ли операция
+func (d *Document) Has(method, path string) bool {
+	item, ok := d.Paths[path]
+	if !ok {
+		return false
+	}
+	_, ok = (*item)[strings.ToLower(method)]
+	return ok
+}
+
+func (b *Builder) Document() *Document {
+	return b.doc
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Schema возвращает схему значения v. Именованные структуры регистрируются в components
+// под именем "пакет.Тип" и возвращаются ссылкой.
+func (b *Builder) Schema(v any) *Schema {
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return b.schemaOf(reflect.TypeOf(v))
+}
+
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == durationType:
+		return &Schema{Type: "integer", Format: "int64", Description: "длительность в наносекундах"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		name := componentName(t)
+		if _, ok := b.doc.Components.Schemas[name]; !ok {
+			// заглушка защищает от бесконечной рекурсии на ссылающихся на себя типах
+			b.doc.Components.Schemas[name] = &Schema{}
+			*b.doc.Components.Schemas[name] = *b.structSchema(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+func componentName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return pkg + "." + t.Name()
+}
+
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	b.addFields(s, t)
+	return s
+}
+
+// addFields добавляет поля структуры в схему. Встроенные структуры без json-тега
+// раскрываются, как это делает encoding/json.
+func (b *Builder) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				b.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		fs := b.schemaOf(f.Type)
+		required := applyRules(fs, f.Tag.Get("validate"))
+		s.Properties[name] = fs
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// applyRules переносит правила validator в схему. Возвращает true для обязательного поля.
+func applyRules(s *Schema, rules string) bool {
+	required := false
+	list := strings.Split(rules, ",")
+	// правила после dive относятся к элементам списка
+	for i, rule := range list {
+		if rule == "dive" {
+			if s.Items != nil {
+				applyRules(s.Items, strings.Join(list[i+1:], ","))
+			}
+			list = list[:i]
+			break
+		}
+	}
+	for _, rule := range list {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "required":
+			required = true
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case validation.TaskStatus:
+			s.Enum = model.TaskStatuses
+		case validation.Priority:
+			s.Enum = model.Priorities
+		case validation.Future:
+			s.Description = "время в будущем"
+		case "max", "min":
+			n, err := strconv.Atoi(param)
+			if err != nil || s.Ref != "" {
+				continue
+			}
+			switch {
+			case s.Type == "string" && name == "max":
+				s.MaxLength = &n
+			case s.Type == "integer" && name == "max":
+				s.Maximum = &n
+			case s.Type == "integer" && name == "min":
+				s.Minimum = &n
+			}
+		}
+	}
+	return required
+}
@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	Status string `json:"status"`
+}
+
+type testRequest struct {
+	testBase
+	Name     string    `json:"name" validate:"required,max=100"`
+	Status   string    `json:"task_status" validate:"required,task_status"`
+	Language string    `json:"language" validate:"oneof=en ru"`
+	Deadline time.Time `json:"deadline" validate:"future"`
+	Users    []int     `json:"users" validate:"dive,min=1"`
+	Internal string    `json:"-"`
+}
+
+func TestBuilder_Add(t *testing.T) {
+	b := New(Info{Title: "test", Version: "1"}, testBase{})
+	b.Add(Route{
+		Method:     http.MethodPut,
+		Path:       "/v1/tasks/{id}/assignees/{userID}",
+		Deprecated: true,
+		Query:      []Parameter{{Name: "days", Schema: &Schema{Type: "integer"}}},
+		Headers:    []Parameter{{Name: "Idempotency-Key", Schema: &Schema{Type: "string"}}},
+		Request:    testRequest{},
+		Response:   testBase{},
+	})
+	doc := b.Document()
+
+	require.True(t, doc.Has(http.MethodPut, "/v1/tasks/{id}/assignees/{userID}"))
+	require.False(t, doc.Has(http.MethodGet, "/v1/tasks/{id}/assignees/{userID}"))
+
+	op := (*doc.Paths["/v1/tasks/{id}/assignees/{userID}"])["put"]
+	require.True(t, op.Deprecated)
+	require.Len(t, op.Parameters, 4)
+	require.Equal(t, "id", op.Parameters[0].Name)
+	require.Equal(t, "path", op.Parameters[0].In)
+	require.Equal(t, "query", op.Parameters[2].In)
+	require.Equal(t, "header", op.Parameters[3].In)
+	for _, status := range []string{"200", "400", "404", "409", "422", "500"} {
+		require.Contains(t, op.Responses, status)
+	}
+	require.Equal(t, "#/components/schemas/openapi.testRequest", op.RequestBody.Content["application/json"].Schema.Ref)
+
+	s := doc.Components.Schemas["openapi.testRequest"]
+	require.NotNil(t, s)
+	require.ElementsMatch(t, []string{"name", "task_status"}, s.Required)
+	require.Contains(t, s.Properties, "status", "встроенная структура раскрывается")
+	require.NotContains(t, s.Properties, "Internal")
+	require.Equal(t, 100, *s.Properties["name"].MaxLength)
+	require.Equal(t, []string{"en", "ru"}, s.Properties["language"].Enum)
+	require.NotEmpty(t, s.Properties["task_status"].Enum)
+	require.Equal(t, "date-time", s.Properties["deadline"].Format)
+	require.Equal(t, 1, *s.Properties["users"].Items.Minimum)
+}
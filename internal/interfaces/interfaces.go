@@ -2,32 +2,161 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"Tasks/internal/model"
 )
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=StorageRepository --output=../service/mocks
 type StorageRepository interface {
-	CreateNewTask(ctx context.Context, task model.Task) (int, error)
-	GetAllUsersWorkTask(ctx context.Context, taskID int) ([]model.User, error)
-	GetAllTasks(ctx context.Context, userID int) ([]model.Task, error)
+	CreateNewTask(ctx context.Context, task model.Task) (model.Task, error)
+	GetAllUsersWorkTask(ctx context.Context, taskID int, page model.PageRequest) (model.Page[model.User], error)
 	TaskShortDeadline(ctx context.Context, userID int) ([]model.Task, error)
-	TaskUpdateStatus(ctx context.Context, newStatus string, taskID int) error
+	// TaskUpdateStatus version = 0 - без проверки версии, иначе при несовпадении model.ErrPreconditionFailed
+	TaskUpdateStatus(ctx context.Context, newStatus string, taskID int, version int) (model.Task, error)
 	AddNewUserTask(ctx context.Context, userID int, taskID int) error
-	DeleteTask(ctx context.Context, taskID int) error
+	// DeleteTask возвращает проект и исполнителей удалённой задачи
+	DeleteTask(ctx context.Context, taskID int, version int) (int, []int, error)
 	RemoveUserFromTask(ctx context.Context, userID int, taskID int) error
 	TaskByID(ctx context.Context, taskID int) (model.Task, error)
 	UserByID(ctx context.Context, taskID int) ([]int, error)
+	// BatchTasks применяет изменения к задачам в одной транзакции, каждая задача - в своей точке сохранения:
+	// ошибка одной задачи отменяет только её изменения
+	BatchTasks(ctx context.Context, batch model.TaskBatch) ([]model.TaskBatchResult, error)
+
+	CreateSprint(ctx context.Context, sprint model.Sprint) (int, error)
+	SprintByID(ctx context.Context, sprintID int) (model.Sprint, error)
+	AddTaskToSprint(ctx context.Context, sprintID int, taskID int) error
+	StartSprint(ctx context.Context, sprintID int) error
+	CompleteSprint(ctx context.Context, sprintID int, nextSprintID int) ([]int, error)
+	CreateMilestone(ctx context.Context, milestone model.Milestone) (int, error)
+	SetTaskMilestone(ctx context.Context, taskID int, milestoneID int) error
+	TaskMilestoneOverrun(ctx context.Context, userID int) ([]model.Task, error)
+
+	TasksDueBefore(ctx context.Context, before time.Time) ([]model.Task, error)
+	ClaimReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) (bool, error)
+	ReleaseReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) error
+
+	CreateProject(ctx context.Context, project model.Project) (int, error)
+	ProjectByID(ctx context.Context, projectID int) (model.Project, error)
+	CreateEscalationRule(ctx context.Context, rule model.EscalationRule) (int, error)
+	EscalationRules(ctx context.Context, projectID int) ([]model.EscalationRule, error)
+	DeleteEscalationRule(ctx context.Context, ruleID int) error
+	TasksToEscalate(ctx context.Context, rule model.EscalationRule, deadlineBefore time.Time) ([]model.Task, error)
+	EscalateTask(ctx context.Context, rule model.EscalationRule, task model.Task, details string) (bool, error)
+	ClaimEscalationNotices(ctx context.Context) ([]model.EscalationNotice, error)
+	ReleaseEscalationNotice(ctx context.Context, notice model.EscalationNotice) error
+	TaskHistory(ctx context.Context, taskID int) ([]model.TaskHistory, error)
+
+	ListTasks(ctx context.Context, filter model.TaskFilter, page model.PageRequest) (model.Page[model.Task], error)
+	SearchTasks(ctx context.Context, words []string, filter model.TaskFilter, page model.PageRequest) (model.Page[model.SearchResult], error)
+	UserTimezone(ctx context.Context, userID int) (string, error)
+	SetUserTimezone(ctx context.Context, userID int, timezone string) error
+	UserLanguage(ctx context.Context, userID int) (string, error)
+	SetUserLanguage(ctx context.Context, userID int, language string) error
+	CreateTeam(ctx context.Context, team model.Team) (int, error)
+	AddTeamMember(ctx context.Context, teamID int, userID int) error
+
+	CreateCalendar(ctx context.Context, calendar model.Calendar) (int, error)
+	AddCalendarHolidays(ctx context.Context, calendarID int, holidays []model.Holiday) error
+	CalendarByID(ctx context.Context, calendarID int) (model.Calendar, error)
+	CalendarFor(ctx context.Context, projectID int, userID int) (model.Calendar, bool, error)
+	SetProjectCalendar(ctx context.Context, projectID int, calendarID int) error
+	SetUserCalendar(ctx context.Context, userID int, calendarID int) error
+
+	CreateView(ctx context.Context, view model.View) (int, error)
+	UpdateView(ctx context.Context, view model.View) error
+	DeleteView(ctx context.Context, viewID int) error
+	ViewByID(ctx context.Context, viewID int) (model.View, error)
+	Views(ctx context.Context, userID int) ([]model.View, error)
+	ProjectMember(ctx context.Context, projectID int, userID int) (bool, error)
+	SubscribeView(ctx context.Context, viewID int, userID int) error
+	UnsubscribeView(ctx context.Context, viewID int, userID int) error
+	ViewSubscriptions(ctx context.Context) ([]model.ViewSubscription, error)
+	UpdateViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) ([]int, error)
+	ReleaseViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) error
+
+	EventScope(ctx context.Context, userID int) (model.EventScope, error)
+
+	CreateWebhook(ctx context.Context, webhook model.Webhook) (int, error)
+	// UpdateWebhook webhook.Secret = "" - ключ подписи не меняется
+	UpdateWebhook(ctx context.Context, webhook model.Webhook) error
+	DeleteWebhook(ctx context.Context, webhookID int) error
+	WebhookByID(ctx context.Context, webhookID int) (model.Webhook, error)
+	Webhooks(ctx context.Context, projectID int) ([]model.Webhook, error)
+	// EnqueueWebhookDeliveries ставит событие в очередь доставки подписанным вебхукам его проекта,
+	// повторно полученное событие пропускается
+	EnqueueWebhookDeliveries(ctx context.Context, event model.TaskEvent, payload []byte) (int, error)
+	// ClaimWebhookDeliveries берёт в работу доставки, срок попытки которых наступил; без записи результата
+	// доставка снова станет доступной через lease
+	ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookJob, error)
+	// RecordWebhookAttempt true - после этой неудачи вебхук отключён
+	RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, disableAfter int) (bool, error)
+	WebhookDeliveries(ctx context.Context, webhookID int, page model.PageRequest) (model.Page[model.WebhookDelivery], error)
+	ReplayWebhookDelivery(ctx context.Context, webhookID int, deliveryID int) (int, error)
+
+	// SyncChanges изменения задач, видимых пользователю, с номерами после since
+	SyncChanges(ctx context.Context, scope model.EventScope, since int64, limit int) (model.SyncChanges, error)
+	// PruneSyncTombstones удаляет надгробия старше before, возвращает число удалённых
+	PruneSyncTombstones(ctx context.Context, before time.Time) (int, error)
+
+	// Пакетная загрузка для GraphQL: отсутствующие ID пропускаются
+	TasksByIDs(ctx context.Context, taskIDs []int) ([]model.Task, error)
+	UsersByIDs(ctx context.Context, userIDs []int) ([]model.User, error)
+	ProjectsByIDs(ctx context.Context, projectIDs []int) ([]model.Project, error)
+	AssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]model.User, error)
+	TasksByAssignees(ctx context.Context, userIDs []int, limit int) (map[int][]model.Task, error)
+
+	// TaskCounts незавершённые задачи по статусам и просроченные на момент now, для метрик
+	TaskCounts(ctx context.Context, now time.Time) (model.TaskCounts, error)
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=CacheRepository --output=../service/mocks
 type CacheRepository interface {
 	InsertingCache(ctx context.Context, task model.Task) error
 	GetTaskFromCache(ctx context.Context, taskID int) (model.Task, error)
-	UpdateTaskStatusInCache(ctx context.Context, taskID int, status string) error
 	DeleteTaskFromCache(ctx context.Context, taskID int) error
 }
 
+// IdempotencyRepository хранит ответы на запросы с ключом идемпотентности
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=IdempotencyRepository --output=../service/mocks
+type IdempotencyRepository interface {
+	// ReserveIdempotencyKey занимает свободный ключ на время выполнения запроса, false - ключ уже занят
+	ReserveIdempotencyKey(ctx context.Context, key string, fingerprint string, ttl time.Duration) (bool, error)
+	IdempotentResponse(ctx context.Context, key string) (model.IdempotentResponse, bool, error)
+	SaveIdempotentResponse(ctx context.Context, key string, response model.IdempotentResponse, ttl time.Duration) error
+	ReleaseIdempotencyKey(ctx context.Context, key string) error
+}
+
+// EventStream общий для всех экземпляров приложения журнал событий задач ограниченной длины
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=EventStream --output=../service/mocks
+type EventStream interface {
+	PublishEvent(ctx context.Context, event model.TaskEvent) error
+	// EventsAfter не больше count событий после lastID. false - события после lastID могли быть
+	// вытеснены из журнала или их больше count, продолжить поток без пропусков нельзя.
+	EventsAfter(ctx context.Context, lastID string, count int64) ([]model.TaskEvent, bool, error)
+	// LastEventID ID последнего события журнала, "0-0" - журнал пуст
+	LastEventID(ctx context.Context) (string, error)
+	// ReadEvents ждёт события после lastID не дольше block, по истечении возвращает пустой список
+	ReadEvents(ctx context.Context, lastID string, block time.Duration) ([]model.TaskEvent, error)
+}
+
+// BoardRepository присутствие на досках проектов и сигналы досок, общие для всех экземпляров приложения
+//
+//go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=BoardRepository --output=../service/mocks
+type BoardRepository interface {
+	// SetPresence записывает присутствие соединения connID, запись без продления удаляется через ttl
+	SetPresence(ctx context.Context, projectID int, connID string, presence model.Presence, ttl time.Duration) error
+	RemovePresence(ctx context.Context, projectID int, connID string) error
+	// Presence присутствие на доске по одной записи на соединение
+	Presence(ctx context.Context, projectID int) ([]model.Presence, error)
+	PublishBoardSignal(ctx context.Context, signal model.BoardSignal) error
+	// BoardSignals сигналы всех досок, канал закрывается после отмены ctx
+	BoardSignals(ctx context.Context) (<-chan model.BoardSignal, error)
+}
+
 //go:generate go run github.com/vektra/mockery/v2@v2.51.1 --name=Broker --output=../service/mocks
 type Broker interface {
 	Produce(message []byte, topic string) error
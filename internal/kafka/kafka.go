@@ -1,12 +1,16 @@
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
+const pingTimeout = 5 * time.Second
+
 type Producer struct {
 	producer *kafka.Producer
 }
@@ -43,6 +47,22 @@ func (p *Producer) Produce(message []byte, topic string) error {
 	}
 }
 
+// Ping запрашивает метаданные кластера: ответ хотя бы одного брокера означает, что кластер доступен.
+// Без дедлайна в ctx ожидание ограничено pingTimeout.
+func (p *Producer) Ping(ctx context.Context) error {
+	timeout := pingTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	if timeout <= 0 {
+		return context.DeadlineExceeded
+	}
+	if _, err := p.producer.GetMetadata(nil, false, int(timeout.Milliseconds())); err != nil {
+		return fmt.Errorf("failed to get metadata: %w", err)
+	}
+	return nil
+}
+
 func (p *Producer) Close() {
 	p.producer.Flush(2000)
 	p.producer.Close()
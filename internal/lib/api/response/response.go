@@ -1,15 +1,25 @@
 package response
 
 import (
-	"fmt"
+	"errors"
+	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/query"
+	"Tasks/internal/lib/validation"
+	"Tasks/internal/model"
 )
 
 type Response struct {
 	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
 	Error  string `json:"error,omitempty"`
+	// Errors ошибки отдельных полей, заполняется только для code = validation_failed
+	Errors []FieldError `json:"errors,omitempty"`
 }
 
 const (
@@ -17,28 +27,154 @@ const (
 	StatusError = "ERROR"
 )
 
+// Коды ошибок в ответе. Не меняются между версиями, клиенты могут на них опираться.
+const (
+	CodeBadRequest   = "bad_request"
+	CodeValidation   = "validation_failed"
+	CodeNotFound     = "not_found"
+	CodeConflict     = "conflict"
+	CodeForbidden    = "forbidden"
+	CodeUnauthorized = "unauthorized"
+	CodePrecondition = "precondition_failed"
+	CodeInternal     = "internal_error"
+)
+
+// ErrBadRequest запрос не удалось разобрать: некорректный JSON или параметры пути
+var ErrBadRequest = errors.New("bad request")
+
+func BadRequest(format string, args ...any) error {
+	return &model.Error{Kind: ErrBadRequest, Format: format, Args: args}
+}
+
 func OK() Response {
 	return Response{Status: StatusOK}
 }
 
-func Error(msg string) Response {
-	return Response{Status: StatusError, Error: msg}
+// Error ответ с ошибкой, текст переводится на язык lang
+func Error(lang string, format string, args ...any) Response {
+	return Response{Status: StatusError, Error: i18n.T(lang, format, args...)}
 }
 
-func ValidationError(errs validator.ValidationErrors) Response {
+// FieldError ошибка проверки одного поля запроса. Field - имя поля в JSON, Rule - нарушенное правило,
+// Param - параметр правила (например, максимальная длина). Position - позиция ошибки в символах с единицы
+// для параметра q с запросом на языке фильтров.
+type FieldError struct {
+	Field    string `json:"field"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+	Param    string `json:"param,omitempty"`
+	Position int    `json:"position,omitempty"`
+}
+
+func ValidationError(errs validator.ValidationErrors, lang string) Response {
 	var errMsgs []string
+	fields := make([]FieldError, 0, len(errs))
 
 	for _, err := range errs {
-		switch err.ActualTag() {
-		case "required":
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is a required field", err.Field()))
+		fe := FieldError{
+			Field: err.Field(),
+			Rule:  err.Tag(),
+			Param: err.Param(),
+		}
+		switch err.Tag() {
+		case "required", "required_without", "required_with", "required_if":
+			// параметр условных правил - имя поля Go-структуры, клиенту он не нужен
+			fe.Param = ""
+			fe.Message = i18n.T(lang, "field %s is a required field", fe.Field)
+		case "excluded_with", "excluded_without", "excluded_if":
+			fe.Param = ""
+			fe.Message = i18n.T(lang, "field %s conflicts with another field", fe.Field)
+		case "max":
+			fe.Message = i18n.T(lang, lengthFormat(err, "field %s must be at most %s"), fe.Field, fe.Param)
+		case "min":
+			fe.Message = i18n.T(lang, lengthFormat(err, "field %s must be at least %s"), fe.Field, fe.Param)
+		case "oneof":
+			fe.Message = i18n.T(lang, "field %s must be one of: %s", fe.Field, strings.ReplaceAll(fe.Param, " ", ", "))
+		case validation.Future:
+			fe.Message = i18n.T(lang, "field %s must be in the future", fe.Field)
+		case validation.TaskStatus:
+			fe.Param = strings.Join(model.TaskStatuses, " ")
+			fe.Message = i18n.T(lang, "field %s must be one of: %s", fe.Field, strings.Join(model.TaskStatuses, ", "))
+		case validation.Priority:
+			fe.Param = strings.Join(model.Priorities, " ")
+			fe.Message = i18n.T(lang, "field %s must be one of: %s", fe.Field, strings.Join(model.Priorities, ", "))
 		default:
-			errMsgs = append(errMsgs, fmt.Sprintf("field %s is not valid", err.Field()))
+			fe.Message = i18n.T(lang, "field %s is not valid", fe.Field)
 		}
+		errMsgs = append(errMsgs, fe.Message)
+		fields = append(fields, fe)
 	}
 
 	return Response{
 		Status: StatusError,
+		Code:   CodeValidation,
 		Error:  strings.Join(errMsgs, ", "),
+		Errors: fields,
+	}
+}
+
+// QueryError ответ на ошибку в параметре q: сообщение и позиция, с которой запрос не удалось разобрать
+func QueryError(err *query.Error, lang string) Response {
+	msg := i18n.T(lang, err.Format, err.Args...)
+	return Response{
+		Status: StatusError,
+		Code:   CodeBadRequest,
+		Error:  i18n.T(lang, "invalid query at position %d: %s", err.Pos, msg),
+		Errors: []FieldError{{Field: "q", Rule: "query", Message: msg, Position: err.Pos}},
+	}
+}
+
+// lengthFormat для строк и списков min и max ограничивают длину, а не значение
+func lengthFormat(err validator.FieldError, format string) string {
+	switch err.Kind() {
+	case reflect.String:
+		return format + " characters"
+	case reflect.Slice, reflect.Map:
+		return format + " items"
+	}
+	return format
+}
+
+// FromError возвращает HTTP-статус и тело ответа для ошибки на языке lang.
+// Клиенту отдаётся только сообщение доменной ошибки, текст остальных ошибок остаётся в логах.
+func FromError(err error, lang string) (int, Response) {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		return http.StatusUnprocessableEntity, ValidationError(validationErrs, lang)
+	}
+	var queryErr *query.Error
+	if errors.As(err, &queryErr) {
+		return http.StatusBadRequest, QueryError(queryErr, lang)
+	}
+
+	var status int
+	var code, msg string
+	switch {
+	case errors.Is(err, ErrBadRequest):
+		status, code, msg = http.StatusBadRequest, CodeBadRequest, "bad request"
+	case errors.Is(err, model.ErrValidation):
+		status, code, msg = http.StatusUnprocessableEntity, CodeValidation, "validation failed"
+	case errors.Is(err, model.ErrNotFound):
+		status, code, msg = http.StatusNotFound, CodeNotFound, "resource not found"
+	case errors.Is(err, model.ErrConflict):
+		status, code, msg = http.StatusConflict, CodeConflict, "conflict"
+	case errors.Is(err, model.ErrForbidden):
+		status, code, msg = http.StatusForbidden, CodeForbidden, "forbidden"
+	case errors.Is(err, model.ErrUnauthorized):
+		status, code, msg = http.StatusUnauthorized, CodeUnauthorized, "unauthorized"
+	case errors.Is(err, model.ErrPreconditionFailed):
+		status, code, msg = http.StatusPreconditionFailed, CodePrecondition, "precondition failed"
+	default:
+		body := Error(lang, "internal server error")
+		body.Code = CodeInternal
+		return http.StatusInternalServerError, body
+	}
+
+	body := Error(lang, msg)
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		body = Error(lang, domainErr.Format, domainErr.Args...)
 	}
+	body.Code = code
+	return status, body
 }
@@ -0,0 +1,119 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/query"
+	"Tasks/internal/lib/validation"
+	"Tasks/internal/model"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+		msg    string
+	}{
+		{
+			name:   "not found wrapped by repository and service",
+			err:    fmt.Errorf("failed to get task: %w", model.NotFound("task with ID %d not found", 7)),
+			status: http.StatusNotFound,
+			code:   CodeNotFound,
+			msg:    "task with ID 7 not found",
+		},
+		{name: "conflict", err: model.Conflict("sprint 3 is already completed"), status: http.StatusConflict, code: CodeConflict, msg: "sprint 3 is already completed"},
+		{name: "precondition failed", err: model.PreconditionFailed("task %d has been modified, current version is %d", 3, 4), status: http.StatusPreconditionFailed, code: CodePrecondition, msg: "task 3 has been modified, current version is 4"},
+		{name: "forbidden", err: model.Forbidden("access denied"), status: http.StatusForbidden, code: CodeForbidden, msg: "access denied"},
+		{name: "unauthorized", err: model.Unauthorized("authentication required"), status: http.StatusUnauthorized, code: CodeUnauthorized, msg: "authentication required"},
+		{name: "validation", err: model.Invalid("unknown task priority %q", "urgent"), status: http.StatusUnprocessableEntity, code: CodeValidation, msg: `unknown task priority "urgent"`},
+		{name: "bare sentinel", err: fmt.Errorf("lookup: %w", model.ErrNotFound), status: http.StatusNotFound, code: CodeNotFound, msg: "resource not found"},
+		{name: "bad request", err: BadRequest("failed to decode request"), status: http.StatusBadRequest, code: CodeBadRequest, msg: "failed to decode request"},
+		{
+			name:   "query syntax error",
+			err:    &query.Error{Pos: 8, Format: "unknown field %q", Args: []any{"stat"}},
+			status: http.StatusBadRequest,
+			code:   CodeBadRequest,
+			msg:    `invalid query at position 8: unknown field "stat"`,
+		},
+		{
+			name:   "internal error hides details",
+			err:    fmt.Errorf("failed to execute query: %w", errors.New("dial tcp 10.0.0.5:5432: connection refused")),
+			status: http.StatusInternalServerError,
+			code:   CodeInternal,
+			msg:    "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := FromError(tt.err, i18n.English)
+			if status != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, status)
+			}
+			if body.Status != StatusError || body.Code != tt.code || body.Error != tt.msg {
+				t.Errorf("expected (%s, %q), got (%s, %q)", tt.code, tt.msg, body.Code, body.Error)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	type request struct {
+		TaskText     string    `json:"task_text" validate:"required,max=5"`
+		Status       string    `json:"status" validate:"task_status"`
+		Deadline     time.Time `json:"deadline"`
+		DeadlineExpr string    `json:"deadline_expr" validate:"excluded_with=Deadline"`
+	}
+
+	err := validation.New().Struct(request{TaskText: "too long", Status: "paused", Deadline: time.Now(), DeadlineExpr: "+1d"})
+	var errs validator.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected validation errors, got %v", err)
+	}
+
+	status, body := FromError(fmt.Errorf("validation error: %w", err), i18n.English)
+	if status != http.StatusUnprocessableEntity || body.Code != CodeValidation {
+		t.Fatalf("expected 422 %s, got %d %s", CodeValidation, status, body.Code)
+	}
+	expected := []FieldError{
+		{Field: "task_text", Rule: "max", Message: "field task_text must be at most 5 characters", Param: "5"},
+		{Field: "status", Rule: validation.TaskStatus, Message: "field status must be one of: todo, in_progress, done", Param: "todo in_progress done"},
+		{Field: "deadline_expr", Rule: "excluded_with", Message: "field deadline_expr conflicts with another field"},
+	}
+	if len(body.Errors) != len(expected) {
+		t.Fatalf("expected %d field errors, got %v", len(expected), body.Errors)
+	}
+	for i := range expected {
+		if body.Errors[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], body.Errors[i])
+		}
+	}
+}
+
+func TestFromError_Localized(t *testing.T) {
+	_, body := FromError(fmt.Errorf("failed to get task: %w", model.NotFound("task with ID %d not found", 7)), i18n.Russian)
+	if body.Error != "задача с ID 7 не найдена" {
+		t.Errorf("unexpected message %q", body.Error)
+	}
+
+	_, body = FromError(errors.New("connection refused"), i18n.Russian)
+	if body.Code != CodeInternal || body.Error != "внутренняя ошибка сервера" {
+		t.Errorf("unexpected response %+v", body)
+	}
+
+	// сообщение без перевода остаётся на английском
+	_, body = FromError(model.Invalid("%v", errors.New("unexpected token")), i18n.Russian)
+	if body.Error != "unexpected token" {
+		t.Errorf("unexpected message %q", body.Error)
+	}
+}
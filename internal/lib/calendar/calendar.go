@@ -0,0 +1,177 @@
+package calendar
+
+import (
+	"fmt"
+	"time"
+
+	"Tasks/internal/model"
+)
+
+const dateLayout = "2006-01-02"
+
+// Calendar рабочий календарь: рабочие часы, выходные дни недели и праздники.
+// Нулевой указатель *Calendar означает круглосуточный календарь без выходных.
+type Calendar struct {
+	loc       *time.Location
+	workStart time.Duration
+	workEnd   time.Duration
+	weekends  map[time.Weekday]bool
+	holidays  map[string]bool
+}
+
+func New(loc *time.Location, workStart, workEnd time.Duration, weekends []time.Weekday, holidays []time.Time) (*Calendar, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	if workStart < 0 || workEnd > 24*time.Hour || workEnd <= workStart {
+		return nil, model.Invalid("invalid working hours %s-%s", clock(workStart), clock(workEnd))
+	}
+	c := &Calendar{
+		loc:       loc,
+		workStart: workStart,
+		workEnd:   workEnd,
+		weekends:  make(map[time.Weekday]bool, len(weekends)),
+		holidays:  make(map[string]bool, len(holidays)),
+	}
+	for _, d := range weekends {
+		c.weekends[d] = true
+	}
+	if len(c.weekends) == 7 {
+		return nil, model.Invalid("calendar must have at least one working weekday")
+	}
+	for _, h := range holidays {
+		c.holidays[h.Format(dateLayout)] = true
+	}
+	return c, nil
+}
+
+// FromModel строит календарь из сохранённого описания
+func FromModel(m model.Calendar) (*Calendar, error) {
+	loc, err := time.LoadLocation(m.Timezone)
+	if err != nil {
+		return nil, model.Invalid("unknown timezone %q", m.Timezone)
+	}
+	start, err := ParseClock(m.WorkStart)
+	if err != nil {
+		return nil, err
+	}
+	end, err := ParseClock(m.WorkEnd)
+	if err != nil {
+		return nil, err
+	}
+	weekends := make([]time.Weekday, 0, len(m.Weekends))
+	for _, d := range m.Weekends {
+		if d < 0 || d > 6 {
+			return nil, model.Invalid("invalid weekday %d", d)
+		}
+		weekends = append(weekends, time.Weekday(d))
+	}
+	holidays := make([]time.Time, 0, len(m.Holidays))
+	for _, h := range m.Holidays {
+		holidays = append(holidays, h.Date)
+	}
+	return New(loc, start, end, weekends, holidays)
+}
+
+// ParseClock разбирает время суток в формате "15:04"
+func ParseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, model.Invalid("invalid time of day %q", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// clock время суток в формате ParseClock
+func clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// IsWorkingDay день не выходной и не праздник
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if c == nil {
+		return true
+	}
+	t = t.In(c.loc)
+	return !c.weekends[t.Weekday()] && !c.holidays[t.Format(dateLayout)]
+}
+
+// AddBusinessDays возвращает конец рабочего дня, отстоящего от t на n рабочих дней.
+// При n = 0 - конец текущего дня, если он рабочий, иначе ближайшего следующего.
+func (c *Calendar) AddBusinessDays(t time.Time, n int) time.Time {
+	if c == nil {
+		return t.AddDate(0, 0, n)
+	}
+	day := c.startOfDay(t)
+	for !c.IsWorkingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	for i := 0; i < n; {
+		day = day.AddDate(0, 0, 1)
+		if c.IsWorkingDay(day) {
+			i++
+		}
+	}
+	return day.Add(c.workEnd)
+}
+
+// AddBusinessHours прибавляет к t продолжительность d, считая только рабочие часы рабочих дней
+func (c *Calendar) AddBusinessHours(t time.Time, d time.Duration) time.Time {
+	if c == nil {
+		return t.Add(d)
+	}
+	t = t.In(c.loc)
+	for {
+		day := c.startOfDay(t)
+		start, end := day.Add(c.workStart), day.Add(c.workEnd)
+		if !c.IsWorkingDay(day) || !t.Before(end) {
+			t = day.AddDate(0, 0, 1)
+			continue
+		}
+		if t.Before(start) {
+			t = start
+		}
+		available := end.Sub(t)
+		if d <= available {
+			return t.Add(d)
+		}
+		d -= available
+		t = day.AddDate(0, 0, 1)
+	}
+}
+
+// BusinessDuration рабочее время от from до to: учитываются только рабочие часы рабочих дней,
+// как в AddBusinessHours. Если to раньше from, результат отрицательный.
+func (c *Calendar) BusinessDuration(from, to time.Time) time.Duration {
+	if c == nil {
+		return to.Sub(from)
+	}
+	if to.Before(from) {
+		return -c.BusinessDuration(to, from)
+	}
+	var total time.Duration
+	for day := c.startOfDay(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !c.IsWorkingDay(day) {
+			continue
+		}
+		start, end := day.Add(c.workStart), day.Add(c.workEnd)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
+
+func (c *Calendar) startOfDay(t time.Time) time.Time {
+	t = t.In(c.loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, c.loc)
+}
@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// календарь 09:00-18:00, выходные суббота и воскресенье, праздник 1 января 2025 (среда)
+func testCalendar(t *testing.T) *Calendar {
+	t.Helper()
+	c, err := New(time.UTC, 9*time.Hour, 18*time.Hour,
+		[]time.Weekday{time.Saturday, time.Sunday},
+		[]time.Time{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestCalendar_AddBusinessDays(t *testing.T) {
+	c := testCalendar(t)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		n        int
+		expected time.Time
+	}{
+		{name: "within a week", from: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), n: 3, expected: time.Date(2025, 1, 9, 18, 0, 0, 0, time.UTC)},
+		{name: "over the weekend", from: time.Date(2025, 1, 9, 10, 0, 0, 0, time.UTC), n: 3, expected: time.Date(2025, 1, 14, 18, 0, 0, 0, time.UTC)},
+		{name: "over the holiday", from: time.Date(2024, 12, 31, 10, 0, 0, 0, time.UTC), n: 1, expected: time.Date(2025, 1, 2, 18, 0, 0, 0, time.UTC)},
+		{name: "zero days from a weekend", from: time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC), n: 0, expected: time.Date(2025, 1, 13, 18, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.AddBusinessDays(tt.from, tt.n); !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_AddBusinessHours(t *testing.T) {
+	c := testCalendar(t)
+
+	tests := []struct {
+		name     string
+		from     time.Time
+		hours    int
+		expected time.Time
+	}{
+		{name: "same day", from: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC), hours: 4, expected: time.Date(2025, 1, 6, 14, 0, 0, 0, time.UTC)},
+		{name: "next day", from: time.Date(2025, 1, 6, 16, 0, 0, 0, time.UTC), hours: 4, expected: time.Date(2025, 1, 7, 11, 0, 0, 0, time.UTC)},
+		{name: "before working hours", from: time.Date(2025, 1, 6, 7, 0, 0, 0, time.UTC), hours: 1, expected: time.Date(2025, 1, 6, 10, 0, 0, 0, time.UTC)},
+		{name: "friday evening", from: time.Date(2025, 1, 10, 20, 0, 0, 0, time.UTC), hours: 2, expected: time.Date(2025, 1, 13, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.AddBusinessHours(tt.from, time.Duration(tt.hours)*time.Hour); !got.Equal(tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCalendar_BusinessDuration(t *testing.T) {
+	c := testCalendar(t)
+
+	// с пятницы 12:00 до понедельника 12:00 - выходные и нерабочие часы не считаются:
+	// пятница 12:00-18:00 и понедельник 09:00-12:00
+	from := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 13, 12, 0, 0, 0, time.UTC)
+	if got := c.BusinessDuration(from, to); got != 9*time.Hour {
+		t.Errorf("expected 9h, got %v", got)
+	}
+	if got := c.BusinessDuration(to, from); got != -9*time.Hour {
+		t.Errorf("expected -9h, got %v", got)
+	}
+	// AddBusinessHours обратна BusinessDuration
+	if got := c.AddBusinessHours(from, 9*time.Hour); !got.Equal(to) {
+		t.Errorf("expected %v, got %v", to, got)
+	}
+
+	// с субботы 10:00 до воскресенья 18:00 рабочего времени нет
+	if got := c.BusinessDuration(time.Date(2025, 1, 11, 10, 0, 0, 0, time.UTC), time.Date(2025, 1, 12, 18, 0, 0, 0, time.UTC)); got != 0 {
+		t.Errorf("expected 0 over the weekend, got %v", got)
+	}
+
+	var nilCalendar *Calendar
+	if got := nilCalendar.BusinessDuration(from, to); got != 72*time.Hour {
+		t.Errorf("expected 72h without calendar, got %v", got)
+	}
+}
+
+func TestParseRelative(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected Relative
+		wantErr  bool
+	}{
+		{expr: "+3bd", expected: Relative{N: 3, Unit: UnitBusinessDays}},
+		{expr: "+3 business days", expected: Relative{N: 3, Unit: UnitBusinessDays}},
+		{expr: " +8 Working  Hours ", expected: Relative{N: 8, Unit: UnitBusinessHours}},
+		{expr: "+2d", expected: Relative{N: 2, Unit: UnitDays}},
+		{expr: "+1 week", expected: Relative{N: 1, Unit: UnitWeeks}},
+		{expr: "3d", wantErr: true},
+		{expr: "+d", wantErr: true},
+		{expr: "+3 fortnights", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseRelative(tt.expr)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseICal(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20250101",
+		"DTEND;VALUE=DATE:20250103",
+		"SUMMARY:Новогодние",
+		"  каникулы",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20250308T000000Z",
+		"SUMMARY:8 марта",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	holidays, err := ParseICal(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(holidays) != 3 {
+		t.Fatalf("expected 3 holidays, got %d", len(holidays))
+	}
+	if holidays[0].Name != "Новогодние каникулы" || holidays[1].Date.Day() != 2 || holidays[2].Date.Month() != time.March {
+		t.Errorf("unexpected holidays: %+v", holidays)
+	}
+}
@@ -0,0 +1,108 @@
+package calendar
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"Tasks/internal/model"
+)
+
+// максимальная длина одного события в днях, защищает от некорректных файлов
+const maxEventDays = 366
+
+// ParseICal извлекает праздничные дни из событий VEVENT файла iCalendar (RFC 5545).
+// Многодневные события разворачиваются в отдельные дни, DTEND не включается.
+func ParseICal(r io.Reader) ([]model.Holiday, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		holidays []model.Holiday
+		inEvent  bool
+		start    time.Time
+		end      time.Time
+		summary  string
+	)
+	for i, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// параметры свойства (DTSTART;VALUE=DATE) для разбора не нужны
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent, start, end, summary = true, time.Time{}, time.Time{}, ""
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			if !inEvent {
+				continue
+			}
+			inEvent = false
+			if start.IsZero() {
+				return nil, model.Invalid("line %d: event without DTSTART", i+1)
+			}
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+			days := 0
+			for d := start; d.Before(end); d = d.AddDate(0, 0, 1) {
+				if days++; days > maxEventDays {
+					return nil, model.Invalid("line %d: event is longer than %d days", i+1, maxEventDays)
+				}
+				holidays = append(holidays, model.Holiday{Date: d, Name: summary})
+			}
+		case !inEvent:
+		case name == "DTSTART":
+			if start, ok = parseICalDate(value); !ok {
+				return nil, model.Invalid("line %d: invalid date %q", i+1, value)
+			}
+		case name == "DTEND":
+			if end, ok = parseICalDate(value); !ok {
+				return nil, model.Invalid("line %d: invalid date %q", i+1, value)
+			}
+		case name == "SUMMARY":
+			summary = unescape(value)
+		}
+	}
+	return holidays, nil
+}
+
+// unfold склеивает перенесённые строки: продолжение начинается с пробела или табуляции
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICalDate берёт из DATE или DATE-TIME только дату
+func parseICalDate(value string) (time.Time, bool) {
+	if len(value) < 8 {
+		return time.Time{}, false
+	}
+	d, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return d, true
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\,`, ",", `\;`, ";", `\n`, " ", `\N`, " ", `\\`, `\`).Replace(s)
+}
@@ -0,0 +1,77 @@
+package calendar
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"Tasks/internal/model"
+)
+
+// Единицы относительного дедлайна
+const (
+	UnitBusinessDays  = "bd"
+	UnitBusinessHours = "bh"
+	UnitWeeks         = "w"
+	UnitDays          = "d"
+	UnitHours         = "h"
+	UnitMinutes       = "m"
+)
+
+var unitAliases = map[string]string{
+	"bd": UnitBusinessDays, "business day": UnitBusinessDays, "business days": UnitBusinessDays,
+	"working day": UnitBusinessDays, "working days": UnitBusinessDays,
+	"bh": UnitBusinessHours, "business hour": UnitBusinessHours, "business hours": UnitBusinessHours,
+	"working hour": UnitBusinessHours, "working hours": UnitBusinessHours,
+	"w": UnitWeeks, "week": UnitWeeks, "weeks": UnitWeeks,
+	"d": UnitDays, "day": UnitDays, "days": UnitDays,
+	"h": UnitHours, "hour": UnitHours, "hours": UnitHours,
+	"m": UnitMinutes, "min": UnitMinutes, "minute": UnitMinutes, "minutes": UnitMinutes,
+}
+
+// Relative относительный дедлайн вида "+3 business days", "+3bd", "+2d", "+4h"
+type Relative struct {
+	N    int
+	Unit string
+}
+
+func ParseRelative(expr string) (Relative, error) {
+	s := strings.TrimSpace(expr)
+	if !strings.HasPrefix(s, "+") {
+		return Relative{}, model.Invalid("relative deadline %q must start with '+'", expr)
+	}
+	s = s[1:]
+
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	n, err := strconv.Atoi(s[:i])
+	if err != nil {
+		return Relative{}, model.Invalid("relative deadline %q must contain a number", expr)
+	}
+
+	unit, ok := unitAliases[strings.ToLower(strings.Join(strings.Fields(s[i:]), " "))]
+	if !ok {
+		return Relative{}, model.Invalid("unknown unit in relative deadline %q", expr)
+	}
+	return Relative{N: n, Unit: unit}, nil
+}
+
+// From вычисляет дедлайн относительно t. Рабочие единицы учитывают календарь c.
+func (r Relative) From(t time.Time, c *Calendar) time.Time {
+	switch r.Unit {
+	case UnitBusinessDays:
+		return c.AddBusinessDays(t, r.N)
+	case UnitBusinessHours:
+		return c.AddBusinessHours(t, time.Duration(r.N)*time.Hour)
+	case UnitWeeks:
+		return t.AddDate(0, 0, 7*r.N)
+	case UnitDays:
+		return t.AddDate(0, 0, r.N)
+	case UnitHours:
+		return t.Add(time.Duration(r.N) * time.Hour)
+	default:
+		return t.Add(time.Duration(r.N) * time.Minute)
+	}
+}
@@ -0,0 +1,60 @@
+package deadline
+
+import (
+	"time"
+
+	"Tasks/internal/model"
+)
+
+// Именованные окна дедлайнов
+const (
+	Overdue   = "overdue"
+	Today     = "today"
+	Tomorrow  = "tomorrow"
+	ThisWeek  = "this_week"
+	NextNDays = "next_n_days"
+)
+
+// Range диапазон дедлайнов [From, To). Нулевой From означает "без нижней границы".
+type Range struct {
+	From time.Time
+	To   time.Time
+	// UnfinishedOnly в выборку попадают только незавершённые задачи (для просроченных)
+	UnfinishedOnly bool
+}
+
+// Window вычисляет диапазон для именованного окна относительно now в часовом поясе loc.
+// Для next_n_days n - количество дней, включая сегодняшний.
+func Window(name string, n int, now time.Time, loc *time.Location) (Range, error) {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch name {
+	case Overdue:
+		return Range{To: now, UnfinishedOnly: true}, nil
+	case Today:
+		return Range{From: today, To: today.AddDate(0, 0, 1)}, nil
+	case Tomorrow:
+		return Range{From: today.AddDate(0, 0, 1), To: today.AddDate(0, 0, 2)}, nil
+	case ThisWeek:
+		// неделя начинается с понедельника
+		offset := (int(today.Weekday()) + 6) % 7
+		monday := today.AddDate(0, 0, -offset)
+		return Range{From: monday, To: monday.AddDate(0, 0, 7)}, nil
+	case NextNDays:
+		if n <= 0 {
+			return Range{}, model.Invalid("window %s requires a positive number of days", NextNDays)
+		}
+		return Range{From: now, To: today.AddDate(0, 0, n)}, nil
+	default:
+		return Range{}, model.Invalid("unknown deadline window %q", name)
+	}
+}
+
+// Custom диапазон с произвольными границами, любая из которых может быть нулевой
+func Custom(from, to time.Time) (Range, error) {
+	if !from.IsZero() && !to.IsZero() && !to.After(from) {
+		return Range{}, model.Invalid("deadline range end must be after its start")
+	}
+	return Range{From: from, To: to}, nil
+}
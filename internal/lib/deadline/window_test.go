@@ -0,0 +1,59 @@
+package deadline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindow(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skip("timezone database is not available")
+	}
+	// среда, 23:30 по Москве - в UTC ещё 20:30 того же дня
+	now := time.Date(2025, 1, 15, 23, 30, 0, 0, moscow)
+
+	tests := []struct {
+		name    string
+		window  string
+		n       int
+		from    time.Time
+		to      time.Time
+		wantErr bool
+	}{
+		{name: "overdue", window: Overdue, to: now},
+		{name: "today", window: Today, from: time.Date(2025, 1, 15, 0, 0, 0, 0, moscow), to: time.Date(2025, 1, 16, 0, 0, 0, 0, moscow)},
+		{name: "tomorrow", window: Tomorrow, from: time.Date(2025, 1, 16, 0, 0, 0, 0, moscow), to: time.Date(2025, 1, 17, 0, 0, 0, 0, moscow)},
+		{name: "this week", window: ThisWeek, from: time.Date(2025, 1, 13, 0, 0, 0, 0, moscow), to: time.Date(2025, 1, 20, 0, 0, 0, 0, moscow)},
+		{name: "next 3 days", window: NextNDays, n: 3, from: now, to: time.Date(2025, 1, 18, 0, 0, 0, 0, moscow)},
+		{name: "next 0 days", window: NextNDays, wantErr: true},
+		{name: "unknown", window: "someday", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Window(tt.window, tt.n, now.UTC(), moscow)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if !got.From.Equal(tt.from) || !got.To.Equal(tt.to) {
+				t.Errorf("expected [%v, %v), got [%v, %v)", tt.from, tt.to, got.From, got.To)
+			}
+		})
+	}
+}
+
+func TestWindow_SundayBelongsToCurrentWeek(t *testing.T) {
+	sunday := time.Date(2025, 1, 19, 12, 0, 0, 0, time.UTC)
+	got, err := Window(ThisWeek, 0, sunday, time.UTC)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC); !got.From.Equal(want) {
+		t.Errorf("expected week to start at %v, got %v", want, got.From)
+	}
+}
@@ -0,0 +1,55 @@
+package etag
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Format ETag версии ресурса: сильный, в кавычках, например "3"
+func Format(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// IfMatch возвращает версию из заголовка If-Match. 0 - заголовка нет или указан "*", проверять нечего.
+// Если ни один ETag не может совпасть с версией ресурса (слабый или чужой), возвращается -1:
+// такое условие всегда ложно. Из списка ETag учитывается первый, похожий на версию.
+func IfMatch(header string) int {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if version, ok := parse(strings.TrimSpace(tag)); ok {
+			return version
+		}
+	}
+	return -1
+}
+
+// NoneMatch сообщает, совпадает ли версия с одним из ETag заголовка If-None-Match.
+// Сравнение слабое: W/"3" совпадает с "3".
+func NoneMatch(header string, version int) bool {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if v, ok := parse(tag); ok && v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// parse версия из сильного ETag
+func parse(tag string) (int, bool) {
+	if len(tag) < 3 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, false
+	}
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
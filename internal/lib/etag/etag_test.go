@@ -0,0 +1,51 @@
+package etag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{name: "no header", header: "", want: 0},
+		{name: "any", header: "*", want: 0},
+		{name: "version", header: `"3"`, want: 3},
+		{name: "list", header: `W/"2", "5"`, want: 5},
+		{name: "weak tag never matches", header: `W/"3"`, want: -1},
+		{name: "foreign tag", header: `"abc"`, want: -1},
+		{name: "zero version", header: `"0"`, want: -1},
+		{name: "unquoted", header: `3`, want: -1},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, IfMatch(tt.header))
+		})
+	}
+}
+
+func TestNoneMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "no header", header: "", want: false},
+		{name: "any", header: "*", want: true},
+		{name: "same version", header: Format(3), want: true},
+		{name: "weak comparison", header: `W/"3"`, want: true},
+		{name: "list", header: `"1", "3"`, want: true},
+		{name: "other version", header: `"2"`, want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, NoneMatch(tt.header, 3))
+		})
+	}
+}
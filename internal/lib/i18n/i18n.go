@@ -0,0 +1,89 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Поддерживаемые языки. Сообщения в коде пишутся на английском, он же используется,
+// если язык не поддерживается или перевода нет.
+const (
+	English = "en"
+	Russian = "ru"
+
+	Default = English
+)
+
+// catalogs переводы по языкам. Ключ - исходная английская строка формата.
+var catalogs = map[string]map[string]string{
+	Russian: ru,
+}
+
+// Supported сообщает, есть ли сообщения на языке lang
+func Supported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok || lang == English
+}
+
+// T переводит строку формата на язык lang и подставляет аргументы
+func T(lang string, format string, args ...any) string {
+	if tr, ok := catalogs[lang][format]; ok {
+		format = tr
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Match выбирает поддерживаемый язык из заголовка Accept-Language с учётом весов q.
+// Возвращает false, если ни один язык из заголовка не поддерживается.
+func Match(acceptLanguage string) (string, bool) {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		// en-US и ru-RU сводятся к основному языку
+		lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if lang == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 && Supported(lang) {
+			candidates = append(candidates, candidate{lang: lang, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang, true
+}
+
+type ctxKey struct{}
+
+// WithLanguage сохраняет язык ответа в контексте запроса
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, lang)
+}
+
+// FromContext возвращает язык ответа, а если он не выбран - язык по умолчанию
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(ctxKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
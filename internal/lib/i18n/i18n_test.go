@@ -0,0 +1,74 @@
+package i18n
+
+import (
+	"testing"
+
+	"Tasks/internal/model"
+)
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+		ok       bool
+	}{
+		{header: "ru-RU,ru;q=0.9,en-US;q=0.8,en;q=0.7", expected: Russian, ok: true},
+		{header: "en-GB", expected: English, ok: true},
+		{header: "de-DE,de;q=0.9,ru;q=0.5,en;q=0.6", expected: English, ok: true},
+		{header: "fr, ru;q=0", ok: false},
+		{header: "*", ok: false},
+		{header: "", ok: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.header, func(t *testing.T) {
+			got, ok := Match(tt.header)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("expected (%q, %v), got (%q, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestNotification(t *testing.T) {
+	msg := model.NotificationMessage{Event: model.EventChangeStatus, TaskID: 3, ChangeStatus: model.TaskStatusInProgress}
+
+	tests := []struct {
+		lang     string
+		expected string
+		text     string
+	}{
+		{lang: Russian, expected: Russian, text: "Статус задачи #3 изменён на «в работе»"},
+		{lang: English, expected: English, text: `Task #3 status changed to "in progress"`},
+		{lang: "de", expected: English, text: `Task #3 status changed to "in progress"`},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.lang, func(t *testing.T) {
+			got := Notification(msg, tt.lang)
+			if got.Language != tt.expected || got.Text != tt.text {
+				t.Errorf("expected (%s, %q), got (%s, %q)", tt.expected, tt.text, got.Language, got.Text)
+			}
+		})
+	}
+}
+
+func TestNotification_TasksChanged(t *testing.T) {
+	msg := model.NotificationMessage{Event: model.EventTasksChanged, Changes: []model.NotificationChange{
+		{Event: model.EventChangeStatus, TaskID: 3, ChangeStatus: model.TaskStatusDone},
+		{Event: model.EventAddUser, TaskID: 4},
+	}}
+
+	got := Notification(msg, Russian)
+	if got.Text != "Изменено ваших задач: 2" {
+		t.Errorf("unexpected text %q", got.Text)
+	}
+	if got.Changes[0].Text != "Статус задачи #3 изменён на «выполнена»" || got.Changes[1].Text != "Вы назначены на задачу #4" {
+		t.Errorf("unexpected change texts %+v", got.Changes)
+	}
+	if msg.Changes[0].Text != "" {
+		t.Errorf("source message must not be modified")
+	}
+}
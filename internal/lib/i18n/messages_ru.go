@@ -0,0 +1,168 @@
+package i18n
+
+// ru переводы на русский. Аргументы подставляются в том же порядке, что и в английской строке.
+var ru = map[string]string{
+	// общие ошибки
+	"bad request":           "некорректный запрос",
+	"validation failed":     "данные не прошли проверку",
+	"resource not found":    "ресурс не найден",
+	"conflict":              "конфликт с текущим состоянием ресурса",
+	"forbidden":             "недостаточно прав",
+	"unauthorized":          "требуется аутентификация",
+	"precondition failed":   "ресурс изменился с момента чтения",
+	"internal server error": "внутренняя ошибка сервера",
+
+	// ошибки запроса
+	"failed to decode request":                                     "не удалось разобрать тело запроса",
+	"invalid path parameter %s":                                    "некорректный параметр пути %s",
+	"invalid query parameter %s":                                   "некорректный параметр запроса %s",
+	"invalid overdue_by: %v":                                       "некорректное значение overdue_by: %v",
+	"authentication required":                                      "требуется аутентификация",
+	"invalid or expired token":                                     "токен недействителен или истёк",
+	"invalid %s header":                                            "некорректный заголовок %s",
+	"failed to read request body":                                  "не удалось прочитать тело запроса",
+	"request body is too large":                                    "тело запроса слишком большое",
+	"idempotency key was already used for a different request":     "ключ идемпотентности уже использован для другого запроса",
+	"a request with this idempotency key is still being processed": "запрос с этим ключом идемпотентности ещё выполняется",
+
+	// проверка полей
+	"field %s is a required field":                                 "поле %s обязательно",
+	"field %s must be at most %s":                                  "поле %s должно быть не больше %s",
+	"field %s must be at most %s characters":                       "длина поля %s должна быть не больше %s символов",
+	"field %s must be at most %s items":                            "поле %s должно содержать не больше %s элементов",
+	"field %s must be at least %s":                                 "поле %s должно быть не меньше %s",
+	"field %s must be at least %s characters":                      "длина поля %s должна быть не меньше %s символов",
+	"field %s must be at least %s items":                           "поле %s должно содержать не меньше %s элементов",
+	"field %s must be one of: %s":                                  "поле %s должно принимать одно из значений: %s",
+	"field %s must be in the future":                               "поле %s должно содержать время в будущем",
+	"field %s conflicts with another field":                        "поле %s несовместимо с другим полем запроса",
+	"field %s is not valid":                                        "поле %s заполнено некорректно",
+	"unknown task priority %q":                                     "неизвестный приоритет задачи %q",
+	"unknown task status %q":                                       "неизвестный статус задачи %q",
+	"unknown timezone %q":                                          "неизвестный часовой пояс %q",
+	"invalid working hours %s-%s":                                  "некорректные рабочие часы %s-%s",
+	"calendar must have at least one working weekday":              "в календаре должен быть хотя бы один рабочий день недели",
+	"invalid weekday %d":                                           "некорректный день недели %d",
+	"invalid time of day %q":                                       "некорректное время суток %q",
+	"relative deadline %q must start with '+'":                     "относительный дедлайн %q должен начинаться с '+'",
+	"relative deadline %q must contain a number":                   "относительный дедлайн %q должен содержать число",
+	"unknown unit in relative deadline %q":                         "неизвестная единица в относительном дедлайне %q",
+	"line %d: event without DTSTART":                               "строка %d: событие без DTSTART",
+	"line %d: event is longer than %d days":                        "строка %d: событие длиннее %d дней",
+	"line %d: invalid date %q":                                     "строка %d: некорректная дата %q",
+	"failed to read iCalendar file":                                "не удалось прочитать файл iCalendar",
+	"window %s requires a positive number of days":                 "для окна %s нужно положительное число дней",
+	"unknown deadline window %q":                                   "неизвестное окно дедлайнов %q",
+	"deadline range end must be after its start":                   "конец диапазона дедлайнов должен быть позже начала",
+	"unknown language %q":                                          "неподдерживаемый язык %q",
+	"unknown sort field %q":                                        "неизвестное поле сортировки %q",
+	"cursor does not match the requested sort order":               "курсор выдан для другого порядка сортировки",
+	"task list is empty":                                           "список задач пуст",
+	"batch must contain at most %d tasks":                          "операция может затрагивать не больше %d задач",
+	"task %d is listed more than once":                             "задача %d указана больше одного раза",
+	"delete cannot be combined with other changes":                 "удаление нельзя совмещать с другими изменениями",
+	"batch contains no changes":                                    "операция не содержит изменений",
+	"invalid label %q":                                             "некорректная метка %q",
+	"search query must contain at least one word":                  "поисковый запрос должен содержать хотя бы одно слово",
+	"search query must contain at most %d words":                   "поисковый запрос должен содержать не больше %d слов",
+	"invalid value":                                                "некорректное значение",
+	"task deadline is too far in the past":                         "дедлайн задачи уже прошёл",
+	"sprint end date must be after its start date":                 "дата окончания спринта должна быть позже даты начала",
+	"milestone end date must be after its start date":              "дата окончания вехи должна быть позже даты начала",
+	"overdue interval must not be negative":                        "время просрочки не может быть отрицательным",
+	"escalation rule must assign or notify the project manager":    "правило эскалации должно назначать менеджера проекта или уведомлять его",
+	"unfinished tasks cannot be carried over into the same sprint": "незавершённые задачи нельзя перенести в тот же спринт",
+	"unknown view column %q":                                       "неизвестная колонка представления %q",
+	"view sort cannot be combined with ORDER BY in the query":      "сортировку представления нельзя сочетать с ORDER BY в условии",
+
+	// язык фильтров задач, параметр q
+	"invalid query at position %d: %s":              "ошибка в запросе в позиции %d: %s",
+	"query parameters %s and %s cannot be combined": "параметры %s и %s нельзя использовать вместе",
+	"unexpected character %q":                       "недопустимый символ %q",
+	"unterminated string":                           "строка не закрыта кавычкой",
+	"unexpected end of query":                       "неожиданный конец запроса",
+	"query is longer than %d characters":            "запрос длиннее %d символов",
+	"query is nested deeper than %d levels":         "вложенность запроса больше %d уровней",
+	"unexpected %q":                                 "неожиданное %q",
+	"expected %q":                                   "ожидается %q",
+	"expected field name":                           "ожидается имя поля",
+	"expected comparison operator":                  "ожидается оператор сравнения",
+	"expected value":                                "ожидается значение",
+	"unknown field %q":                              "неизвестное поле %q",
+	"cannot sort by %s":                             "сортировка по полю %s недоступна",
+	"operator %s is not supported for field %s":     "оператор %s не поддерживается для поля %s",
+	"invalid value %q for field %s":                 "некорректное значение %q для поля %s",
+	"invalid value for field %s":                    "некорректное значение для поля %s",
+	"field %s requires a value":                     "для поля %s нужно значение",
+	"me requires an authenticated user":             "me доступно только аутентифицированному пользователю",
+	"invalid duration %q":                           "некорректная длительность %q",
+	"invalid date %q":                               "некорректная дата %q",
+	"unknown query field %q":                        "неизвестное поле запроса %q",
+	"unsupported query expression %T":               "неподдерживаемое выражение запроса %T",
+	"invalid query value %v":                        "некорректное значение в запросе %v",
+
+	// ненайденные ресурсы и конфликты
+	"task with ID %d not found":                             "задача с ID %d не найдена",
+	"user with ID %d not found":                             "пользователь с ID %d не найден",
+	"user with ID %d is not assigned to task %d":            "пользователь с ID %d не назначен на задачу %d",
+	"project with ID %d not found":                          "проект с ID %d не найден",
+	"sprint with ID %d not found":                           "спринт с ID %d не найден",
+	"view with ID %d not found":                             "представление с ID %d не найдено",
+	"user with ID %d is not subscribed to view %d":          "пользователь с ID %d не подписан на представление %d",
+	"only the owner can change view %d":                     "изменить представление %d может только его владелец",
+	"user with ID %d is not a member of project %d":         "пользователь с ID %d не участвует в проекте %d",
+	"escalation rule with ID %d not found":                  "правило эскалации с ID %d не найдено",
+	"calendar not found":                                    "календарь не найден",
+	"sprint %d is already completed":                        "спринт %d уже завершён",
+	"sprint with ID %d not found or is not active":          "спринт с ID %d не найден или не запущен",
+	"sprint with ID %d not found or is not planned":         "спринт с ID %d не найден или уже запущен",
+	"sprint %d has unfinished tasks and no next sprint":     "в спринте %d есть незавершённые задачи, а следующего спринта нет",
+	"task %d has been modified, current version is %d":      "задача %d изменилась, текущая версия %d",
+	"resource already exists":                               "ресурс уже существует",
+	"access denied":                                         "доступ запрещён",
+	"task %d has been modified":                             "задача %d изменилась",
+	"user %d is already assigned to task %d":                "пользователь %d уже назначен на задачу %d",
+	"referenced resource does not exist or is still in use": "связанный ресурс не существует или ещё используется",
+
+	// доска проекта
+	"websocket upgrade required":    "требуется подключение по WebSocket",
+	"failed to decode message":      "не удалось разобрать сообщение",
+	"unknown message type %q":       "неизвестный тип сообщения %q",
+	"invalid task ID %d":            "некорректный ID задачи %d",
+	"token belongs to another user": "токен принадлежит другому пользователю",
+	"server is shutting down":       "сервер останавливается",
+
+	// вебхуки
+	"only the manager of project %d can manage its webhooks": "управлять вебхуками проекта %d может только его руководитель",
+	"webhook URL must be an absolute http or https URL":      "адрес вебхука должен быть абсолютным URL http или https",
+	"unknown event type %q":                                  "неизвестный тип события %q",
+	"webhook with ID %d not found":                           "вебхук с ID %d не найден",
+	"delivery with ID %d not found":                          "доставка с ID %d не найдена",
+
+	// синхронизация
+	"too many mutations, at most %d are allowed":                           "слишком много изменений, допускается не больше %d",
+	"task_ref %q does not refer to a task created earlier in this request": "task_ref %q не указывает на задачу, созданную ранее в этом запросе",
+	"task_id or task_ref is required":                                      "нужен task_id или task_ref",
+	"user_id is required":                                                  "нужен user_id",
+	"unknown mutation type %q":                                             "неизвестный тип изменения %q",
+
+	// GraphQL
+	"query complexity %d exceeds the limit of %d": "сложность запроса %d превышает предел %d",
+	"limit must be between 1 and %d":              "limit должен быть от 1 до %d",
+	"graphql endpoint is disabled":                "эндпоинт GraphQL отключён",
+	"metrics are disabled":                        "метрики отключены",
+
+	// уведомления
+	"You have been assigned to task #%d":                "Вы назначены на задачу #%d",
+	"You have been removed from task #%d":               "Вы сняты с задачи #%d",
+	"Task #%d has been deleted":                         "Задача #%d удалена",
+	"Task #%d status changed to \"%s\"":                 "Статус задачи #%d изменён на «%s»",
+	"The deadline for task #%d is approaching":          "Приближается дедлайн задачи #%d",
+	"The deadline for task #%d has passed":              "Дедлайн задачи #%d прошёл",
+	"Task #%d is overdue and has been escalated to you": "Задача #%d просрочена и передана вам",
+	"%d of your tasks have been changed":                "Изменено ваших задач: %d",
+	"Task #%d now matches a view you follow":            "Задача #%d попала в представление, на которое вы подписаны",
+	"to do":                                             "к выполнению",
+	"in progress":                                       "в работе",
+	"done":                                              "выполнена",
+}
@@ -0,0 +1,57 @@
+package i18n
+
+import "Tasks/internal/model"
+
+// notificationFormats тексты уведомлений по событиям, аргумент - ID задачи
+var notificationFormats = map[string]string{
+	model.EventAddUser:             "You have been assigned to task #%d",
+	model.EventRemoveUser:          "You have been removed from task #%d",
+	model.EventDeleteTask:          "Task #%d has been deleted",
+	model.EventDeadlineApproaching: "The deadline for task #%d is approaching",
+	model.EventDeadlineMissed:      "The deadline for task #%d has passed",
+	model.EventTaskEscalated:       "Task #%d is overdue and has been escalated to you",
+	model.EventTaskEnteredView:     "Task #%d now matches a view you follow",
+}
+
+// statusNames названия статусов задачи для текстов уведомлений
+var statusNames = map[string]string{
+	model.TaskStatusTodo:       "to do",
+	model.TaskStatusInProgress: "in progress",
+	model.TaskStatusDone:       "done",
+}
+
+// Notification заполняет язык и текст уведомления. Неподдерживаемый язык заменяется языком по умолчанию.
+func Notification(msg model.NotificationMessage, lang string) model.NotificationMessage {
+	if !Supported(lang) {
+		lang = Default
+	}
+	msg.Language = lang
+
+	if msg.Event == model.EventTasksChanged {
+		changes := make([]model.NotificationChange, len(msg.Changes))
+		for i, change := range msg.Changes {
+			change.Text = text(change.Event, change.TaskID, change.ChangeStatus, lang)
+			changes[i] = change
+		}
+		msg.Changes = changes
+		msg.Text = T(lang, "%d of your tasks have been changed", len(msg.Changes))
+		return msg
+	}
+	msg.Text = text(msg.Event, msg.TaskID, msg.ChangeStatus, lang)
+	return msg
+}
+
+// text текст уведомления о событии с одной задачей
+func text(event string, taskID int, changeStatus string, lang string) string {
+	if event == model.EventChangeStatus {
+		status, ok := statusNames[changeStatus]
+		if !ok {
+			status = changeStatus
+		}
+		return T(lang, "Task #%d status changed to \"%s\"", taskID, T(lang, status))
+	}
+	if format, ok := notificationFormats[event]; ok {
+		return T(lang, format, taskID)
+	}
+	return ""
+}
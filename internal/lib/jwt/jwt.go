@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -8,6 +9,8 @@ import (
 	"Tasks/internal/model"
 )
 
+var ErrInvalidToken = errors.New("invalid token")
+
 func NewToken(user model.User, secret string, duration time.Duration) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
 
@@ -23,3 +26,42 @@ func NewToken(user model.User, secret string, duration time.Duration) (string, e
 
 	return tokenString, nil
 }
+
+// Claims данные токена доступа
+type Claims struct {
+	UserID    int
+	Login     string
+	ExpiresAt time.Time
+}
+
+// ParseToken проверяет подпись и срок действия токена, выданного NewToken, и возвращает ID пользователя
+func ParseToken(tokenString string, secret string) (int, error) {
+	claims, err := ParseClaims(tokenString, secret)
+	return claims.UserID, err
+}
+
+// ParseClaims как ParseToken, но возвращает все данные токена: долгим соединениям нужен срок его действия
+func ParseClaims(tokenString string, secret string) (Claims, error) {
+	token, err := jwt.Parse(tokenString, func(*jwt.Token) (any, error) {
+		return []byte(secret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return Claims{}, errors.Join(ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	// числа в JSON-claims разбираются как float64
+	id, ok := claims["id"].(float64)
+	if !ok || id <= 0 || id != float64(int(id)) {
+		return Claims{}, ErrInvalidToken
+	}
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return Claims{}, ErrInvalidToken
+	}
+	login, _ := claims["login"].(string)
+	return Claims{UserID: int(id), Login: login, ExpiresAt: expiresAt.Time}, nil
+}
@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/model"
+)
+
+func TestParseToken(t *testing.T) {
+	const secret = "secret"
+	valid, err := NewToken(model.User{ID: 7, Login: "user1"}, secret, time.Hour)
+	require.NoError(t, err)
+	expired, err := NewToken(model.User{ID: 7, Login: "user1"}, secret, -time.Hour)
+	require.NoError(t, err)
+	noID, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"exp": time.Now().Add(time.Hour).Unix()}).SignedString([]byte(secret))
+	require.NoError(t, err)
+	noExp, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"id": 7}).SignedString([]byte(secret))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		token   string
+		secret  string
+		want    int
+		wantErr bool
+	}{
+		{name: "valid", token: valid, secret: secret, want: 7},
+		{name: "wrong secret", token: valid, secret: "other", wantErr: true},
+		{name: "expired", token: expired, secret: secret, wantErr: true},
+		{name: "without id", token: noID, secret: secret, wantErr: true},
+		{name: "without expiration", token: noExp, secret: secret, wantErr: true},
+		{name: "garbage", token: "not.a.token", secret: secret, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseToken(tt.token, tt.secret)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidToken)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseClaims(t *testing.T) {
+	const secret = "secret"
+	token, err := NewToken(model.User{ID: 7, Login: "anna"}, secret, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := ParseClaims(token, secret)
+	require.NoError(t, err)
+	require.Equal(t, 7, claims.UserID)
+	require.Equal(t, "anna", claims.Login)
+	require.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt, time.Minute)
+
+	_, err = ParseClaims(token, "other")
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,44 @@
+// Package pagination кодирует курсоры постраничной выборки в непрозрачные для клиента строки.
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"Tasks/internal/model"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+type cursor struct {
+	Sort  string `json:"s,omitempty"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id"`
+}
+
+// Encode кодирует курсор в строку для параметра cursor. Для nil возвращает пустую строку.
+func Encode(c *model.Cursor) string {
+	if c == nil {
+		return ""
+	}
+	raw, _ := json.Marshal(cursor{Sort: c.Sort, Desc: c.Desc, Value: c.Value, ID: c.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode разбирает строку, полученную от Encode. Пустая строка - первая страница, nil.
+func Decode(s string) (*model.Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c cursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return &model.Cursor{Sort: c.Sort, Desc: c.Desc, Value: c.Value, ID: c.ID}, nil
+}
@@ -0,0 +1,40 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/model"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	c := &model.Cursor{Sort: model.SortDeadline, Desc: true, Value: "2025-01-15T10:00:00", ID: 42}
+
+	got, err := Decode(Encode(c))
+	require.NoError(t, err)
+	require.Equal(t, c, got)
+
+	require.Empty(t, Encode(nil))
+	got, err = Decode("")
+	require.NoError(t, err)
+	require.Nil(t, got)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "!!!"},
+		{name: "not json", cursor: "bm90IGpzb24"},
+		{name: "no id", cursor: Encode(&model.Cursor{Sort: model.SortDeadline})},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.cursor)
+			require.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
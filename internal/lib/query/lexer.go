@@ -0,0 +1,133 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	// tokDuration число с единицей измерения: 30m, 12h, 7d, 2w
+	tokDuration
+	tokString
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+	tokPlus
+	tokMinus
+)
+
+// token лексема запроса. Pos - позиция первого символа, считается в символах с единицы.
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// is сообщает, что лексема - ключевое слово kw. Ключевые слова не зависят от регистра.
+func (t token) is(kw string) bool {
+	return t.kind == tokIdent && strings.EqualFold(t.text, kw)
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// lex разбивает запрос на лексемы
+func lex(input string) ([]token, error) {
+	runes := []rune(input)
+	var tokens []token
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: pos})
+			i++
+		case r == '+':
+			tokens = append(tokens, token{kind: tokPlus, text: "+", pos: pos})
+			i++
+		case r == '-':
+			tokens = append(tokens, token{kind: tokMinus, text: "-", pos: pos})
+			i++
+		case r == '=' || r == '~':
+			tokens = append(tokens, token{kind: tokOp, text: string(r), pos: pos})
+			i++
+		case r == '<' || r == '>' || r == '!':
+			op := string(r)
+			if i+1 < len(runes) && (runes[i+1] == '=' || (r == '!' && runes[i+1] == '~')) {
+				op += string(runes[i+1])
+			}
+			if op == "!" {
+				return nil, errorf(pos, "unexpected character %q", string(r))
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+			i += len([]rune(op))
+		case r == '\'' || r == '"':
+			text, n, ok := lexString(runes[i:])
+			if !ok {
+				return nil, errorf(pos, "unterminated string")
+			}
+			tokens = append(tokens, token{kind: tokString, text: text, pos: pos})
+			i += n
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && unicode.IsDigit(runes[j]) {
+				j++
+			}
+			kind := tokNumber
+			if j < len(runes) && unicode.IsLetter(runes[j]) {
+				kind = tokDuration
+				for j < len(runes) && isIdentRune(runes[j]) {
+					j++
+				}
+			}
+			tokens = append(tokens, token{kind: kind, text: string(runes[i:j]), pos: pos})
+			i = j
+		case isIdentRune(r):
+			j := i
+			for j < len(runes) && isIdentRune(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[i:j]), pos: pos})
+			i = j
+		default:
+			return nil, errorf(pos, "unexpected character %q", string(r))
+		}
+	}
+	return append(tokens, token{kind: tokEOF, pos: len(runes) + 1}), nil
+}
+
+// lexString читает строку в кавычках, с которых начинается runes. Обратная косая черта экранирует
+// следующий символ. Возвращает текст без кавычек и число прочитанных символов.
+func lexString(runes []rune) (string, int, bool) {
+	quote := runes[0]
+	var b strings.Builder
+	for i := 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+				b.WriteRune(runes[i])
+			}
+		case quote:
+			return b.String(), i + 1, true
+		default:
+			b.WriteRune(runes[i])
+		}
+	}
+	return "", 0, false
+}
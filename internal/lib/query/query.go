@@ -0,0 +1,406 @@
+// Package query разбирает язык фильтров задач, например
+//
+//	status != done AND deadline < now()+7d AND assignee = me ORDER BY deadline
+//
+// Запрос состоит из условий, объединённых AND, OR и NOT со скобками, и необязательного ORDER BY.
+// Условие - поле, оператор и значение: status = done, priority >= high, title ~ "отчёт",
+// project IN (1, 2). Результат - model.TaskQuery, SQL из него строит репозиторий.
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"Tasks/internal/model"
+)
+
+// Error ошибка в запросе. Pos - позиция в символах с единицы, Format - строка формата на английском,
+// по ней ищется перевод.
+type Error struct {
+	Pos    int
+	Format string
+	Args   []any
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, fmt.Sprintf(e.Format, e.Args...))
+}
+
+func errorf(pos int, format string, args ...any) error {
+	return &Error{Pos: pos, Format: format, Args: args}
+}
+
+// Env значения, которые подставляются в запрос: now() и me
+type Env struct {
+	Now time.Time
+	// UserID аутентифицированный пользователь, 0 - запрос анонимный и me недоступен
+	UserID int
+}
+
+type fieldKind int
+
+const (
+	kindEnum fieldKind = iota
+	kindTime
+	kindID
+	kindText
+)
+
+type field struct {
+	kind fieldKind
+	ops  []string
+	// values допустимые значения перечисления
+	values []string
+	// me поле принимает me - ID аутентифицированного пользователя
+	me bool
+}
+
+var (
+	eqOps    = []string{model.OpEq, model.OpNe, model.OpIn, model.OpNotIn}
+	orderOps = []string{model.OpEq, model.OpNe, model.OpLt, model.OpLe, model.OpGt, model.OpGe}
+	textOps  = []string{model.OpEq, model.OpNe, model.OpContains, model.OpNotContains}
+)
+
+var fields = map[string]field{
+	model.QueryStatus:      {kind: kindEnum, ops: eqOps, values: model.TaskStatuses},
+	model.QueryPriority:    {kind: kindEnum, ops: append(slices.Clone(orderOps), model.OpIn, model.OpNotIn), values: model.Priorities},
+	model.QueryDeadline:    {kind: kindTime, ops: orderOps},
+	model.QueryCreatedAt:   {kind: kindTime, ops: orderOps},
+	model.QueryUpdatedAt:   {kind: kindTime, ops: orderOps},
+	model.QueryAssignee:    {kind: kindID, ops: eqOps, me: true},
+	model.QueryProject:     {kind: kindID, ops: eqOps},
+	model.QuerySprint:      {kind: kindID, ops: eqOps},
+	model.QueryTeam:        {kind: kindID, ops: eqOps},
+	model.QueryTitle:       {kind: kindText, ops: textOps},
+	model.QueryDescription: {kind: kindText, ops: textOps},
+	model.QueryText:        {kind: kindText, ops: []string{model.OpContains, model.OpNotContains}},
+}
+
+// единицы длительности в now()+7d
+var durationUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+const (
+	// MaxLength наибольшая длина запроса в символах, как у условия сохранённого представления
+	MaxLength = 2000
+	// MaxDepth наибольшая вложенность NOT и скобок
+	MaxDepth = 32
+)
+
+// Parse разбирает запрос. Ошибки возвращаются как *Error с позицией.
+func Parse(input string, env Env) (model.TaskQuery, error) {
+	if utf8.RuneCountInString(input) > MaxLength {
+		return model.TaskQuery{}, errorf(MaxLength+1, "query is longer than %d characters", MaxLength)
+	}
+	tokens, err := lex(input)
+	if err != nil {
+		return model.TaskQuery{}, err
+	}
+	p := &parser{tokens: tokens, env: env}
+	return p.parse()
+}
+
+// View разбирает условие сохранённого представления. Сортировка представления, если задана,
+// заменяет ORDER BY из условия.
+func View(view model.View, env Env) (model.TaskQuery, error) {
+	q, err := Parse(view.Query, env)
+	if err != nil {
+		return q, err
+	}
+	if view.Sort != "" {
+		q.Sort, q.Desc = view.Sort, view.Desc
+	}
+	return q, nil
+}
+
+type parser struct {
+	tokens []token
+	i      int
+	env    Env
+	// depth текущая вложенность NOT и скобок
+	depth int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func unexpected(t token) error {
+	if t.kind == tokEOF {
+		return errorf(t.pos, "unexpected end of query")
+	}
+	return errorf(t.pos, "unexpected %q", t.text)
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if t := p.peek(); t.kind != kind {
+		return errorf(t.pos, "expected %q", text)
+	}
+	p.next()
+	return nil
+}
+
+func (p *parser) parse() (model.TaskQuery, error) {
+	var q model.TaskQuery
+	if t := p.peek(); t.kind != tokEOF && !t.is("ORDER") {
+		where, err := p.parseOr()
+		if err != nil {
+			return q, err
+		}
+		q.Where = where
+	}
+	if p.peek().is("ORDER") {
+		p.next()
+		if t := p.next(); !t.is("BY") {
+			return q, errorf(t.pos, "expected %q", "BY")
+		}
+		t := p.next()
+		if t.kind != tokIdent {
+			return q, errorf(t.pos, "expected field name")
+		}
+		sort := strings.ToLower(t.text)
+		if !model.ValidSortField(sort) {
+			return q, errorf(t.pos, "cannot sort by %s", t.text)
+		}
+		q.Sort = sort
+		switch {
+		case p.peek().is("ASC"):
+			p.next()
+		case p.peek().is("DESC"):
+			p.next()
+			q.Desc = true
+		}
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return q, unexpected(t)
+	}
+	return q, nil
+}
+
+func (p *parser) parseOr() (model.QueryExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = model.QueryOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (model.QueryExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().is("AND") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = model.QueryAnd{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (model.QueryExpr, error) {
+	t := p.peek()
+	if t.is("NOT") || t.kind == tokLParen {
+		// каждый уровень - вложенное выражение в SQL, глубокие запросы база не разберёт
+		if p.depth == MaxDepth {
+			return nil, errorf(t.pos, "query is nested deeper than %d levels", MaxDepth)
+		}
+		p.depth++
+		defer func() { p.depth-- }()
+	}
+	switch {
+	case t.is("NOT"):
+		p.next()
+		expr, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return model.QueryNot{Expr: expr}, nil
+	case t.kind == tokLParen:
+		p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokRParen, ")"); err != nil {
+			return nil, err
+		}
+		return expr, nil
+	}
+	return p.parseCond()
+}
+
+func (p *parser) parseCond() (model.QueryExpr, error) {
+	name := p.next()
+	if name.kind != tokIdent {
+		if name.kind == tokEOF {
+			return nil, unexpected(name)
+		}
+		return nil, errorf(name.pos, "expected field name")
+	}
+	fieldName := strings.ToLower(name.text)
+	f, ok := fields[fieldName]
+	if !ok {
+		return nil, errorf(name.pos, "unknown field %q", name.text)
+	}
+
+	opTok := p.next()
+	var op string
+	switch {
+	case opTok.kind == tokOp:
+		op = opTok.text
+	case opTok.is("IN"):
+		op = model.OpIn
+	case opTok.is("NOT") && p.peek().is("IN"):
+		p.next()
+		op = model.OpNotIn
+	default:
+		return nil, errorf(opTok.pos, "expected comparison operator")
+	}
+	if !slices.Contains(f.ops, op) {
+		return nil, errorf(opTok.pos, "operator %s is not supported for field %s", op, fieldName)
+	}
+
+	cond := model.QueryCond{Field: fieldName, Op: op}
+	if op != model.OpIn && op != model.OpNotIn {
+		v, err := p.parseValue(fieldName, f)
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = []any{v}
+		return cond, nil
+	}
+
+	if err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	for {
+		v, err := p.parseValue(fieldName, f)
+		if err != nil {
+			return nil, err
+		}
+		cond.Values = append(cond.Values, v)
+		if p.peek().kind != tokComma {
+			break
+		}
+		p.next()
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	return cond, nil
+}
+
+// parseValue читает значение и приводит его к типу поля
+func (p *parser) parseValue(name string, f field) (any, error) {
+	t := p.next()
+	switch f.kind {
+	case kindEnum:
+		if t.kind == tokIdent || t.kind == tokString {
+			v := strings.ToLower(t.text)
+			if slices.Contains(f.values, v) {
+				return v, nil
+			}
+			return nil, errorf(t.pos, "invalid value %q for field %s", t.text, name)
+		}
+	case kindID:
+		switch {
+		case t.is("me") && f.me:
+			if p.env.UserID == 0 {
+				return nil, errorf(t.pos, "me requires an authenticated user")
+			}
+			return p.env.UserID, nil
+		case t.kind == tokNumber:
+			id, err := strconv.Atoi(t.text)
+			if err != nil || id <= 0 {
+				return nil, errorf(t.pos, "invalid value %q for field %s", t.text, name)
+			}
+			return id, nil
+		}
+	case kindText:
+		if t.kind == tokString || t.kind == tokIdent || t.kind == tokNumber {
+			return t.text, nil
+		}
+	case kindTime:
+		switch {
+		case t.is("now"):
+			return p.parseNow()
+		case t.kind == tokString:
+			return parseTime(t)
+		}
+	}
+	if t.kind == tokEOF {
+		return nil, unexpected(t)
+	}
+	return nil, errorf(t.pos, "expected value")
+}
+
+// parseNow читает остаток now() и необязательное смещение +7d или -2h
+func (p *parser) parseNow() (any, error) {
+	if err := p.expect(tokLParen, "("); err != nil {
+		return nil, err
+	}
+	if err := p.expect(tokRParen, ")"); err != nil {
+		return nil, err
+	}
+	now := p.env.Now.UTC()
+
+	sign := p.peek()
+	if sign.kind != tokPlus && sign.kind != tokMinus {
+		return now, nil
+	}
+	p.next()
+	t := p.next()
+	if t.kind != tokDuration {
+		return nil, errorf(t.pos, "invalid duration %q", t.text)
+	}
+	digits := strings.TrimRightFunc(t.text, func(r rune) bool { return !('0' <= r && r <= '9') })
+	unit, ok := durationUnits[strings.ToLower(t.text[len(digits):])]
+	n, err := strconv.Atoi(digits)
+	if !ok || err != nil {
+		return nil, errorf(t.pos, "invalid duration %q", t.text)
+	}
+	offset := time.Duration(n) * unit
+	if sign.kind == tokMinus {
+		offset = -offset
+	}
+	return now.Add(offset), nil
+}
+
+// parseTime разбирает дату "2025-06-01" (полночь UTC) или время в RFC 3339
+func parseTime(t token) (any, error) {
+	if v, err := time.Parse(time.DateOnly, t.text); err == nil {
+		return v, nil
+	}
+	if v, err := time.Parse(time.RFC3339, t.text); err == nil {
+		return v.UTC(), nil
+	}
+	return nil, errorf(t.pos, "invalid date %q", t.text)
+}
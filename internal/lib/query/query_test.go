@@ -0,0 +1,138 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/model"
+)
+
+var env = Env{Now: time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC), UserID: 7}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  model.TaskQuery
+	}{
+		{name: "empty", input: "  ", want: model.TaskQuery{}},
+		{
+			name:  "example from docs",
+			input: "status != done AND deadline < now()+7d AND assignee = me ORDER BY deadline",
+			want: model.TaskQuery{
+				Where: model.QueryAnd{
+					Left: model.QueryAnd{
+						Left:  model.QueryCond{Field: model.QueryStatus, Op: model.OpNe, Values: []any{model.TaskStatusDone}},
+						Right: model.QueryCond{Field: model.QueryDeadline, Op: model.OpLt, Values: []any{env.Now.Add(7 * 24 * time.Hour)}},
+					},
+					Right: model.QueryCond{Field: model.QueryAssignee, Op: model.OpEq, Values: []any{7}},
+				},
+				Sort: model.SortDeadline,
+			},
+		},
+		{
+			name:  "AND binds tighter than OR",
+			input: "priority = high or status = todo and project = 3",
+			want: model.TaskQuery{Where: model.QueryOr{
+				Left: model.QueryCond{Field: model.QueryPriority, Op: model.OpEq, Values: []any{"high"}},
+				Right: model.QueryAnd{
+					Left:  model.QueryCond{Field: model.QueryStatus, Op: model.OpEq, Values: []any{"todo"}},
+					Right: model.QueryCond{Field: model.QueryProject, Op: model.OpEq, Values: []any{3}},
+				},
+			}},
+		},
+		{
+			name:  "parentheses and NOT",
+			input: `NOT (title ~ "отчёт" OR text !~ 'draft \'v2\'')`,
+			want: model.TaskQuery{Where: model.QueryNot{Expr: model.QueryOr{
+				Left:  model.QueryCond{Field: model.QueryTitle, Op: model.OpContains, Values: []any{"отчёт"}},
+				Right: model.QueryCond{Field: model.QueryText, Op: model.OpNotContains, Values: []any{"draft 'v2'"}},
+			}}},
+		},
+		{
+			name:  "IN lists",
+			input: "sprint IN (1, 2) AND assignee NOT IN (me, 3)",
+			want: model.TaskQuery{Where: model.QueryAnd{
+				Left:  model.QueryCond{Field: model.QuerySprint, Op: model.OpIn, Values: []any{1, 2}},
+				Right: model.QueryCond{Field: model.QueryAssignee, Op: model.OpNotIn, Values: []any{7, 3}},
+			}},
+		},
+		{
+			name:  "dates and descending order",
+			input: `created_at >= "2025-01-01" AND updated_at < "2025-02-01T10:00:00+03:00" AND deadline > now()-2h ORDER BY priority DESC`,
+			want: model.TaskQuery{
+				Where: model.QueryAnd{
+					Left: model.QueryAnd{
+						Left:  model.QueryCond{Field: model.QueryCreatedAt, Op: model.OpGe, Values: []any{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}},
+						Right: model.QueryCond{Field: model.QueryUpdatedAt, Op: model.OpLt, Values: []any{time.Date(2025, 2, 1, 7, 0, 0, 0, time.UTC)}},
+					},
+					Right: model.QueryCond{Field: model.QueryDeadline, Op: model.OpGt, Values: []any{env.Now.Add(-2 * time.Hour)}},
+				},
+				Sort: model.SortPriority,
+				Desc: true,
+			},
+		},
+		{name: "order only", input: "order by updated_at asc", want: model.TaskQuery{Sort: model.SortUpdatedAt}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, env)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		env    Env
+		pos    int
+		format string
+	}{
+		{name: "unknown field", input: "status = done AND stat = todo", env: env, pos: 19, format: "unknown field %q"},
+		{name: "unexpected character", input: "status # done", env: env, pos: 8, format: "unexpected character %q"},
+		{name: "unterminated string", input: `title = "abc`, env: env, pos: 9, format: "unterminated string"},
+		{name: "unsupported operator", input: "status < done", env: env, pos: 8, format: "operator %s is not supported for field %s"},
+		{name: "invalid enum value", input: "status = paused", env: env, pos: 10, format: "invalid value %q for field %s"},
+		{name: "missing value", input: "deadline <", env: env, pos: 11, format: "unexpected end of query"},
+		{name: "invalid duration", input: "deadline < now()+7y", env: env, pos: 18, format: "invalid duration %q"},
+		{name: "invalid date", input: `deadline < "tomorrow"`, env: env, pos: 12, format: "invalid date %q"},
+		{name: "unclosed parenthesis", input: "(status = done", env: env, pos: 15, format: "expected %q"},
+		{name: "trailing token", input: "status = done done", env: env, pos: 15, format: "unexpected %q"},
+		{name: "me for project", input: "project = me", env: env, pos: 11, format: "expected value"},
+		{name: "me for anonymous", input: "assignee = me", pos: 12, format: "me requires an authenticated user"},
+		{name: "bad sort field", input: "ORDER BY title", env: env, pos: 10, format: "cannot sort by %s"},
+		{name: "positions count characters", input: "title ~ 'ёж' AND x = 1", env: env, pos: 18, format: "unknown field %q"},
+		{name: "too long", input: "title ~ '" + strings.Repeat("ж", MaxLength) + "'", env: env, pos: MaxLength + 1,
+			format: "query is longer than %d characters"},
+		{name: "too deep", input: strings.Repeat("NOT ", MaxDepth+1) + "status = done", env: env, pos: 4*MaxDepth + 1,
+			format: "query is nested deeper than %d levels"},
+		{name: "too deep parentheses", input: strings.Repeat("(", MaxDepth+1) + "status = done", env: env, pos: MaxDepth + 1,
+			format: "query is nested deeper than %d levels"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, tt.env)
+			var qErr *Error
+			require.True(t, errors.As(err, &qErr), "expected *Error, got %v", err)
+			require.Equal(t, tt.pos, qErr.Pos)
+			require.Equal(t, tt.format, qErr.Format)
+		})
+	}
+}
+
+func TestParse_Limits(t *testing.T) {
+	// запросы на границе ограничений разбираются
+	_, err := Parse(strings.Repeat("NOT ", MaxDepth)+"status = done", env)
+	require.NoError(t, err)
+	_, err = Parse("title ~ '"+strings.Repeat("ж", MaxLength-10)+"'", env)
+	require.NoError(t, err)
+}
@@ -0,0 +1,40 @@
+// Package synctoken кодирует номер изменения синхронизации в непрозрачный для клиента токен.
+package synctoken
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidToken = errors.New("invalid sync token")
+
+// version формата токена: токен другого формата отклоняется, и клиент синхронизируется заново
+const version = 1
+
+type token struct {
+	Version int   `json:"v"`
+	Seq     int64 `json:"s"`
+}
+
+// Encode кодирует номер изменения в токен для параметра since
+func Encode(seq int64) string {
+	raw, _ := json.Marshal(token{Version: version, Seq: seq})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// Decode разбирает строку, полученную от Encode. Пустая строка - синхронизация с начала, 0.
+func Decode(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, ErrInvalidToken
+	}
+	var t token
+	if err := json.Unmarshal(raw, &t); err != nil || t.Version != version || t.Seq < 0 {
+		return 0, ErrInvalidToken
+	}
+	return t.Seq, nil
+}
@@ -0,0 +1,38 @@
+package synctoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	for _, seq := range []int64{0, 1, 1 << 40} {
+		got, err := Decode(Encode(seq))
+		require.NoError(t, err)
+		require.Equal(t, seq, got)
+	}
+
+	got, err := Decode("")
+	require.NoError(t, err)
+	require.Zero(t, got)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "not base64", token: "!!!"},
+		{name: "not json", token: "bm90IGpzb24"},
+		{name: "unknown version", token: "eyJ2IjoyLCJzIjo1fQ"},
+		{name: "negative seq", token: "eyJ2IjoxLCJzIjotNX0"},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(tt.token)
+			require.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+}
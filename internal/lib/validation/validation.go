@@ -0,0 +1,52 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"Tasks/internal/model"
+)
+
+// Правила, которых нет в validator
+const (
+	// Future время позже текущего момента. Нулевое время не проверяется, его обязательность задаёт required.
+	Future = "future"
+	// TaskStatus допустимый статус задачи
+	TaskStatus = "task_status"
+	// Priority допустимый приоритет задачи, пустая строка разрешена
+	Priority = "priority"
+)
+
+// New возвращает валидатор, который называет поля в ошибках по их JSON-тегам
+func New() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(jsonName)
+	// регистрация падает только на пустом имени правила или функции
+	_ = v.RegisterValidation(Future, future)
+	_ = v.RegisterValidation(TaskStatus, func(fl validator.FieldLevel) bool {
+		return model.ValidStatus(fl.Field().String())
+	})
+	_ = v.RegisterValidation(Priority, func(fl validator.FieldLevel) bool {
+		return fl.Field().String() == "" || model.ValidPriority(fl.Field().String())
+	})
+	return v
+}
+
+func jsonName(fld reflect.StructField) string {
+	name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+func future(fl validator.FieldLevel) bool {
+	t, ok := fl.Field().Interface().(time.Time)
+	if !ok {
+		return false
+	}
+	return t.IsZero() || t.After(time.Now())
+}
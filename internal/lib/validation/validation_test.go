@@ -0,0 +1,61 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type request struct {
+	Title    string    `json:"title" validate:"required,max=255"`
+	Deadline time.Time `json:"deadline" validate:"future"`
+	Status   string    `json:"status" validate:"omitempty,task_status"`
+	Priority string    `json:"priority" validate:"priority"`
+}
+
+func TestNew(t *testing.T) {
+	valid := request{Title: "task", Deadline: time.Now().Add(time.Hour), Status: "in_progress", Priority: "high"}
+
+	tests := []struct {
+		name   string
+		modify func(r *request)
+		field  string
+		rule   string
+	}{
+		{name: "valid"},
+		{name: "zero deadline is left to required", modify: func(r *request) { r.Deadline = time.Time{} }},
+		{name: "empty priority", modify: func(r *request) { r.Priority = "" }},
+		{name: "title too long", modify: func(r *request) { r.Title = strings.Repeat("a", 256) }, field: "title", rule: "max"},
+		{name: "deadline in the past", modify: func(r *request) { r.Deadline = time.Now().Add(-time.Minute) }, field: "deadline", rule: Future},
+		{name: "unknown status", modify: func(r *request) { r.Status = "paused" }, field: "status", rule: TaskStatus},
+		{name: "unknown priority", modify: func(r *request) { r.Priority = "urgent" }, field: "priority", rule: Priority},
+	}
+
+	v := New()
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := valid
+			if tt.modify != nil {
+				tt.modify(&req)
+			}
+			err := v.Struct(req)
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var errs validator.ValidationErrors
+			if !errors.As(err, &errs) || len(errs) != 1 {
+				t.Fatalf("expected one validation error, got %v", err)
+			}
+			if errs[0].Field() != tt.field || errs[0].Tag() != tt.rule {
+				t.Errorf("expected %s/%s, got %s/%s", tt.field, tt.rule, errs[0].Field(), errs[0].Tag())
+			}
+		})
+	}
+}
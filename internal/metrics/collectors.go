@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// poolCollector статистика пула соединений pgx, снимается при каждом сборе метрик
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired, idle, total, max           *prometheus.Desc
+	acquires, emptyAcquires, canceled    *prometheus.Desc
+	acquireDuration, newConns, destroyed *prometheus.Desc
+}
+
+func NewPoolCollector(pool *pgxpool.Pool) prometheus.Collector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "pgxpool", name), help, nil, nil)
+	}
+	return &poolCollector{
+		pool:            pool,
+		acquired:        desc("acquired_conns", "Connections currently acquired from the pool."),
+		idle:            desc("idle_conns", "Idle connections in the pool."),
+		total:           desc("total_conns", "Total connections in the pool."),
+		max:             desc("max_conns", "Maximum size of the pool."),
+		acquires:        desc("acquires_total", "Successful connection acquires."),
+		emptyAcquires:   desc("empty_acquires_total", "Acquires that had to wait for a connection because the pool was empty."),
+		canceled:        desc("canceled_acquires_total", "Acquires canceled by their context."),
+		acquireDuration: desc("acquire_duration_seconds_total", "Total time spent acquiring connections."),
+		newConns:        desc("new_conns_total", "Connections opened by the pool."),
+		destroyed:       desc("destroyed_conns_total", "Connections closed because they exceeded MaxConnLifetime or MaxConnIdleTime."),
+	}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.acquired, c.idle, c.total, c.max, c.acquires, c.emptyAcquires,
+		c.canceled, c.acquireDuration, c.newConns, c.destroyed} {
+		ch <- d
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(s.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(s.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(s.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquires, prometheus.CounterValue, float64(s.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(s.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.acquireDuration, prometheus.CounterValue, s.AcquireDuration().Seconds())
+	ch <- prometheus.MustNewConstMetric(c.newConns, prometheus.CounterValue, float64(s.NewConnsCount()))
+	ch <- prometheus.MustNewConstMetric(c.destroyed, prometheus.CounterValue,
+		float64(s.MaxLifetimeDestroyCount()+s.MaxIdleDestroyCount()))
+}
+
+// TaskCounter источник показателей задач, обычно Service.TaskCounts
+type TaskCounter func(ctx context.Context) (model.TaskCounts, error)
+
+// taskCollector незавершённые задачи по статусам и просроченные. Считаются запросом к базе при сборе
+// метрик, запрос ограничен timeout; при ошибке показатели не отдаются, а не обнуляются.
+type taskCollector struct {
+	log     *slog.Logger
+	count   TaskCounter
+	timeout time.Duration
+
+	open, overdue *prometheus.Desc
+}
+
+func NewTaskCollector(log *slog.Logger, count TaskCounter, timeout time.Duration) prometheus.Collector {
+	return &taskCollector{
+		log:     log.With(slog.String("component", "metrics/tasks")),
+		count:   count,
+		timeout: timeout,
+		open: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "open_tasks"),
+			"Unfinished tasks by status.", []string{"status"}, nil),
+		overdue: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "overdue_tasks"),
+			"Unfinished tasks past their deadline.", nil, nil),
+	}
+}
+
+func (c *taskCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.open
+	ch <- c.overdue
+}
+
+func (c *taskCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+	counts, err := c.count(ctx)
+	if err != nil {
+		c.log.Error("failed to count tasks", sl.Err(err))
+		return
+	}
+	// статусы без задач отдаются нулями, чтобы ряд не пропадал с графика
+	for _, status := range model.TaskStatuses {
+		if status == model.TaskStatusDone {
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(counts.ByStatus[status]), status)
+	}
+	ch <- prometheus.MustNewConstMetric(c.overdue, prometheus.GaugeValue, float64(counts.Overdue))
+}
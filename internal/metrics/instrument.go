@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/model"
+)
+
+// cache считает попадания и промахи чтения задач из кэша. Задачи читаются из кэша только в
+// Service.TaskByID, поэтому доля попаданий - это доля TaskByID, обслуженных без базы.
+type cache struct {
+	interfaces.CacheRepository
+	m *Metrics
+}
+
+// Cache оборачивает кэш задач подсчётом попаданий
+func (m *Metrics) Cache(repo interfaces.CacheRepository) interfaces.CacheRepository {
+	return &cache{CacheRepository: repo, m: m}
+}
+
+func (c *cache) GetTaskFromCache(ctx context.Context, taskID int) (model.Task, error) {
+	task, err := c.CacheRepository.GetTaskFromCache(ctx, taskID)
+	switch {
+	case err == nil:
+		c.m.CacheRequests.WithLabelValues(CacheHit).Inc()
+	case errors.Is(err, redis.Nil):
+		c.m.CacheRequests.WithLabelValues(CacheMiss).Inc()
+	default:
+		c.m.CacheRequests.WithLabelValues(CacheError).Inc()
+	}
+	return task, err
+}
+
+// broker измеряет время подтверждения сообщений Kafka и считает отказы
+type broker struct {
+	interfaces.Broker
+	m *Metrics
+}
+
+// Broker оборачивает отправку сообщений в Kafka метриками
+func (m *Metrics) Broker(b interfaces.Broker) interfaces.Broker {
+	return &broker{Broker: b, m: m}
+}
+
+func (b *broker) Produce(message []byte, topic string) error {
+	start := time.Now()
+	err := b.Broker.Produce(message, topic)
+	b.m.KafkaDuration.WithLabelValues(topic).Observe(time.Since(start).Seconds())
+	if err != nil {
+		b.m.KafkaFailures.WithLabelValues(topic).Inc()
+	}
+	return err
+}
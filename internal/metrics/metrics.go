@@ -0,0 +1,87 @@
+// Package metrics метрики Prometheus: HTTP-запросы, пул PostgreSQL, кэш задач, отправка в Kafka
+// и бизнес-показатели задач.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "tasks"
+
+// Результаты чтения кэша, метка result у cache_requests_total
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+)
+
+// Metrics метрики приложения в отдельном реестре: в /metrics попадает только то, что зарегистрировано здесь
+type Metrics struct {
+	registry *prometheus.Registry
+
+	// HTTPRequests и HTTPDuration по шаблону маршрута chi, методу и статусу ответа
+	HTTPRequests *prometheus.CounterVec
+	HTTPDuration *prometheus.HistogramVec
+	// CacheRequests чтения задачи из кэша в Service.TaskByID по результату
+	CacheRequests *prometheus.CounterVec
+	// KafkaDuration и KafkaFailures отправка сообщений в Kafka по топику
+	KafkaDuration *prometheus.HistogramVec
+	KafkaFailures *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		HTTPRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by route pattern, method and status code.",
+		}, []string{"route", "method", "status"}),
+		HTTPDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route pattern, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		CacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_requests_total",
+			Help:      "Task cache reads by result: hit, miss or error.",
+		}, []string{"result"}),
+		KafkaDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "kafka_produce_duration_seconds",
+			Help:      "Time until Kafka acknowledges a produced message, by topic.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"topic"}),
+		KafkaFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "kafka_produce_failures_total",
+			Help:      "Messages Kafka failed to accept, by topic.",
+		}, []string{"topic"}),
+	}
+	// результаты кэша видны с нулями ещё до первого чтения, иначе доля попаданий не считается
+	for _, result := range []string{CacheHit, CacheMiss, CacheError} {
+		m.CacheRequests.WithLabelValues(result)
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.HTTPRequests, m.HTTPDuration, m.CacheRequests, m.KafkaDuration, m.KafkaFailures,
+	)
+	return m
+}
+
+// Register добавляет сборщики, например статистику пула или показатели задач
+func (m *Metrics) Register(cs ...prometheus.Collector) {
+	m.registry.MustRegister(cs...)
+}
+
+// Handler отдаёт метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,116 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+// scrape тело ответа /metrics
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rr.Code)
+	body, err := io.ReadAll(rr.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics_Cache(t *testing.T) {
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("GetTaskFromCache", mock.Anything, 1).Return(model.Task{ID: 1}, nil).Twice()
+	cacheMock.On("GetTaskFromCache", mock.Anything, 2).Return(model.Task{}, redis.Nil).Once()
+	cacheMock.On("GetTaskFromCache", mock.Anything, 3).Return(model.Task{}, errors.New("connection refused")).Once()
+
+	m := New()
+	cache := m.Cache(cacheMock)
+	for _, id := range []int{1, 1, 2, 3} {
+		_, _ = cache.GetTaskFromCache(context.Background(), id)
+	}
+
+	body := scrape(t, m)
+	require.Contains(t, body, `tasks_cache_requests_total{result="hit"} 2`)
+	require.Contains(t, body, `tasks_cache_requests_total{result="miss"} 1`)
+	require.Contains(t, body, `tasks_cache_requests_total{result="error"} 1`)
+}
+
+type brokerFunc func(message []byte, topic string) error
+
+func (f brokerFunc) Produce(message []byte, topic string) error { return f(message, topic) }
+
+func TestMetrics_Broker(t *testing.T) {
+	m := New()
+	b := m.Broker(brokerFunc(func(_ []byte, topic string) error {
+		if topic == "broken" {
+			return errors.New("message timed out")
+		}
+		return nil
+	}))
+	require.NoError(t, b.Produce([]byte("{}"), "notifications"))
+	require.Error(t, b.Produce([]byte("{}"), "broken"))
+
+	body := scrape(t, m)
+	require.Contains(t, body, `tasks_kafka_produce_duration_seconds_count{topic="notifications"} 1`)
+	require.Contains(t, body, `tasks_kafka_produce_duration_seconds_count{topic="broken"} 1`)
+	require.Contains(t, body, `tasks_kafka_produce_failures_total{topic="broken"} 1`)
+	require.NotContains(t, body, `tasks_kafka_produce_failures_total{topic="notifications"}`)
+}
+
+func TestTaskCollector(t *testing.T) {
+	tests := []struct {
+		name    string
+		counts  model.TaskCounts
+		err     error
+		want    []string
+		notWant []string
+	}{
+		{
+			name:   "counts",
+			counts: model.TaskCounts{ByStatus: map[string]int{model.TaskStatusTodo: 4}, Overdue: 2},
+			want: []string{
+				`tasks_open_tasks{status="todo"} 4`,
+				// статус без задач отдаётся нулём
+				`tasks_open_tasks{status="in_progress"} 0`,
+				`tasks_overdue_tasks 2`,
+			},
+			notWant: []string{`status="done"`},
+		},
+		{
+			name:    "storage error",
+			err:     errors.New("connection refused"),
+			notWant: []string{"tasks_open_tasks", "tasks_overdue_tasks"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := New()
+			m.Register(NewTaskCollector(slogdiscard.NewDiscardLogger(), func(ctx context.Context) (model.TaskCounts, error) {
+				_, ok := ctx.Deadline()
+				require.True(t, ok)
+				return tt.counts, tt.err
+			}, time.Second))
+
+			body := scrape(t, m)
+			for _, line := range tt.want {
+				require.Contains(t, body, line)
+			}
+			for _, s := range tt.notWant {
+				require.NotContains(t, body, s)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package model
+
+// MaxBatchTasks наибольшее число задач в одной групповой операции
+const MaxBatchTasks = 100
+
+// TaskBatch групповое изменение задач. Пустые поля не меняются, Labels = nil оставляет метки как есть,
+// пустой список снимает все метки. Delete не сочетается с другими изменениями.
+type TaskBatch struct {
+	TaskIDs         []int
+	Status          string
+	AddAssignees    []int
+	RemoveAssignees []int
+	Labels          []string
+	Delete          bool
+}
+
+// TaskBatchResult результат групповой операции для одной задачи. При Err изменения задачи отменены.
+// Task заполнена, если изменилась сама задача (статус или метки). Assignees - исполнители до изменения,
+// Added и Removed - фактически назначенные и снятые пользователи. ProjectID - проект задачи.
+type TaskBatchResult struct {
+	TaskID    int
+	ProjectID int
+	Task      Task
+	Assignees []int
+	Added     []int
+	Removed   []int
+	Err       error
+}
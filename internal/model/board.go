@@ -0,0 +1,45 @@
+package model
+
+// Типы сообщений канала доски проекта. Клиент отправляет auth, view и typing,
+// сервер - ready, task_event, presence, typing и error.
+const (
+	BoardAuth      = "auth"
+	BoardView      = "view"
+	BoardTyping    = "typing"
+	BoardReady     = "ready"
+	BoardTaskEvent = "task_event"
+	BoardPresence  = "presence"
+	BoardError     = "error"
+)
+
+// BoardMessage сообщение канала доски в обе стороны. Token - токен доступа в auth,
+// TaskID - задача, которую пользователь открыл (view, 0 - только доска) или комментирует (typing).
+type BoardMessage struct {
+	Type     string     `json:"type"`
+	Token    string     `json:"token,omitempty"`
+	TaskID   int        `json:"task_id,omitempty"`
+	UserID   int        `json:"user_id,omitempty"`
+	Login    string     `json:"login,omitempty"`
+	Event    *TaskEvent `json:"event,omitempty"`
+	Presence []Presence `json:"presence,omitempty"`
+	Code     string     `json:"code,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Presence пользователь, открывший доску проекта, и задача, которую он просматривает
+type Presence struct {
+	UserID int    `json:"user_id"`
+	Login  string `json:"login,omitempty"`
+	TaskID int    `json:"task_id,omitempty"`
+}
+
+// BoardSignal сигнал для всех экземпляров приложения: BoardPresence - на доске изменилось присутствие,
+// BoardTyping - пользователь набирает комментарий. ConnID - соединение, от которого пришёл сигнал.
+type BoardSignal struct {
+	Type      string
+	ProjectID int
+	ConnID    string
+	UserID    int
+	Login     string
+	TaskID    int
+}
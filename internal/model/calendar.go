@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Calendar рабочий календарь. WorkStart и WorkEnd в формате "09:00",
+// Weekends - номера выходных дней недели (0 - воскресенье).
+type Calendar struct {
+	ID        int
+	Name      string
+	Timezone  string
+	WorkStart string
+	WorkEnd   string
+	Weekends  []int
+	Holidays  []Holiday
+}
+
+type Holiday struct {
+	Date time.Time
+	Name string
+}
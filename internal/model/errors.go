@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Виды доменных ошибок. Проверяются через errors.Is и определяют HTTP-статус ответа.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized вызывающий не аутентифицирован или его токен недействителен
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrPreconditionFailed ресурс изменился после того, как клиент его прочитал
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error доменная ошибка. Текст не содержит внутренних подробностей и может быть показан клиенту.
+// Format - строка формата на английском, по ней же ищется перевод.
+type Error struct {
+	Kind   error
+	Format string
+	Args   []any
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(e.Format, e.Args...)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+func NotFound(format string, args ...any) error {
+	return &Error{Kind: ErrNotFound, Format: format, Args: args}
+}
+
+func Conflict(format string, args ...any) error {
+	return &Error{Kind: ErrConflict, Format: format, Args: args}
+}
+
+func Forbidden(format string, args ...any) error {
+	return &Error{Kind: ErrForbidden, Format: format, Args: args}
+}
+
+func Invalid(format string, args ...any) error {
+	return &Error{Kind: ErrValidation, Format: format, Args: args}
+}
+
+func Unauthorized(format string, args ...any) error {
+	return &Error{Kind: ErrUnauthorized, Format: format, Args: args}
+}
+
+func PreconditionFailed(format string, args ...any) error {
+	return &Error{Kind: ErrPreconditionFailed, Format: format, Args: args}
+}
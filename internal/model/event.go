@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Типы событий потока изменений задач
+const (
+	TaskEventCreated       = "task_created"
+	TaskEventUpdated       = "task_updated"
+	TaskEventStatusChanged = "task_status_changed"
+	TaskEventAssigned      = "task_assigned"
+	TaskEventUnassigned    = "task_unassigned"
+	TaskEventDeleted       = "task_deleted"
+)
+
+// TaskEvent изменение задачи в потоке событий. ID назначает журнал событий при публикации, ID растут
+// в порядке публикации. Task - задача после изменения, у удалённой задачи не заполнена. UserID - назначенный
+// или снятый исполнитель. Assignees - исполнители на момент события, снятый исполнитель в них входит:
+// по ним и по ProjectID определяется, кому видно событие.
+type TaskEvent struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	TaskID    int       `json:"task_id"`
+	ProjectID int       `json:"project_id,omitempty"`
+	UserID    int       `json:"user_id,omitempty"`
+	Task      *Task     `json:"task,omitempty"`
+	Assignees []int     `json:"assignees,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// EventScope что видит пользователь в потоке событий: администратор видит всё, остальные - задачи,
+// исполнитель которых входит в Users (сам пользователь и его коллеги по командам), и задачи проектов
+// из Projects, которыми он руководит. Совпадает с условием видимости в списках задач.
+type EventScope struct {
+	UserID   int
+	Admin    bool
+	Users    []int
+	Projects []int
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// TaskFilter условия выборки задач. Нулевые значения полей выборку не ограничивают.
+type TaskFilter struct {
+	UserID    int
+	ProjectID int
+	TeamID    int
+	SprintID  int
+
+	// Statuses задачи в любом из перечисленных статусов
+	Statuses []string
+	// Text подстрока названия или описания задачи без учёта регистра
+	Text string
+	// VisibleTo оставляет только задачи, которые видит пользователь с этим ID
+	VisibleTo int
+	// Query условие на языке фильтров, nil - без условия
+	Query *TaskQuery
+
+	DeadlineFrom   time.Time
+	DeadlineTo     time.Time
+	UnfinishedOnly bool
+}
+
+// DeadlineWindow окно дедлайнов: именованное (Name, Days) или произвольный диапазон From-To
+type DeadlineWindow struct {
+	Name string
+	Days int
+	From time.Time
+	To   time.Time
+}
@@ -0,0 +1,10 @@
+package model
+
+// IdempotentResponse ответ на запрос с ключом идемпотентности. Fingerprint - отпечаток запроса,
+// по нему повтор с другим телом отличается от настоящего повтора. Status = 0 - запрос ещё выполняется.
+type IdempotentResponse struct {
+	Fingerprint string
+	Status      int
+	Header      map[string][]string
+	Body        []byte
+}
@@ -2,10 +2,41 @@ package model
 
 import "time"
 
+// События уведомлений
+const (
+	EventAddUser      = "add_user"
+	EventRemoveUser   = "remove_user"
+	EventChangeStatus = "change_status"
+	EventDeleteTask   = "delete_task"
+
+	EventDeadlineApproaching = "deadline_approaching"
+	EventDeadlineMissed      = "deadline_missed"
+	EventTaskEscalated       = "task_escalated"
+	EventTaskEnteredView     = "task_entered_view"
+
+	// EventTasksChanged сводное уведомление о групповой операции, изменения перечислены в Changes
+	EventTasksChanged = "tasks_changed"
+)
+
+// NotificationMessage Text - текст уведомления на языке получателя Language.
+// ViewID заполняется для EventTaskEnteredView, Changes - для EventTasksChanged.
 type NotificationMessage struct {
 	Event        string
 	Timestamp    time.Time
 	TaskID       int
 	UserID       int
+	ViewID       int
 	ChangeStatus string
+	Deadline     time.Time
+	Language     string
+	Text         string
+	Changes      []NotificationChange `json:",omitempty"`
+}
+
+// NotificationChange изменение одной задачи в сводном уведомлении
+type NotificationChange struct {
+	Event        string
+	TaskID       int
+	ChangeStatus string `json:",omitempty"`
+	Text         string
 }
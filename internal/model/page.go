@@ -0,0 +1,54 @@
+package model
+
+// Поля сортировки списков задач
+const (
+	SortDeadline  = "deadline"
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+	SortPriority  = "priority"
+)
+
+// TaskSortFields допустимые поля сортировки задач
+var TaskSortFields = []string{SortDeadline, SortCreatedAt, SortUpdatedAt, SortPriority}
+
+// Размер страницы по умолчанию и максимальный
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageRequest параметры страницы. Limit = 0 - без ограничения, используется устаревшими маршрутами.
+// After - курсор последней записи предыдущей страницы, nil - первая страница.
+type PageRequest struct {
+	Limit     int
+	Sort      string
+	Desc      bool
+	After     *Cursor
+	WithTotal bool
+}
+
+// Cursor позиция в выборке: значение поля сортировки и ID последней записи.
+// Sort и Desc запоминают порядок, в котором курсор был выдан.
+type Cursor struct {
+	Sort  string
+	Desc  bool
+	Value string
+	ID    int
+}
+
+// Page страница выборки. Next - курсор следующей страницы, nil - страница последняя.
+// Total заполняется, только если он был запрошен.
+type Page[T any] struct {
+	Items []T
+	Next  *Cursor
+	Total *int
+}
+
+func ValidSortField(field string) bool {
+	for _, f := range TaskSortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,31 @@
+package model
+
+import "time"
+
+type Project struct {
+	ID        int
+	Name      string
+	ManagerID int
+	CreatedAt time.Time
+}
+
+// EscalationRule правило эскалации: если задача проекта с приоритетом Priority
+// просрочена на OverdueBy, менеджер проекта назначается исполнителем и/или получает уведомление.
+type EscalationRule struct {
+	ID            int
+	ProjectID     int
+	ManagerID     int
+	Priority      string
+	OverdueBy     time.Duration
+	AssignManager bool
+	NotifyManager bool
+	Enabled       bool
+}
+
+// EscalationNotice уведомление руководителя об эскалации, которое ещё не отправлено в Kafka
+type EscalationNotice struct {
+	RuleID   int
+	TaskID   int
+	UserID   int
+	Deadline time.Time
+}
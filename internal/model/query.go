@@ -0,0 +1,69 @@
+package model
+
+// Поля языка фильтров задач
+const (
+	QueryStatus      = "status"
+	QueryPriority    = "priority"
+	QueryDeadline    = "deadline"
+	QueryCreatedAt   = "created_at"
+	QueryUpdatedAt   = "updated_at"
+	QueryAssignee    = "assignee"
+	QueryProject     = "project"
+	QuerySprint      = "sprint"
+	QueryTeam        = "team"
+	QueryTitle       = "title"
+	QueryDescription = "description"
+	QueryText        = "text"
+)
+
+// Операторы сравнения языка фильтров. Contains и NotContains - поиск подстроки без учёта регистра.
+const (
+	OpEq          = "="
+	OpNe          = "!="
+	OpLt          = "<"
+	OpLe          = "<="
+	OpGt          = ">"
+	OpGe          = ">="
+	OpContains    = "~"
+	OpNotContains = "!~"
+	OpIn          = "IN"
+	OpNotIn       = "NOT IN"
+)
+
+// TaskQuery разобранный запрос на языке фильтров задач. Where = nil - без условий,
+// Sort = "" - порядок сортировки не задан.
+type TaskQuery struct {
+	Where QueryExpr
+	Sort  string
+	Desc  bool
+}
+
+// QueryExpr узел условия: QueryAnd, QueryOr, QueryNot или QueryCond
+type QueryExpr interface {
+	queryExpr()
+}
+
+type QueryAnd struct {
+	Left, Right QueryExpr
+}
+
+type QueryOr struct {
+	Left, Right QueryExpr
+}
+
+type QueryNot struct {
+	Expr QueryExpr
+}
+
+// QueryCond сравнение поля со значениями. Для IN и NOT IN значений несколько, для остальных операторов - одно.
+// Значения уже приведены к типу поля: string, int или time.Time.
+type QueryCond struct {
+	Field  string
+	Op     string
+	Values []any
+}
+
+func (QueryAnd) queryExpr()  {}
+func (QueryOr) queryExpr()   {}
+func (QueryNot) queryExpr()  {}
+func (QueryCond) queryExpr() {}
@@ -0,0 +1,13 @@
+package model
+
+// SortRank сортировка результатов поиска по релевантности, по убыванию
+const SortRank = "rank"
+
+// SearchResult найденная задача. Фрагменты названия и описания - HTML,
+// совпадения выделены тегом <mark>, остальной текст экранирован.
+type SearchResult struct {
+	Task               Task
+	Rank               float32
+	TitleSnippet       string
+	DescriptionSnippet string
+}
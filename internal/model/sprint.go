@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Статусы спринта
+const (
+	SprintPlanned   = "planned"
+	SprintActive    = "active"
+	SprintCompleted = "completed"
+)
+
+type Sprint struct {
+	ID        int
+	Title     string
+	Status    string
+	StartDate time.Time
+	EndDate   time.Time
+	CreatedAt time.Time
+}
+
+type Milestone struct {
+	ID        int
+	Title     string
+	StartDate time.Time
+	EndDate   time.Time
+	CreatedAt time.Time
+}
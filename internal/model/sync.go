@@ -0,0 +1,80 @@
+package model
+
+import "time"
+
+// Ограничения синхронизации: изменений в одном ответе и изменений, загружаемых одним запросом
+const (
+	DefaultSyncLimit = 500
+	MaxSyncLimit     = 1000
+	MaxSyncMutations = 100
+)
+
+// Причины надгробия: задача удалена или перестала быть видна пользователю после снятия исполнителя
+const (
+	TombstoneDeleted = "deleted"
+	TombstoneHidden  = "hidden"
+)
+
+// SyncChanges изменения задач, видимых пользователю, в порядке номеров изменений. Assignments - текущие
+// исполнители каждой задачи из Tasks. Seq - номер, с которого продолжается следующая синхронизация.
+// More - изменения не поместились в ответ, Reset - клиент должен заменить локальные данные ответом.
+type SyncChanges struct {
+	Tasks       []Task
+	Assignments []TaskAssignees
+	Tombstones  []Tombstone
+	Seq         int64
+	More        bool
+	Reset       bool
+}
+
+type TaskAssignees struct {
+	TaskID  int   `json:"task_id"`
+	UserIDs []int `json:"user_ids"`
+}
+
+type Tombstone struct {
+	TaskID    int       `json:"task_id"`
+	Reason    string    `json:"reason"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Типы изменений, которые клиент накопил без связи
+const (
+	MutationCreateTask = "create_task"
+	MutationSetStatus  = "set_status"
+	MutationAssign     = "assign"
+	MutationUnassign   = "unassign"
+	MutationDeleteTask = "delete_task"
+)
+
+// SyncMutation изменение, сделанное клиентом без связи. ID - идентификатор изменения у клиента.
+// Задача указывается TaskID или TaskRef - ID изменения create_task из того же запроса.
+// BaseVersion - версия задачи, которую видел клиент, для set_status и delete_task; 0 - без проверки.
+type SyncMutation struct {
+	ID          string
+	Type        string
+	TaskID      int
+	TaskRef     string
+	Task        Task
+	Status      string
+	UserID      int
+	BaseVersion int
+}
+
+// Результаты применения изменения
+const (
+	SyncApplied  = "applied"
+	SyncConflict = "conflict"
+	SyncFailed   = "error"
+)
+
+// SyncResult результат изменения. При конфликте Task - текущее состояние задачи на сервере,
+// nil - задача удалена. Err заполнен для конфликта и ошибки.
+type SyncResult struct {
+	MutationID string
+	TaskID     int
+	Status     string
+	Version    int
+	Task       *Task
+	Err        error
+}
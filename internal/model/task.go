@@ -2,12 +2,70 @@ package model
 
 import "time"
 
+// Статусы задачи. Незавершённой считается задача в любом статусе, кроме TaskStatusDone.
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusDone       = "done"
+)
+
+// Приоритеты задачи
+const (
+	PriorityLow      = "low"
+	PriorityMedium   = "medium"
+	PriorityHigh     = "high"
+	PriorityCritical = "critical"
+)
+
 type Task struct {
 	ID          int
 	NameTask    string
 	Description string
 	Status      string
+	Priority    string
+	ProjectID   int
 	Deadline    time.Time
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	// Version увеличивается при каждом изменении задачи, по нему работают ETag и If-Match
+	Version int
+	// Labels метки задачи по алфавиту
+	Labels []string
+}
+
+// TaskCounts незавершённые задачи: число по статусам и число просроченных
+type TaskCounts struct {
+	ByStatus map[string]int
+	Overdue  int
+}
+
+// TaskHistory запись в истории изменений задачи
+type TaskHistory struct {
+	ID        int
+	TaskID    int
+	Event     string
+	Details   string
+	CreatedAt time.Time
+}
+
+func ValidPriority(priority string) bool {
+	switch priority {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical:
+		return true
+	}
+	return false
+}
+
+// TaskStatuses допустимые статусы задачи
+var TaskStatuses = []string{TaskStatusTodo, TaskStatusInProgress, TaskStatusDone}
+
+// Priorities допустимые приоритеты задачи по возрастанию
+var Priorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical}
+
+func ValidStatus(status string) bool {
+	switch status {
+	case TaskStatusTodo, TaskStatusInProgress, TaskStatusDone:
+		return true
+	}
+	return false
 }
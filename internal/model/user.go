@@ -1,8 +1,21 @@
 package model
 
+import "time"
+
+// AccessLevelAdmin уровень доступа, с которым пользователь видит все задачи
+const AccessLevelAdmin = 10
+
 type User struct {
-	ID      int
-	Login   string
-	HashPas []byte
-	Level   int
+	ID       int
+	Login    string
+	HashPas  []byte
+	Level    int
+	Timezone string
+	Language string
+}
+
+type Team struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
 }
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// View сохранённый фильтр задач. Query - условие на языке фильтров, разбирается при каждом запуске,
+// поэтому now() и me вычисляются для того, кто открывает представление. Sort = "" - сортировка
+// из ORDER BY в Query или по дедлайну. ProjectID != 0 - представление доступно участникам проекта.
+type View struct {
+	ID        int
+	OwnerID   int
+	ProjectID int
+	Name      string
+	Query     string
+	Sort      string
+	Desc      bool
+	Columns   []string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// ViewColumns колонки, которые можно показать в представлении
+var ViewColumns = []string{
+	"id", "title", "description", "status", "priority", "project",
+	"assignees", "deadline", "created_at", "updated_at",
+}
+
+// DefaultViewColumns колонки представления, для которого они не заданы
+var DefaultViewColumns = []string{"id", "title", "status", "priority", "deadline"}
+
+func ValidViewColumn(column string) bool {
+	for _, c := range ViewColumns {
+		if c == column {
+			return true
+		}
+	}
+	return false
+}
+
+// ViewSubscription подписка пользователя на попадание задач в представление
+type ViewSubscription struct {
+	View   View
+	UserID int
+}
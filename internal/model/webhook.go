@@ -0,0 +1,94 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Статусы доставки вебхука
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// TaskEventTypes типы событий задач, на которые можно подписать вебхук
+var TaskEventTypes = []string{
+	TaskEventCreated, TaskEventUpdated, TaskEventStatusChanged,
+	TaskEventAssigned, TaskEventUnassigned, TaskEventDeleted,
+}
+
+func ValidTaskEventType(eventType string) bool {
+	for _, t := range TaskEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook подписка внешней системы на события задач проекта. Events пустой - все типы событий.
+// Failures - неудачные попытки доставки подряд; подписка, у которой их слишком много, отключается
+// (Active = false) до включения вручную. Secret - ключ подписи доставок, в ответах API не отдаётся.
+type Webhook struct {
+	ID         int
+	ProjectID  int
+	URL        string
+	Events     []string
+	Secret     string `json:"-"`
+	Active     bool
+	Failures   int
+	DisabledAt *time.Time
+	CreatedBy  int
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Accepts подписан ли вебхук на события типа eventType
+func (w Webhook) Accepts(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, t := range w.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery запись журнала доставок: событие EventID и результат последней попытки.
+// ReplayOf - доставка, повтор которой запрошен вручную.
+type WebhookDelivery struct {
+	ID             int
+	WebhookID      int
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	ReplayOf       int
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+// WebhookJob доставка, взятая в работу, с адресом и ключом подписи вебхука
+type WebhookJob struct {
+	Delivery WebhookDelivery
+	URL      string
+	Secret   string
+}
+
+// WebhookAttempt результат попытки доставки. Status - новый статус доставки,
+// для DeliveryPending следующая попытка - через RetryIn.
+type WebhookAttempt struct {
+	DeliveryID     int
+	WebhookID      int
+	Status         string
+	RetryIn        time.Duration
+	ResponseStatus int
+	Error          string
+}
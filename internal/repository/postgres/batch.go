@@ -0,0 +1,135 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+func (r *Repo) BatchTasks(ctx context.Context, batch model.TaskBatch) ([]model.TaskBatchResult, error) {
+	const op = "storage.postgres.BatchTasks"
+	log := r.log.With(slog.String("op", op), slog.Int("tasks", len(batch.TaskIDs)))
+	log.Info("applying batch to tasks")
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	results := make([]model.TaskBatchResult, 0, len(batch.TaskIDs))
+	for _, taskID := range batch.TaskIDs {
+		// вложенная транзакция pgx - точка сохранения
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			log.Error("failed to create savepoint", sl.Err(err))
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		res, err := batchTask(ctx, sp, batch, taskID)
+		if err != nil {
+			var domainErr *model.Error
+			if !errors.As(err, &domainErr) {
+				log.Error("failed to apply batch to task", slog.Int("taskID", taskID), sl.Err(err))
+				return nil, fmt.Errorf("failed to apply batch to task %d: %w", taskID, err)
+			}
+			if err := sp.Rollback(ctx); err != nil {
+				log.Error("failed to roll back to savepoint", sl.Err(err))
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+			results = append(results, model.TaskBatchResult{TaskID: taskID, Err: err})
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			log.Error("failed to release savepoint", sl.Err(err))
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+		results = append(results, res)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("batch applied successfully")
+	return results, nil
+}
+
+// batchTask применяет изменения к одной задаче. Доменная ошибка относится только к этой задаче,
+// остальные ошибки прерывают всю операцию.
+func batchTask(ctx context.Context, tx pgx.Tx, batch model.TaskBatch, taskID int) (model.TaskBatchResult, error) {
+	res := model.TaskBatchResult{TaskID: taskID}
+
+	// блокировка задачи защищает от параллельного изменения тех же задач
+	err := tx.QueryRow(ctx, "SELECT COALESCE(project_id, 0) FROM tasks WHERE task_id = $1 FOR UPDATE", taskID).Scan(&res.ProjectID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return res, model.NotFound("task with ID %d not found", taskID)
+	}
+	if err != nil {
+		return res, fmt.Errorf("failed to lock task: %w", pgError(err))
+	}
+
+	if res.Assignees, err = queryInts(ctx, tx, "SELECT user_id FROM task_assignments WHERE task_id = $1 ORDER BY user_id", taskID); err != nil {
+		return res, fmt.Errorf("failed to get task assignees: %w", err)
+	}
+
+	if batch.Delete {
+		if _, err := tx.Exec(ctx, "DELETE FROM tasks WHERE task_id = $1", taskID); err != nil {
+			return res, fmt.Errorf("failed to delete task: %w", pgError(err))
+		}
+		return res, nil
+	}
+
+	if len(batch.AddAssignees) > 0 {
+		// уже назначенные пользователи пропускаются, повтор операции не приводит к ошибке
+		query := `INSERT INTO task_assignments (user_id, task_id)
+                  SELECT u, $2 FROM unnest($1::int[]) AS u
+                  ON CONFLICT DO NOTHING
+                  RETURNING user_id`
+		if res.Added, err = queryInts(ctx, tx, query, batch.AddAssignees, taskID); err != nil {
+			return res, fmt.Errorf("failed to add assignees: %w", pgError(err))
+		}
+	}
+	if len(batch.RemoveAssignees) > 0 {
+		query := "DELETE FROM task_assignments WHERE task_id = $1 AND user_id = ANY($2) RETURNING user_id"
+		if res.Removed, err = queryInts(ctx, tx, query, taskID, batch.RemoveAssignees); err != nil {
+			return res, fmt.Errorf("failed to remove assignees: %w", pgError(err))
+		}
+	}
+
+	if batch.Labels != nil {
+		if _, err := tx.Exec(ctx, "DELETE FROM task_labels WHERE task_id = $1", taskID); err != nil {
+			return res, fmt.Errorf("failed to delete labels: %w", pgError(err))
+		}
+		query := "INSERT INTO task_labels (task_id, label) SELECT $1, l FROM unnest($2::text[]) AS l ON CONFLICT DO NOTHING"
+		if _, err := tx.Exec(ctx, query, taskID, batch.Labels); err != nil {
+			return res, fmt.Errorf("failed to set labels: %w", pgError(err))
+		}
+	}
+
+	// исполнители не входят в задачу, их изменение не меняет её версию
+	if batch.Status != "" || batch.Labels != nil {
+		query := `UPDATE tasks t SET status = COALESCE(NULLIF($2, ''), status), updated_at = CURRENT_TIMESTAMP,
+                  version = version + 1
+                  WHERE t.task_id = $1
+                  RETURNING ` + taskColumns
+		if res.Task, err = scanTask(tx.QueryRow(ctx, query, taskID, batch.Status)); err != nil {
+			return res, fmt.Errorf("failed to update task: %w", pgError(err))
+		}
+	}
+	return res, nil
+}
+
+func queryInts(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]int, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[int])
+}
@@ -0,0 +1,165 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// создание рабочего календаря
+func (r *Repo) CreateCalendar(ctx context.Context, calendar model.Calendar) (int, error) {
+	const op = "storage.postgres.CreateCalendar"
+	log := r.log.With(slog.String("op", op))
+	log.Info("creating a new calendar")
+
+	query := `INSERT INTO calendars (name, timezone, work_start, work_end, weekends)
+              VALUES ($1, $2, $3::time, $4::time, $5) RETURNING calendar_id`
+	err := r.postgres.Pool.QueryRow(ctx, query,
+		calendar.Name,
+		calendar.Timezone,
+		calendar.WorkStart,
+		calendar.WorkEnd,
+		calendar.Weekends,
+	).Scan(&calendar.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create calendar: %w", pgError(err))
+	}
+	log.Info("calendar created successfully", slog.Int("calendarID", calendar.ID))
+	return calendar.ID, nil
+}
+
+// добавление праздников в календарь, уже существующие дни перезаписываются
+func (r *Repo) AddCalendarHolidays(ctx context.Context, calendarID int, holidays []model.Holiday) error {
+	const op = "storage.postgres.AddCalendarHolidays"
+	log := r.log.With(slog.String("op", op), slog.Int("calendarID", calendarID))
+	log.Info("adding holidays to calendar", slog.Int("count", len(holidays)))
+
+	query := `INSERT INTO calendar_holidays (calendar_id, day, name) VALUES ($1, $2, $3)
+              ON CONFLICT (calendar_id, day) DO UPDATE SET name = EXCLUDED.name`
+
+	batch := &pgx.Batch{}
+	for _, h := range holidays {
+		batch.Queue(query, calendarID, h.Date, h.Name)
+	}
+	if err := r.postgres.Pool.SendBatch(ctx, batch).Close(); err != nil {
+		log.Error("failed to add holidays", sl.Err(err))
+		return fmt.Errorf("failed to add holidays: %w", pgError(err))
+	}
+	log.Info("holidays added successfully")
+	return nil
+}
+
+// Получение календаря по ID вместе с праздниками
+func (r *Repo) CalendarByID(ctx context.Context, calendarID int) (model.Calendar, error) {
+	const op = "storage.postgres.CalendarByID"
+	log := r.log.With(slog.String("op", op), slog.Int("calendarID", calendarID))
+
+	query := `SELECT calendar_id, name, timezone, to_char(work_start, 'HH24:MI'), to_char(work_end, 'HH24:MI'), weekends
+              FROM calendars WHERE calendar_id = $1`
+	return r.scanCalendar(ctx, log, query, calendarID)
+}
+
+// Получение календаря, действующего для задачи: календарь проекта, а если его нет - календарь пользователя.
+// Возвращает false, если календарь не назначен.
+func (r *Repo) CalendarFor(ctx context.Context, projectID int, userID int) (model.Calendar, bool, error) {
+	const op = "storage.postgres.CalendarFor"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID), slog.Int("userID", userID))
+
+	query := `SELECT c.calendar_id, c.name, c.timezone, to_char(c.work_start, 'HH24:MI'), to_char(c.work_end, 'HH24:MI'), c.weekends
+              FROM calendars c
+              WHERE c.calendar_id = COALESCE(
+                  (SELECT calendar_id FROM projects WHERE project_id = $1),
+                  (SELECT calendar_id FROM users WHERE user_id = $2)
+              )`
+	calendar, err := r.scanCalendar(ctx, log, query, projectID, userID)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.Calendar{}, false, nil
+	}
+	if err != nil {
+		return model.Calendar{}, false, err
+	}
+	return calendar, true, nil
+}
+
+// назначение календаря проекту
+func (r *Repo) SetProjectCalendar(ctx context.Context, projectID int, calendarID int) error {
+	const op = "storage.postgres.SetProjectCalendar"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID), slog.Int("calendarID", calendarID))
+	log.Info("assigning calendar to project")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "UPDATE projects SET calendar_id = NULLIF($1, 0) WHERE project_id = $2", calendarID, projectID)
+	if err != nil {
+		log.Error("failed to assign calendar", sl.Err(err))
+		return fmt.Errorf("failed to assign calendar to project: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("project with ID %d not found", projectID)
+	}
+	return nil
+}
+
+// назначение календаря пользователю
+func (r *Repo) SetUserCalendar(ctx context.Context, userID int, calendarID int) error {
+	const op = "storage.postgres.SetUserCalendar"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID), slog.Int("calendarID", calendarID))
+	log.Info("assigning calendar to user")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "UPDATE users SET calendar_id = NULLIF($1, 0) WHERE user_id = $2", calendarID, userID)
+	if err != nil {
+		log.Error("failed to assign calendar", sl.Err(err))
+		return fmt.Errorf("failed to assign calendar to user: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("user with ID %d not found", userID)
+	}
+	return nil
+}
+
+// scanCalendar читает календарь одним запросом и праздники - вторым.
+// Если календаря нет, возвращается model.ErrNotFound.
+func (r *Repo) scanCalendar(ctx context.Context, log *slog.Logger, query string, args ...any) (model.Calendar, error) {
+	var calendar model.Calendar
+	err := r.postgres.Pool.QueryRow(ctx, query, args...).Scan(
+		&calendar.ID,
+		&calendar.Name,
+		&calendar.Timezone,
+		&calendar.WorkStart,
+		&calendar.WorkEnd,
+		&calendar.Weekends,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Calendar{}, model.NotFound("calendar not found")
+		}
+		log.Error("failed to execute query", sl.Err(err))
+		return model.Calendar{}, fmt.Errorf("failed to retrieve calendar: %w", err)
+	}
+
+	rows, err := r.postgres.Pool.Query(ctx,
+		"SELECT day, COALESCE(name, '') FROM calendar_holidays WHERE calendar_id = $1 ORDER BY day", calendar.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return model.Calendar{}, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var h model.Holiday
+		if err := rows.Scan(&h.Date, &h.Name); err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return model.Calendar{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		calendar.Holidays = append(calendar.Holidays, h)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return model.Calendar{}, fmt.Errorf("row iteration error: %w", err)
+	}
+	return calendar, nil
+}
@@ -0,0 +1,234 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// sortColumn выражение сортировки и тип, к которому приводится значение из курсора.
+// Выражения не возвращают NULL, иначе сравнение строк в условии курсора теряет записи.
+type sortColumn struct {
+	expr string
+	cast string
+	// value значение выражения для задачи в том виде, в каком его примет PostgreSQL
+	value func(model.Task) string
+}
+
+// формат TIMESTAMP без часового пояса, в колонках хранится UTC
+const timestampLayout = "2006-01-02T15:04:05.999999"
+
+func timestampValue(t time.Time, zero string) string {
+	if t.IsZero() {
+		return zero
+	}
+	return t.UTC().Format(timestampLayout)
+}
+
+// priorityRank порядковый номер приоритета, 0 для неизвестного
+func priorityRank(priority string) int {
+	for i, p := range model.Priorities {
+		if p == priority {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+var taskSortColumns = map[string]sortColumn{
+	model.SortDeadline: {
+		expr:  "COALESCE(t.deadline, 'infinity'::timestamp)",
+		cast:  "timestamp",
+		value: func(t model.Task) string { return timestampValue(t.Deadline, "infinity") },
+	},
+	model.SortCreatedAt: {
+		expr:  "COALESCE(t.created_at, '-infinity'::timestamp)",
+		cast:  "timestamp",
+		value: func(t model.Task) string { return timestampValue(t.CreatedAt, "-infinity") },
+	},
+	model.SortUpdatedAt: {
+		expr:  "COALESCE(t.updated_at, '-infinity'::timestamp)",
+		cast:  "timestamp",
+		value: func(t model.Task) string { return timestampValue(t.UpdatedAt, "-infinity") },
+	},
+	model.SortPriority: {
+		expr:  "COALESCE(array_position(ARRAY['" + strings.Join(model.Priorities, "','") + "'], t.priority::text), 0)",
+		cast:  "int",
+		value: func(t model.Task) string { return strconv.Itoa(priorityRank(t.Priority)) },
+	},
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// taskConditions условия WHERE для фильтра задач
+func taskConditions(filter model.TaskFilter) ([]string, []any, error) {
+	var where []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID != 0 {
+		add("EXISTS (SELECT 1 FROM task_assignments ta WHERE ta.task_id = t.task_id AND ta.user_id = $%d)", filter.UserID)
+	}
+	if filter.ProjectID != 0 {
+		add("t.project_id = $%d", filter.ProjectID)
+	}
+	if filter.TeamID != 0 {
+		add(`EXISTS (SELECT 1 FROM task_assignments ta
+                     JOIN team_members tm ON tm.user_id = ta.user_id
+                     WHERE ta.task_id = t.task_id AND tm.team_id = $%d)`, filter.TeamID)
+	}
+	if filter.SprintID != 0 {
+		add("EXISTS (SELECT 1 FROM sprint_tasks st WHERE st.task_id = t.task_id AND st.sprint_id = $%d)", filter.SprintID)
+	}
+	// в колонке TIMESTAMP дедлайны хранятся в UTC
+	if !filter.DeadlineFrom.IsZero() {
+		add("t.deadline >= $%d", filter.DeadlineFrom.UTC())
+	}
+	if !filter.DeadlineTo.IsZero() {
+		add("t.deadline < $%d", filter.DeadlineTo.UTC())
+	}
+	if filter.UnfinishedOnly {
+		add("t.status IS DISTINCT FROM $%d", model.TaskStatusDone)
+	}
+	if len(filter.Statuses) > 0 {
+		add("t.status = ANY($%d)", filter.Statuses)
+	}
+	if filter.Text != "" {
+		add("(t.title ILIKE $%[1]d OR t.description ILIKE $%[1]d)", "%"+escapeLike(filter.Text)+"%")
+	}
+	if filter.VisibleTo != 0 {
+		add(visibleTo, filter.VisibleTo)
+	}
+	if filter.Query != nil && filter.Query.Where != nil {
+		cond, err := queryCompiler{args: &args}.compile(filter.Query.Where)
+		if err != nil {
+			return nil, nil, err
+		}
+		where = append(where, cond)
+	}
+	return where, args, nil
+}
+
+// visibleTo условие видимости задачи пользователю $N: он администратор, исполнитель задачи,
+// состоит в команде с исполнителем или руководит проектом задачи
+var visibleTo = `(EXISTS (SELECT 1 FROM users u WHERE u.user_id = $%[1]d AND u.access_level >= ` + strconv.Itoa(model.AccessLevelAdmin) + `)
+     OR EXISTS (SELECT 1 FROM task_assignments ta
+                WHERE ta.task_id = t.task_id
+                  AND (ta.user_id = $%[1]d OR ta.user_id IN (
+                       SELECT tm2.user_id FROM team_members tm1
+                       JOIN team_members tm2 ON tm2.team_id = tm1.team_id
+                       WHERE tm1.user_id = $%[1]d)))
+     OR EXISTS (SELECT 1 FROM projects p WHERE p.project_id = t.project_id AND p.manager_id = $%[1]d))`
+
+// выборка страницы задач по фильтру: исполнитель, проект, команда, спринт, статусы, текст и диапазон дедлайнов
+func (r *Repo) ListTasks(ctx context.Context, filter model.TaskFilter, page model.PageRequest) (model.Page[model.Task], error) {
+	const op = "storage.postgres.ListTasks"
+	log := r.log.With(slog.String("op", op))
+	log.Info("retrieving tasks by filter")
+
+	sort := page.Sort
+	if sort == "" {
+		sort = model.SortDeadline
+	}
+	column, ok := taskSortColumns[sort]
+	if !ok {
+		return model.Page[model.Task]{}, model.Invalid("unknown sort field %q", sort)
+	}
+
+	where, args, err := taskConditions(filter)
+	if err != nil {
+		return model.Page[model.Task]{}, err
+	}
+	var result model.Page[model.Task]
+
+	if page.WithTotal {
+		countQuery := "SELECT count(*) FROM tasks t"
+		if len(where) > 0 {
+			countQuery += " WHERE " + strings.Join(where, " AND ")
+		}
+		var total int
+		if err := r.postgres.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			log.Error("failed to execute query", sl.Err(err))
+			return result, fmt.Errorf("failed to count tasks: %w", err)
+		}
+		result.Total = &total
+	}
+
+	direction, compare := "ASC", ">"
+	if page.Desc {
+		direction, compare = "DESC", "<"
+	}
+	if page.After != nil {
+		args = append(args, page.After.Value, page.After.ID)
+		where = append(where, fmt.Sprintf("(%s, t.task_id) %s ($%d::%s, $%d)",
+			column.expr, compare, len(args)-1, column.cast, len(args)))
+	}
+
+	query := "SELECT " + taskColumns + " FROM tasks t"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, t.task_id %s", column.expr, direction, direction)
+	if page.Limit > 0 {
+		// лишняя запись показывает, что есть следующая страница
+		args = append(args, page.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.postgres.Pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return result, fmt.Errorf("failed to execute query: %w", pgError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return result, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result.Items = append(result.Items, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return result, fmt.Errorf("row iteration error: %w", pgError(err))
+	}
+
+	if page.Limit > 0 && len(result.Items) > page.Limit {
+		result.Items = result.Items[:page.Limit]
+		last := result.Items[len(result.Items)-1]
+		result.Next = &model.Cursor{Sort: sort, Desc: page.Desc, Value: column.value(last), ID: last.ID}
+	}
+	log.Info("successfully retrieved tasks", slog.Int("taskCount", len(result.Items)))
+	return result, nil
+}
+
+// EventScope область видимости пользователя для потока событий, те же правила, что в visibleTo
+func (r *Repo) EventScope(ctx context.Context, userID int) (model.EventScope, error) {
+	const op = "storage.postgres.EventScope"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	scope := model.EventScope{UserID: userID}
+	query := `SELECT EXISTS (SELECT 1 FROM users u WHERE u.user_id = $1 AND u.access_level >= ` + strconv.Itoa(model.AccessLevelAdmin) + `),
+                     ARRAY(SELECT DISTINCT tm2.user_id FROM team_members tm1
+                           JOIN team_members tm2 ON tm2.team_id = tm1.team_id
+                           WHERE tm1.user_id = $1),
+                     ARRAY(SELECT p.project_id FROM projects p WHERE p.manager_id = $1)`
+	if err := r.postgres.Pool.QueryRow(ctx, query, userID).Scan(&scope.Admin, &scope.Users, &scope.Projects); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return model.EventScope{}, fmt.Errorf("failed to get event scope: %w", pgError(err))
+	}
+	return scope, nil
+}
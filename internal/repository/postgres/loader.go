@@ -0,0 +1,152 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// Пакетная загрузка по спискам ID одним запросом для DataLoader GraphQL.
+// Отсутствующие ID пропускаются, порядок результатов не гарантируется.
+
+func (r *Repo) TasksByIDs(ctx context.Context, taskIDs []int) ([]model.Task, error) {
+	const op = "storage.postgres.TasksByIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	query := "SELECT " + taskColumns + " FROM tasks t WHERE t.task_id = ANY($1)"
+	rows, err := r.postgres.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to retrieve tasks: %w", pgError(err))
+	}
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Task, error) {
+		return scanTask(row)
+	})
+	if err != nil {
+		log.Error("failed to scan rows", sl.Err(err))
+		return nil, fmt.Errorf("failed to scan tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+func (r *Repo) UsersByIDs(ctx context.Context, userIDs []int) ([]model.User, error) {
+	const op = "storage.postgres.UsersByIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(userIDs)))
+
+	query := "SELECT user_id, username, access_level, timezone, language FROM users WHERE user_id = ANY($1)"
+	rows, err := r.postgres.Pool.Query(ctx, query, userIDs)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to retrieve users: %w", pgError(err))
+	}
+	users, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.User, error) {
+		var user model.User
+		err := row.Scan(&user.ID, &user.Login, &user.Level, &user.Timezone, &user.Language)
+		return user, err
+	})
+	if err != nil {
+		log.Error("failed to scan rows", sl.Err(err))
+		return nil, fmt.Errorf("failed to scan users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *Repo) ProjectsByIDs(ctx context.Context, projectIDs []int) ([]model.Project, error) {
+	const op = "storage.postgres.ProjectsByIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(projectIDs)))
+
+	query := "SELECT project_id, name, COALESCE(manager_id, 0), created_at FROM projects WHERE project_id = ANY($1)"
+	rows, err := r.postgres.Pool.Query(ctx, query, projectIDs)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to retrieve projects: %w", pgError(err))
+	}
+	projects, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (model.Project, error) {
+		var project model.Project
+		err := row.Scan(&project.ID, &project.Name, &project.ManagerID, &project.CreatedAt)
+		return project, err
+	})
+	if err != nil {
+		log.Error("failed to scan rows", sl.Err(err))
+		return nil, fmt.Errorf("failed to scan projects: %w", err)
+	}
+	return projects, nil
+}
+
+// AssigneesByTaskIDs исполнители каждой задачи по ID, упорядоченные по ID пользователя
+func (r *Repo) AssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]model.User, error) {
+	const op = "storage.postgres.AssigneesByTaskIDs"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(taskIDs)))
+
+	query := `SELECT ta.task_id, u.user_id, u.username, u.access_level, u.timezone, u.language
+              FROM task_assignments ta
+              JOIN users u ON u.user_id = ta.user_id
+              WHERE ta.task_id = ANY($1)
+              ORDER BY ta.task_id, u.user_id`
+	rows, err := r.postgres.Pool.Query(ctx, query, taskIDs)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to retrieve assignees: %w", pgError(err))
+	}
+	defer rows.Close()
+
+	assignees := make(map[int][]model.User, len(taskIDs))
+	for rows.Next() {
+		var taskID int
+		var user model.User
+		if err := rows.Scan(&taskID, &user.ID, &user.Login, &user.Level, &user.Timezone, &user.Language); err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		assignees[taskID] = append(assignees[taskID], user)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return assignees, nil
+}
+
+// TasksByAssignees не больше limit задач каждого пользователя по ближайшему дедлайну
+func (r *Repo) TasksByAssignees(ctx context.Context, userIDs []int, limit int) (map[int][]model.Task, error) {
+	const op = "storage.postgres.TasksByAssignees"
+	log := r.log.With(slog.String("op", op), slog.Int("count", len(userIDs)))
+
+	query := `SELECT ` + taskColumns + `, u.user_id
+              FROM unnest($1::int[]) AS u(user_id)
+              CROSS JOIN LATERAL (
+                  SELECT t.* FROM tasks t
+                  JOIN task_assignments ta ON ta.task_id = t.task_id
+                  WHERE ta.user_id = u.user_id
+                  ORDER BY t.deadline, t.task_id
+                  LIMIT $2
+              ) t
+              ORDER BY u.user_id, t.deadline, t.task_id`
+	rows, err := r.postgres.Pool.Query(ctx, query, userIDs, limit)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to retrieve tasks: %w", pgError(err))
+	}
+	defer rows.Close()
+
+	tasks := make(map[int][]model.Task, len(userIDs))
+	for rows.Next() {
+		var userID int
+		task, err := scanTask(rows, &userID)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tasks[userID] = append(tasks[userID], task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return tasks, nil
+}
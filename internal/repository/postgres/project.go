@@ -0,0 +1,307 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// создание проекта
+func (r *Repo) CreateProject(ctx context.Context, project model.Project) (int, error) {
+	const op = "storage.postgres.CreateProject"
+	log := r.log.With(slog.String("op", op))
+	log.Info("creating a new project")
+
+	query := "INSERT INTO projects (name, manager_id) VALUES ($1, NULLIF($2, 0)) RETURNING project_id"
+	err := r.postgres.Pool.QueryRow(ctx, query, project.Name, project.ManagerID).Scan(&project.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create project: %w", pgError(err))
+	}
+	log.Info("project created successfully", slog.Int("projectID", project.ID))
+	return project.ID, nil
+}
+
+// создание правила эскалации для проекта
+func (r *Repo) CreateEscalationRule(ctx context.Context, rule model.EscalationRule) (int, error) {
+	const op = "storage.postgres.CreateEscalationRule"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", rule.ProjectID))
+	log.Info("creating a new escalation rule")
+
+	query := `INSERT INTO escalation_rules (project_id, priority, overdue_seconds, assign_manager, notify_manager, enabled)
+              VALUES ($1, $2, $3, $4, $5, $6) RETURNING rule_id`
+	err := r.postgres.Pool.QueryRow(ctx, query,
+		rule.ProjectID,
+		rule.Priority,
+		int64(rule.OverdueBy.Seconds()),
+		rule.AssignManager,
+		rule.NotifyManager,
+		rule.Enabled,
+	).Scan(&rule.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create escalation rule: %w", pgError(err))
+	}
+	log.Info("escalation rule created successfully", slog.Int("ruleID", rule.ID))
+	return rule.ID, nil
+}
+
+// получение правил эскалации. projectID = 0 - включённые правила всех проектов
+func (r *Repo) EscalationRules(ctx context.Context, projectID int) ([]model.EscalationRule, error) {
+	const op = "storage.postgres.EscalationRules"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID))
+	log.Debug("retrieving escalation rules")
+
+	query := `SELECT er.rule_id, er.project_id, COALESCE(p.manager_id, 0), er.priority, er.overdue_seconds,
+                     er.assign_manager, er.notify_manager, er.enabled
+              FROM escalation_rules er
+              JOIN projects p ON p.project_id = er.project_id
+              WHERE ($1 = 0 AND er.enabled) OR er.project_id = $1
+              ORDER BY er.rule_id`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, projectID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var rules []model.EscalationRule
+	for rows.Next() {
+		var rule model.EscalationRule
+		var overdueSeconds int64
+		err := rows.Scan(&rule.ID, &rule.ProjectID, &rule.ManagerID, &rule.Priority, &overdueSeconds,
+			&rule.AssignManager, &rule.NotifyManager, &rule.Enabled)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		rule.OverdueBy = time.Duration(overdueSeconds) * time.Second
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return rules, nil
+}
+
+// удаление правила эскалации
+func (r *Repo) DeleteEscalationRule(ctx context.Context, ruleID int) error {
+	const op = "storage.postgres.DeleteEscalationRule"
+	log := r.log.With(slog.String("op", op), slog.Int("ruleID", ruleID))
+	log.Info("deleting escalation rule")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "DELETE FROM escalation_rules WHERE rule_id = $1", ruleID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to delete escalation rule: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("escalation rule with ID %d not found", ruleID)
+	}
+	log.Info("escalation rule deleted successfully")
+	return nil
+}
+
+// Получение незавершённых задач, подпадающих под правило и ещё не эскалированных им.
+// deadlineBefore - момент, раньше которого должен был наступить дедлайн.
+func (r *Repo) TasksToEscalate(ctx context.Context, rule model.EscalationRule, deadlineBefore time.Time) ([]model.Task, error) {
+	const op = "storage.postgres.TasksToEscalate"
+	log := r.log.With(slog.String("op", op), slog.Int("ruleID", rule.ID))
+	log.Debug("retrieving tasks to escalate")
+
+	query := `SELECT ` + taskColumns + `
+              FROM tasks t
+              WHERE t.project_id = $1
+              AND t.priority = $2
+              AND t.status IS DISTINCT FROM $3
+              AND t.deadline IS NOT NULL
+              AND t.deadline <= $4
+              AND NOT EXISTS (
+                  SELECT 1 FROM task_escalations e
+                  WHERE e.rule_id = $5 AND e.task_id = t.task_id AND e.deadline = t.deadline
+              )`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, rule.ProjectID, rule.Priority, model.TaskStatusDone, deadlineBefore, rule.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var tasks []model.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return tasks, nil
+}
+
+// Эскалация задачи по правилу в одной транзакции: фиксирует эскалацию, при необходимости
+// назначает менеджера проекта исполнителем и пишет запись в историю задачи.
+// Уведомление менеджера сохраняется вместе с эскалацией и отправляется планировщиком отдельно.
+// Возвращает false, если задача уже была эскалирована этим правилом.
+func (r *Repo) EscalateTask(ctx context.Context, rule model.EscalationRule, task model.Task, details string) (bool, error) {
+	const op = "storage.postgres.EscalateTask"
+	log := r.log.With(slog.String("op", op), slog.Int("ruleID", rule.ID), slog.Int("taskID", task.ID))
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	notifyUserID := 0
+	if rule.NotifyManager {
+		notifyUserID = rule.ManagerID
+	}
+	claim := `INSERT INTO task_escalations (rule_id, task_id, deadline, notify_user_id) VALUES ($1, $2, $3, NULLIF($4, 0))
+              ON CONFLICT DO NOTHING`
+	tag, err := tx.Exec(ctx, claim, rule.ID, task.ID, task.Deadline, notifyUserID)
+	if err != nil {
+		log.Error("failed to record escalation", sl.Err(err))
+		return false, fmt.Errorf("failed to record escalation: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if rule.AssignManager && rule.ManagerID != 0 {
+		assign := `INSERT INTO task_assignments (user_id, task_id) VALUES ($1, $2)
+                   ON CONFLICT (user_id, task_id) DO NOTHING`
+		if _, err := tx.Exec(ctx, assign, rule.ManagerID, task.ID); err != nil {
+			log.Error("failed to assign manager", sl.Err(err))
+			return false, fmt.Errorf("failed to assign manager: %w", err)
+		}
+	}
+
+	history := "INSERT INTO task_history (task_id, event, details) VALUES ($1, $2, $3)"
+	if _, err := tx.Exec(ctx, history, task.ID, model.EventTaskEscalated, details); err != nil {
+		log.Error("failed to write task history", sl.Err(err))
+		return false, fmt.Errorf("failed to write task history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("task escalated successfully")
+	return true, nil
+}
+
+// Резервирование неотправленных уведомлений об эскалации: уведомления снимаются с очереди,
+// поэтому другой экземпляр планировщика их уже не получит. Неотправленные возвращаются ReleaseEscalationNotice.
+func (r *Repo) ClaimEscalationNotices(ctx context.Context) ([]model.EscalationNotice, error) {
+	const op = "storage.postgres.ClaimEscalationNotices"
+	log := r.log.With(slog.String("op", op))
+
+	query := `WITH pending AS (
+                  SELECT rule_id, task_id, deadline, notify_user_id FROM task_escalations
+                  WHERE notify_user_id IS NOT NULL
+                  FOR UPDATE SKIP LOCKED)
+              UPDATE task_escalations e SET notify_user_id = NULL
+              FROM pending p
+              WHERE e.rule_id = p.rule_id AND e.task_id = p.task_id AND e.deadline = p.deadline
+              RETURNING p.rule_id, p.task_id, p.notify_user_id, p.deadline`
+
+	rows, err := r.postgres.Pool.Query(ctx, query)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to claim escalation notices: %w", err)
+	}
+	defer rows.Close()
+	var notices []model.EscalationNotice
+	for rows.Next() {
+		var n model.EscalationNotice
+		if err := rows.Scan(&n.RuleID, &n.TaskID, &n.UserID, &n.Deadline); err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		notices = append(notices, n)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return notices, nil
+}
+
+// Возврат уведомления об эскалации в очередь, если его не удалось отправить
+func (r *Repo) ReleaseEscalationNotice(ctx context.Context, notice model.EscalationNotice) error {
+	const op = "storage.postgres.ReleaseEscalationNotice"
+	log := r.log.With(slog.String("op", op), slog.Int("ruleID", notice.RuleID), slog.Int("taskID", notice.TaskID))
+
+	query := `UPDATE task_escalations SET notify_user_id = $4
+              WHERE rule_id = $1 AND task_id = $2 AND deadline = $3`
+	_, err := r.postgres.Pool.Exec(ctx, query, notice.RuleID, notice.TaskID, notice.Deadline, notice.UserID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to release escalation notice: %w", err)
+	}
+	return nil
+}
+
+// получение истории задачи
+func (r *Repo) TaskHistory(ctx context.Context, taskID int) ([]model.TaskHistory, error) {
+	const op = "storage.postgres.TaskHistory"
+	log := r.log.With(slog.String("op", op), slog.Int("taskID", taskID))
+	log.Info("retrieving task history")
+
+	query := `SELECT id, task_id, event, COALESCE(details, ''), created_at
+              FROM task_history WHERE task_id = $1 ORDER BY created_at, id`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, taskID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var history []model.TaskHistory
+	for rows.Next() {
+		var h model.TaskHistory
+		if err := rows.Scan(&h.ID, &h.TaskID, &h.Event, &h.Details, &h.CreatedAt); err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		history = append(history, h)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return history, nil
+}
+
+// Получение проекта по ID
+func (r *Repo) ProjectByID(ctx context.Context, projectID int) (model.Project, error) {
+	const op = "storage.postgres.ProjectByID"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID))
+
+	query := "SELECT project_id, name, COALESCE(manager_id, 0), created_at FROM projects WHERE project_id = $1"
+
+	var project model.Project
+	err := r.postgres.Pool.QueryRow(ctx, query, projectID).Scan(&project.ID, &project.Name, &project.ManagerID, &project.CreatedAt)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Project{}, model.NotFound("project with ID %d not found", projectID)
+		}
+		return model.Project{}, fmt.Errorf("failed to retrieve project by ID: %w", err)
+	}
+	return project, nil
+}
@@ -0,0 +1,224 @@
+package repoStorage
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Tasks/internal/model"
+)
+
+// queryCompiler переводит разобранный запрос на языке фильтров в условие WHERE.
+// Значения передаются только параметрами, в текст запроса попадают лишь имена колонок и операторы
+// из фиксированного набора.
+type queryCompiler struct {
+	args *[]any
+}
+
+// param добавляет аргумент и возвращает его плейсхолдер
+func (c queryCompiler) param(v any) string {
+	*c.args = append(*c.args, v)
+	return fmt.Sprintf("$%d", len(*c.args))
+}
+
+// операторы сравнения, которые можно подставить в SQL как есть
+var sqlCompareOps = map[string]string{
+	model.OpEq: "=",
+	model.OpLt: "<",
+	model.OpLe: "<=",
+	model.OpGt: ">",
+	model.OpGe: ">=",
+}
+
+func (c queryCompiler) compile(expr model.QueryExpr) (string, error) {
+	switch e := expr.(type) {
+	case model.QueryAnd:
+		return c.binary(e.Left, e.Right, "AND")
+	case model.QueryOr:
+		return c.binary(e.Left, e.Right, "OR")
+	case model.QueryNot:
+		inner, err := c.compile(e.Expr)
+		if err != nil {
+			return "", err
+		}
+		// NULL в условии превратил бы NOT в NULL, поэтому отрицание ложного или неизвестного - истина
+		return "NOT COALESCE(" + inner + ", false)", nil
+	case model.QueryCond:
+		return c.cond(e)
+	}
+	return "", model.Invalid("unsupported query expression %T", expr)
+}
+
+func (c queryCompiler) binary(left, right model.QueryExpr, op string) (string, error) {
+	l, err := c.compile(left)
+	if err != nil {
+		return "", err
+	}
+	r, err := c.compile(right)
+	if err != nil {
+		return "", err
+	}
+	return "(" + l + " " + op + " " + r + ")", nil
+}
+
+func (c queryCompiler) cond(e model.QueryCond) (string, error) {
+	if len(e.Values) == 0 {
+		return "", model.Invalid("field %s requires a value", e.Field)
+	}
+	switch e.Field {
+	case model.QueryStatus:
+		return c.column("t.status::text", e, toStrings)
+	case model.QueryPriority:
+		if _, ok := sqlCompareOps[e.Op]; ok && e.Op != model.OpEq {
+			// порядок приоритетов задаётся списком model.Priorities, а не алфавитом
+			return c.column(taskSortColumns[model.SortPriority].expr, e, func(values []any) (any, error) {
+				s, err := toStrings(values)
+				if err != nil {
+					return nil, err
+				}
+				return priorityRank(s.([]string)[0]), nil
+			})
+		}
+		return c.column("t.priority::text", e, toStrings)
+	case model.QueryDeadline:
+		return c.column("t.deadline", e, toTimes)
+	case model.QueryCreatedAt:
+		return c.column("t.created_at", e, toTimes)
+	case model.QueryUpdatedAt:
+		return c.column("t.updated_at", e, toTimes)
+	case model.QueryProject:
+		return c.column("t.project_id", e, toInts)
+	case model.QueryAssignee:
+		return c.exists("SELECT 1 FROM task_assignments ta WHERE ta.task_id = t.task_id AND ta.user_id = ANY(%s)", e)
+	case model.QuerySprint:
+		return c.exists("SELECT 1 FROM sprint_tasks st WHERE st.task_id = t.task_id AND st.sprint_id = ANY(%s)", e)
+	case model.QueryTeam:
+		return c.exists(`SELECT 1 FROM task_assignments ta
+                     JOIN team_members tm ON tm.user_id = ta.user_id
+                     WHERE ta.task_id = t.task_id AND tm.team_id = ANY(%s)`, e)
+	case model.QueryTitle:
+		return c.text([]string{"t.title"}, e)
+	case model.QueryDescription:
+		return c.text([]string{"t.description"}, e)
+	case model.QueryText:
+		return c.text([]string{"t.title", "t.description"}, e)
+	}
+	return "", model.Invalid("unknown query field %q", e.Field)
+}
+
+// column сравнение колонки со значением. convert приводит значения к типу колонки:
+// для IN и NOT IN - к срезу, для остальных операторов - к одному значению.
+func (c queryCompiler) column(column string, e model.QueryCond, convert func([]any) (any, error)) (string, error) {
+	v, err := convert(e.Values)
+	if err != nil {
+		return "", err
+	}
+	switch e.Op {
+	case model.OpIn:
+		return column + " = ANY(" + c.param(v) + ")", nil
+	case model.OpNotIn:
+		// как и !=, пустое значение колонки не входит в список
+		return "NOT COALESCE(" + column + " = ANY(" + c.param(v) + "), false)", nil
+	case model.OpNe:
+		return column + " IS DISTINCT FROM " + c.param(first(v)), nil
+	}
+	op, ok := sqlCompareOps[e.Op]
+	if !ok {
+		return "", model.Invalid("operator %s is not supported for field %s", e.Op, e.Field)
+	}
+	return column + " " + op + " " + c.param(first(v)), nil
+}
+
+// exists условие на связанную таблицу: задача связана хотя бы с одним из значений
+func (c queryCompiler) exists(subquery string, e model.QueryCond) (string, error) {
+	ids, err := toInts(e.Values)
+	if err != nil {
+		return "", err
+	}
+	cond := "EXISTS (" + fmt.Sprintf(subquery, c.param(ids)) + ")"
+	switch e.Op {
+	case model.OpEq, model.OpIn:
+		return cond, nil
+	case model.OpNe, model.OpNotIn:
+		return "NOT " + cond, nil
+	}
+	return "", model.Invalid("operator %s is not supported for field %s", e.Op, e.Field)
+}
+
+// text сравнение текстовых колонок без учёта регистра. Для нескольких колонок условие
+// выполняется, если подходит хотя бы одна.
+func (c queryCompiler) text(columns []string, e model.QueryCond) (string, error) {
+	s, ok := e.Values[0].(string)
+	if !ok {
+		return "", model.Invalid("invalid value for field %s", e.Field)
+	}
+	var pattern, op string
+	negate := false
+	switch e.Op {
+	case model.OpEq, model.OpNe:
+		pattern, op, negate = escapeLike(s), "ILIKE", e.Op == model.OpNe
+	case model.OpContains, model.OpNotContains:
+		pattern, op, negate = "%"+escapeLike(s)+"%", "ILIKE", e.Op == model.OpNotContains
+	default:
+		return "", model.Invalid("operator %s is not supported for field %s", e.Op, e.Field)
+	}
+	p := c.param(pattern)
+	parts := make([]string, len(columns))
+	for i, column := range columns {
+		parts[i] = "COALESCE(" + column + ", '') " + op + " " + p
+	}
+	cond := "(" + strings.Join(parts, " OR ") + ")"
+	if negate {
+		return "NOT " + cond, nil
+	}
+	return cond, nil
+}
+
+func first(v any) any {
+	switch s := v.(type) {
+	case []string:
+		return s[0]
+	case []int:
+		return s[0]
+	case []time.Time:
+		return s[0]
+	}
+	return v
+}
+
+func toStrings(values []any) (any, error) {
+	out := make([]string, len(values))
+	for i, v := range values {
+		s, ok := v.(string)
+		if !ok {
+			return nil, model.Invalid("invalid query value %v", v)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+func toInts(values []any) (any, error) {
+	out := make([]int, len(values))
+	for i, v := range values {
+		n, ok := v.(int)
+		if !ok {
+			return nil, model.Invalid("invalid query value %v", v)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// toTimes приводит время к UTC: в колонках TIMESTAMP хранится UTC
+func toTimes(values []any) (any, error) {
+	out := make([]time.Time, len(values))
+	for i, v := range values {
+		t, ok := v.(time.Time)
+		if !ok {
+			return nil, model.Invalid("invalid query value %v", v)
+		}
+		out[i] = t.UTC()
+	}
+	return out, nil
+}
@@ -0,0 +1,44 @@
+package repoStorage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/model"
+)
+
+func TestQueryCompiler_Negation(t *testing.T) {
+	tests := []struct {
+		name string
+		expr model.QueryExpr
+		want string
+	}{
+		// задачи без проекта проходят и project != 3, и project NOT IN (3, 4)
+		{
+			name: "not equal",
+			expr: model.QueryCond{Field: model.QueryProject, Op: model.OpNe, Values: []any{3}},
+			want: "t.project_id IS DISTINCT FROM $1",
+		},
+		{
+			name: "not in",
+			expr: model.QueryCond{Field: model.QueryProject, Op: model.OpNotIn, Values: []any{3, 4}},
+			want: "NOT COALESCE(t.project_id = ANY($1), false)",
+		},
+		{
+			name: "NOT",
+			expr: model.QueryNot{Expr: model.QueryCond{Field: model.QueryProject, Op: model.OpIn, Values: []any{3}}},
+			want: "NOT COALESCE(t.project_id = ANY($1), false)",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var args []any
+			got, err := queryCompiler{args: &args}.compile(tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+			require.Len(t, args, 1)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// получение незавершённых задач с дедлайном не позже before, включая просроченные.
+// Задачи, о просрочке которых уже напомнили всем исполнителям, не возвращаются: для них
+// напоминаний больше не будет, и выборка не растёт с каждой просроченной задачей.
+func (r *Repo) TasksDueBefore(ctx context.Context, before time.Time) ([]model.Task, error) {
+	const op = "storage.postgres.TasksDueBefore"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("retrieving unfinished tasks due before", slog.Time("before", before))
+
+	query := `SELECT ` + taskColumns + `
+              FROM tasks t
+              WHERE t.deadline IS NOT NULL
+              AND t.deadline <= $1
+              AND t.status IS DISTINCT FROM $2
+              AND EXISTS (SELECT 1 FROM task_assignments ta
+                          WHERE ta.task_id = t.task_id
+                          AND NOT EXISTS (SELECT 1 FROM deadline_reminders dr
+                                          WHERE dr.task_id = ta.task_id
+                                          AND dr.user_id = ta.user_id
+                                          AND dr.threshold_seconds = 0
+                                          AND dr.deadline = t.deadline))`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, before, model.TaskStatusDone)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var tasks []model.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return tasks, nil
+}
+
+// Резервирование напоминания. Возвращает false, если такое напоминание уже было отправлено
+// (в том числе другим экземпляром планировщика).
+func (r *Repo) ClaimReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) (bool, error) {
+	const op = "storage.postgres.ClaimReminder"
+	log := r.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	query := `INSERT INTO deadline_reminders (task_id, user_id, threshold_seconds, deadline)
+              VALUES ($1, $2, $3, $4)
+              ON CONFLICT DO NOTHING`
+	tag, err := r.postgres.Pool.Exec(ctx, query, taskID, userID, int64(threshold.Seconds()), deadline)
+	if err != nil {
+		log.Error("failed to claim reminder", sl.Err(err))
+		return false, fmt.Errorf("failed to claim reminder: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// Снятие резерва с напоминания, чтобы его можно было отправить повторно
+func (r *Repo) ReleaseReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) error {
+	const op = "storage.postgres.ReleaseReminder"
+	log := r.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("userID", userID))
+
+	query := `DELETE FROM deadline_reminders
+              WHERE task_id = $1 AND user_id = $2 AND threshold_seconds = $3 AND deadline = $4`
+	_, err := r.postgres.Pool.Exec(ctx, query, taskID, userID, int64(threshold.Seconds()), deadline)
+	if err != nil {
+		log.Error("failed to release reminder", sl.Err(err))
+		return fmt.Errorf("failed to release reminder: %w", err)
+	}
+	return nil
+}
@@ -2,10 +2,14 @@ package repoStorage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
 	"Tasks/internal/interfaces"
 	"Tasks/internal/lib/logger/sl"
 	"Tasks/internal/model"
@@ -21,86 +25,131 @@ func NewStorage(storage *postgres.Storage, log *slog.Logger) interfaces.StorageR
 	return &Repo{postgres: storage, log: log}
 }
 
-// создание задачи
-func (r *Repo) CreateNewTask(ctx context.Context, task model.Task) (int, error) { // возвращаем taskID
+// колонки задачи в порядке, ожидаемом scanTask
+const taskColumns = "t.task_id, t.title, t.description, t.status, t.priority, COALESCE(t.project_id, 0), " +
+	"t.deadline, t.created_at, t.updated_at, t.version, " +
+	"ARRAY(SELECT l.label FROM task_labels l WHERE l.task_id = t.task_id ORDER BY l.label)"
+
+// scanTask читает задачу; extra - приёмники колонок, выбранных после taskColumns
+func scanTask(row pgx.Row, extra ...any) (model.Task, error) {
+	var task model.Task
+	dest := append([]any{
+		&task.ID,
+		&task.NameTask,
+		&task.Description,
+		&task.Status,
+		&task.Priority,
+		&task.ProjectID,
+		&task.Deadline,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.Version,
+		&task.Labels,
+	}, extra...)
+	err := row.Scan(dest...)
+	return task, err
+}
+
+// Коды ошибок PostgreSQL, которые переводятся в доменные ошибки
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgNotNullViolation    = "23502"
+	pgCheckViolation      = "23514"
+	pgInvalidText         = "22P02"
+	pgInvalidDatetime     = "22007"
+	pgDatetimeOverflow    = "22008"
+)
+
+// pgError переводит нарушения ограничений PostgreSQL в доменные ошибки, остальные ошибки возвращает как есть
+func pgError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return model.Conflict("resource already exists")
+	case pgForeignKeyViolation:
+		return model.Conflict("referenced resource does not exist or is still in use")
+	case pgNotNullViolation, pgCheckViolation, pgInvalidText, pgInvalidDatetime, pgDatetimeOverflow:
+		return model.Invalid("invalid value")
+	}
+	return err
+}
+
+// создание задачи. Возвращает записанную строку: статус, даты создания и изменения и версию задаёт база.
+func (r *Repo) CreateNewTask(ctx context.Context, task model.Task) (model.Task, error) {
 	const op = "storage.postgres.CreateNewTask"
 	log := r.log.With(slog.String("op", op))
 	log.Info("create-new-task а new task")
 
-	query := "INSERT INTO tasks (title, description, deadline) " +
-		"VALUES ($1, $2, $3) RETURNING task_id"
-	err := r.postgres.Pool.QueryRow(ctx, query, task.NameTask, task.Description, task.Deadline).Scan(&task.ID)
+	query := "INSERT INTO tasks AS t (title, description, deadline, priority, project_id) " +
+		"VALUES ($1, $2, $3, $4, NULLIF($5, 0)) RETURNING " + taskColumns
+	// в колонке TIMESTAMP смещение не хранится, поэтому дедлайн записывается в UTC
+	created, err := scanTask(r.postgres.Pool.QueryRow(ctx, query, task.NameTask, task.Description, task.Deadline.UTC(), task.Priority, task.ProjectID))
 	if err != nil {
 		log.Error("failed to execute query", sl.Err(err))
-		return 0, fmt.Errorf("failed to create-new-task new task: %w", err)
+		return model.Task{}, fmt.Errorf("failed to create-new-task new task: %w", pgError(err))
 	}
-	log.Info("task created successfully", slog.Int("taskID", task.ID))
-	return task.ID, nil
+	log.Info("task created successfully", slog.Int("taskID", created.ID))
+	return created, nil
 }
 
-// получение всех пользователей работающих над задачей
-func (r *Repo) GetAllUsersWorkTask(ctx context.Context, taskID int) ([]model.User, error) {
+// получение страницы пользователей работающих над задачей, по возрастанию ID
+func (r *Repo) GetAllUsersWorkTask(ctx context.Context, taskID int, page model.PageRequest) (model.Page[model.User], error) {
 	const op = "storage.postgres.GetAllUsersWorkTask"
 	log := r.log.With(slog.String("op", op))
 	log.Info("getting all the users working on the task")
-	getUsers := "SELECT u.* FROM users u JOIN task_assignments ta ON u.user_id = ta.user_id WHERE ta.task_id = $1"
 
-	rows, err := r.postgres.Pool.Query(ctx, getUsers, taskID)
-	if err != nil {
-		log.Error("failed to execute query", sl.Err(err))
-		return nil, fmt.Errorf("failed to execute query: %w", err)
-	}
-	defer rows.Close()
-	var users []model.User
-	var ignored string
-	for rows.Next() {
-		var user model.User
-		err := rows.Scan(&user.ID, &user.Login, &ignored, &user.Level)
+	var result model.Page[model.User]
+	if page.WithTotal {
+		var total int
+		err := r.postgres.Pool.QueryRow(ctx, "SELECT count(*) FROM task_assignments WHERE task_id = $1", taskID).Scan(&total)
 		if err != nil {
-			log.Error("failed to scan row", sl.Err(err))
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			log.Error("failed to execute query", sl.Err(err))
+			return result, fmt.Errorf("failed to count users: %w", err)
 		}
-		users = append(users, user)
+		result.Total = &total
 	}
-	if err := rows.Err(); err != nil {
-		log.Error("row iteration error", sl.Err(err))
-		return nil, fmt.Errorf("row iteration error: %w", err)
-	}
-	log.Info("successfully retrieved users", slog.Int("userCount", len(users)))
-	return users, nil
-}
-
-// получение всех задач пользователя
-func (r *Repo) GetAllTasks(ctx context.Context, userID int) ([]model.Task, error) {
-	const op = "storage.postgres.GetAllTasks"
-	log := r.log.With(slog.String("op", op))
-	log.Info("retrieving all tasks for user")
 
-	getTasks := "SELECT t.* FROM tasks t JOIN task_assignments ta ON t.task_id = ta.task_id WHERE ta.user_id = $1;"
+	getUsers := "SELECT u.user_id, u.username, u.access_level, u.timezone, u.language FROM users u JOIN task_assignments ta ON u.user_id = ta.user_id WHERE ta.task_id = $1"
+	args := []any{taskID}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		getUsers += fmt.Sprintf(" AND u.user_id > $%d", len(args))
+	}
+	getUsers += " ORDER BY u.user_id"
+	if page.Limit > 0 {
+		args = append(args, page.Limit+1)
+		getUsers += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
 
-	rows, err := r.postgres.Pool.Query(ctx, getTasks, userID)
+	rows, err := r.postgres.Pool.Query(ctx, getUsers, args...)
 	if err != nil {
 		log.Error("failed to execute query", sl.Err(err))
-		return nil, fmt.Errorf("failed to execute query: %w", err)
+		return result, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
-	var tasks []model.Task
 	for rows.Next() {
-		var task model.Task
-		err := rows.Scan(&task.ID, &task.NameTask, &task.Description, &task.Status, &task.Deadline, &task.CreatedAt, &task.UpdatedAt)
+		var user model.User
+		err := rows.Scan(&user.ID, &user.Login, &user.Level, &user.Timezone, &user.Language)
 		if err != nil {
 			log.Error("failed to scan row", sl.Err(err))
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+			return result, fmt.Errorf("failed to scan row: %w", err)
 		}
-		tasks = append(tasks, task)
-
+		result.Items = append(result.Items, user)
 	}
 	if err := rows.Err(); err != nil {
 		log.Error("row iteration error", sl.Err(err))
-		return nil, fmt.Errorf("row iteration error: %w", err)
+		return result, fmt.Errorf("row iteration error: %w", err)
 	}
-	log.Info("successfully retrieved tasks", slog.Int("taskCount", len(tasks)))
-	return tasks, nil
+	if page.Limit > 0 && len(result.Items) > page.Limit {
+		result.Items = result.Items[:page.Limit]
+		result.Next = &model.Cursor{ID: result.Items[len(result.Items)-1].ID}
+	}
+	log.Info("successfully retrieved users", slog.Int("userCount", len(result.Items)))
+	return result, nil
 }
 
 // получение задач с приближающемся сроком
@@ -108,13 +157,13 @@ func (r *Repo) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task,
 	const op = "storage.postgres.TaskShortDeadline"
 	log := r.log.With(slog.String("op", op))
 	log.Info("retrieving tasks with short deadlines")
-	shortDeadline := `SELECT t.* 
+	shortDeadline := `SELECT ` + taskColumns + `
                   FROM tasks t 
                   JOIN task_assignments ta ON t.task_id = ta.task_id 
                   WHERE ta.user_id = $1 
                   AND t.deadline BETWEEN $2 AND $3;`
 
-	currentTime := time.Now()
+	currentTime := time.Now().UTC()
 	threeDaysLater := currentTime.Add(3 * 24 * time.Hour)
 
 	rows, err := r.postgres.Pool.Query(ctx, shortDeadline, userID, currentTime, threeDaysLater)
@@ -126,8 +175,7 @@ func (r *Repo) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task,
 	defer rows.Close()
 	var tasks []model.Task
 	for rows.Next() {
-		var task model.Task
-		err := rows.Scan(&task.ID, &task.NameTask, &task.Description, &task.Status, &task.Deadline, &task.CreatedAt, &task.UpdatedAt)
+		task, err := scanTask(rows)
 		if err != nil {
 			log.Error("failed to scan row", sl.Err(err))
 			return nil, fmt.Errorf("failed to scan row: %w", err)
@@ -144,19 +192,40 @@ func (r *Repo) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task,
 }
 
 // методы update
-func (r *Repo) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int) error {
+// TaskUpdateStatus меняет статус и увеличивает версию задачи. version = 0 - без проверки версии,
+// иначе задача меняется, только если её текущая версия равна version. Возвращает изменённую задачу.
+func (r *Repo) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int, version int) (model.Task, error) {
 	const op = "storage.postgres.TaskUpdateStatus"
 	log := r.log.With(slog.String("op", op))
 	log.Info("updating task status")
-	query := "UPDATE tasks SET status = $1, updated_at = CURRENT_TIMESTAMP WHERE task_id = $2"
+	query := `UPDATE tasks t SET status = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1
+              WHERE t.task_id = $2 AND ($3 = 0 OR t.version = $3)
+              RETURNING ` + taskColumns
 	// Выполняем запрос к базе данных.
-	_, err := r.postgres.Pool.Exec(ctx, query, newStatus, taskID)
+	task, err := scanTask(r.postgres.Pool.QueryRow(ctx, query, newStatus, taskID, version))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.Task{}, r.taskPrecondition(ctx, taskID)
+	}
 	if err != nil {
 		log.Error("failed to update task status", sl.Err(err))
-		return fmt.Errorf("failed to update task status: %w", err)
+		return model.Task{}, fmt.Errorf("failed to update task status: %w", pgError(err))
 	}
-	log.Info("task status updated successfully")
-	return nil
+	log.Info("task status updated successfully", slog.Int("version", task.Version))
+	return task, nil
+}
+
+// taskPrecondition объясняет, почему условное изменение задачи не затронуло строк:
+// задачи нет или её версия уже другая
+func (r *Repo) taskPrecondition(ctx context.Context, taskID int) error {
+	var current int
+	err := r.postgres.Pool.QueryRow(ctx, "SELECT version FROM tasks WHERE task_id = $1", taskID).Scan(&current)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.NotFound("task with ID %d not found", taskID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get task version: %w", err)
+	}
+	return model.PreconditionFailed("task %d has been modified, current version is %d", taskID, current)
 }
 
 // добавление пользователя к задачи
@@ -169,31 +238,61 @@ func (r *Repo) AddNewUserTask(ctx context.Context, userID int, taskID int) error
 	_, err := r.postgres.Pool.Exec(ctx, addUser, userID, taskID)
 	if err != nil {
 		log.Error("failed to add user to task", sl.Err(err))
-		return fmt.Errorf("failed to add user to task: %w", err)
+		return fmt.Errorf("failed to add user to task: %w", pgError(err))
 	}
 	log.Info("user successfully added to task")
 	return nil
 }
 
 // методы delete
-// удаление задачи
-func (r *Repo) DeleteTask(ctx context.Context, taskID int) error {
+// удаление задачи, version = 0 - без проверки версии. Возвращает исполнителей удалённой задачи,
+// назначения удаляются вместе с ней.
+func (r *Repo) DeleteTask(ctx context.Context, taskID int, version int) (int, []int, error) {
 	const op = "storage.postgres.DeleteTask"
 	log := r.log.With(slog.String("op", op))
 
 	log.Info("deleting a task")
 
-	query := "DELETE FROM tasks WHERE task_id = $1"
+	// основной запрос видит назначения в состоянии до удаления
+	query := `WITH deleted AS (
+                  DELETE FROM tasks WHERE task_id = $1 AND ($2 = 0 OR version = $2) RETURNING task_id, project_id
+              )
+              SELECT COALESCE(d.project_id, 0), ta.user_id FROM deleted d LEFT JOIN task_assignments ta ON ta.task_id = d.task_id`
 
 	// Выполняем запрос к базе данных
-	_, err := r.postgres.Pool.Exec(ctx, query, taskID)
+	rows, err := r.postgres.Pool.Query(ctx, query, taskID, version)
 	if err != nil {
 		log.Error("failed to execute query", sl.Err(err))
-		return fmt.Errorf("failed to delete task: %w", err)
+		return 0, nil, fmt.Errorf("failed to delete task: %w", pgError(err))
+	}
+	var (
+		deleted   bool
+		projectID int
+		assignees []int
+	)
+	for rows.Next() {
+		var userID *int
+		if err := rows.Scan(&projectID, &userID); err != nil {
+			rows.Close()
+			log.Error("failed to scan row", sl.Err(err))
+			return 0, nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		deleted = true
+		if userID != nil {
+			assignees = append(assignees, *userID)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, nil, fmt.Errorf("failed to delete task: %w", pgError(err))
+	}
+	if !deleted {
+		return 0, nil, r.taskPrecondition(ctx, taskID)
 	}
 
 	log.Info("task deleted successfully")
-	return nil
+	return projectID, assignees, nil
 }
 
 // Снятие пользователя с задачи
@@ -210,11 +309,14 @@ func (r *Repo) RemoveUserFromTask(ctx context.Context, userID int, taskID int) e
 	query := "DELETE FROM task_assignments WHERE user_id = $1 AND task_id = $2"
 
 	// Выполняем запрос к базе данных
-	_, err := r.postgres.Pool.Exec(ctx, query, userID, taskID)
+	tag, err := r.postgres.Pool.Exec(ctx, query, userID, taskID)
 	if err != nil {
 		log.Error("failed to execute query", sl.Err(err))
 		return fmt.Errorf("failed to remove user from task: %w", err)
 	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("user with ID %d is not assigned to task %d", userID, taskID)
+	}
 
 	log.Info("user removed from task successfully")
 	return nil
@@ -227,22 +329,13 @@ func (r *Repo) TaskByID(ctx context.Context, taskID int) (model.Task, error) {
 
 	log.Info("retrieving task by ID")
 
-	query := "SELECT task_id, title, description, status, deadline, created_at, updated_at FROM tasks WHERE task_id = $1"
+	query := "SELECT " + taskColumns + " FROM tasks t WHERE t.task_id = $1"
 
-	var task model.Task
-	err := r.postgres.Pool.QueryRow(ctx, query, taskID).Scan(
-		&task.ID,
-		&task.NameTask,
-		&task.Description,
-		&task.Status,
-		&task.Deadline,
-		&task.CreatedAt,
-		&task.UpdatedAt,
-	)
+	task, err := scanTask(r.postgres.Pool.QueryRow(ctx, query, taskID))
 	if err != nil {
 		log.Error("failed to execute query", sl.Err(err))
-		if err.Error() == "sql: no rows in result set" {
-			return model.Task{}, fmt.Errorf("task with ID %d not found", taskID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Task{}, model.NotFound("task with ID %d not found", taskID)
 		}
 		return model.Task{}, fmt.Errorf("failed to retrieve task by ID: %w", err)
 	}
@@ -0,0 +1,79 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	"Tasks/internal/storage/postgres"
+)
+
+// newTestRepo подключается к базе TEST_POSTGRES_URL с накатанными миграциями.
+// Без переменной окружения тесты хранилища пропускаются.
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+	pool, err := pgxpool.New(context.Background(), url)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+	return &Repo{postgres: &postgres.Storage{Pool: pool}, log: slogdiscard.NewDiscardLogger()}
+}
+
+// createTestTask создаёт задачу с дедлайном через сутки
+func createTestTask(t *testing.T, repo *Repo, title string) int {
+	t.Helper()
+	task, err := repo.CreateNewTask(context.Background(), model.Task{
+		NameTask: fmt.Sprintf("%s %d", title, time.Now().UnixNano()),
+		Deadline: time.Now().Add(24 * time.Hour),
+		Priority: model.PriorityMedium,
+	})
+	require.NoError(t, err)
+	return task.ID
+}
+
+func TestRepo_CreateNewTask_DeadlineOffset(t *testing.T) {
+	repo := newTestRepo(t)
+	// 12:00 по Москве - 09:00 UTC
+	deadline := time.Date(2030, 1, 15, 12, 0, 0, 0, time.FixedZone("+03:00", 3*60*60))
+	created, err := repo.CreateNewTask(context.Background(), model.Task{
+		NameTask: "deadline with offset",
+		Deadline: deadline,
+		Priority: model.PriorityMedium,
+	})
+	require.NoError(t, err)
+	assert.True(t, created.Deadline.Equal(deadline), "expected %v, got %v", deadline, created.Deadline)
+
+	task, err := repo.TaskByID(context.Background(), created.ID)
+	require.NoError(t, err)
+	assert.True(t, task.Deadline.Equal(deadline), "expected %v, got %v", deadline, task.Deadline)
+	assert.Equal(t, 9, task.Deadline.UTC().Hour())
+}
+
+func TestRepo_CreateNewTask(t *testing.T) {
+	repo := newTestRepo(t)
+	created, err := repo.CreateNewTask(context.Background(), model.Task{
+		NameTask: "stored row",
+		Deadline: time.Now().Add(time.Hour),
+		Priority: model.PriorityHigh,
+	})
+	require.NoError(t, err)
+	// значения по умолчанию заполняет база
+	assert.NotZero(t, created.ID)
+	assert.Equal(t, model.TaskStatusTodo, created.Status)
+	assert.Equal(t, model.PriorityHigh, created.Priority)
+	assert.Equal(t, 1, created.Version)
+	assert.False(t, created.CreatedAt.IsZero())
+	assert.False(t, created.UpdatedAt.IsZero())
+	assert.Empty(t, created.Labels)
+}
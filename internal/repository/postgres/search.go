@@ -0,0 +1,131 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// Совпадения во фрагментах ts_headline отмечаются управляющими символами, а не тегами:
+// текст задачи экранируется уже после выделения, и теги из него не попадают в ответ
+const (
+	markStart = "\x02"
+	markStop  = "\x03"
+
+	titleHeadline       = "StartSel=\x02, StopSel=\x03, HighlightAll=true"
+	descriptionHeadline = "StartSel=\x02, StopSel=\x03, MaxWords=35, MinWords=15, MaxFragments=2"
+)
+
+// searchRank релевантность задачи; веса заданы в колонке search_vector: название - A, описание - B
+const searchRank = "ts_rank(t.search_vector, q.query)"
+
+// searchQuery строит tsquery из слов, начиная с параметра $first. Каждое слово ищется по префиксу
+// в русской и английской конфигурации, все слова должны найтись.
+func searchQuery(words []string, first int) string {
+	terms := make([]string, len(words))
+	for i := range words {
+		n := first + i
+		terms[i] = fmt.Sprintf("(to_tsquery('russian', $%[1]d) || to_tsquery('english', $%[1]d))", n)
+	}
+	return strings.Join(terms, " && ")
+}
+
+// highlight экранирует фрагмент и заменяет отметки совпадений на <mark>
+func highlight(fragment string) string {
+	fragment = html.EscapeString(fragment)
+	return strings.NewReplacer(markStart, "<mark>", markStop, "</mark>").Replace(fragment)
+}
+
+// полнотекстовый поиск задач по словам words с учётом фильтра, по убыванию релевантности
+func (r *Repo) SearchTasks(ctx context.Context, words []string, filter model.TaskFilter, page model.PageRequest) (model.Page[model.SearchResult], error) {
+	const op = "storage.postgres.SearchTasks"
+	log := r.log.With(slog.String("op", op))
+	log.Info("searching tasks", slog.Int("wordCount", len(words)))
+
+	where, args, err := taskConditions(filter)
+	if err != nil {
+		return model.Page[model.SearchResult]{}, err
+	}
+	with := "WITH q AS (SELECT " + searchQuery(words, len(args)+1) + " AS query)"
+	for _, w := range words {
+		args = append(args, w+":*")
+	}
+	where = append([]string{"t.search_vector @@ q.query"}, where...)
+
+	var result model.Page[model.SearchResult]
+	if page.WithTotal {
+		countQuery := with + " SELECT count(*) FROM tasks t, q WHERE " + strings.Join(where, " AND ")
+		var total int
+		if err := r.postgres.Pool.QueryRow(ctx, countQuery, args...).Scan(&total); err != nil {
+			log.Error("failed to execute query", sl.Err(err))
+			return result, fmt.Errorf("failed to count search results: %w", err)
+		}
+		result.Total = &total
+	}
+
+	if page.After != nil {
+		args = append(args, page.After.Value, page.After.ID)
+		where = append(where, fmt.Sprintf("(%[1]s < $%[2]d::real OR (%[1]s = $%[2]d::real AND t.task_id > $%[3]d))",
+			searchRank, len(args)-1, len(args)))
+	}
+	limit := ""
+	if page.Limit > 0 {
+		args = append(args, page.Limit+1)
+		limit = fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	args = append(args, titleHeadline, descriptionHeadline)
+
+	// фрагменты строятся только для записей страницы, ts_headline дорогой
+	query := with + `, found AS (
+              SELECT t.task_id, ` + searchRank + ` AS rank
+              FROM tasks t, q
+              WHERE ` + strings.Join(where, " AND ") + `
+              ORDER BY rank DESC, t.task_id` + limit + `)
+              SELECT ` + taskColumns + `, f.rank,
+                     ts_headline('russian', t.title, q.query, $` + strconv.Itoa(len(args)-1) + `),
+                     ts_headline('russian', COALESCE(t.description, ''), q.query, $` + strconv.Itoa(len(args)) + `)
+              FROM found f
+              JOIN tasks t ON t.task_id = f.task_id
+              CROSS JOIN q
+              ORDER BY f.rank DESC, t.task_id`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return result, fmt.Errorf("failed to execute query: %w", pgError(err))
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res model.SearchResult
+		res.Task, err = scanTask(rows, &res.Rank, &res.TitleSnippet, &res.DescriptionSnippet)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return result, fmt.Errorf("failed to scan row: %w", err)
+		}
+		res.TitleSnippet = highlight(res.TitleSnippet)
+		res.DescriptionSnippet = highlight(res.DescriptionSnippet)
+		result.Items = append(result.Items, res)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return result, fmt.Errorf("row iteration error: %w", pgError(err))
+	}
+
+	if page.Limit > 0 && len(result.Items) > page.Limit {
+		result.Items = result.Items[:page.Limit]
+		last := result.Items[len(result.Items)-1]
+		result.Next = &model.Cursor{
+			Sort:  model.SortRank,
+			Value: strconv.FormatFloat(float64(last.Rank), 'g', -1, 32),
+			ID:    last.Task.ID,
+		}
+	}
+	log.Info("search completed", slog.Int("resultCount", len(result.Items)))
+	return result, nil
+}
@@ -0,0 +1,248 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// создание спринта
+func (r *Repo) CreateSprint(ctx context.Context, sprint model.Sprint) (int, error) {
+	const op = "storage.postgres.CreateSprint"
+	log := r.log.With(slog.String("op", op))
+	log.Info("creating a new sprint")
+
+	query := "INSERT INTO sprints (title, start_date, end_date) VALUES ($1, $2, $3) RETURNING sprint_id"
+	err := r.postgres.Pool.QueryRow(ctx, query, sprint.Title, sprint.StartDate.UTC(), sprint.EndDate.UTC()).Scan(&sprint.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create sprint: %w", pgError(err))
+	}
+	log.Info("sprint created successfully", slog.Int("sprintID", sprint.ID))
+	return sprint.ID, nil
+}
+
+// Получение спринта по ID
+func (r *Repo) SprintByID(ctx context.Context, sprintID int) (model.Sprint, error) {
+	const op = "storage.postgres.SprintByID"
+	log := r.log.With(slog.String("op", op), slog.Int("sprintID", sprintID))
+	log.Info("retrieving sprint by ID")
+
+	query := "SELECT sprint_id, title, status, start_date, end_date, created_at FROM sprints WHERE sprint_id = $1"
+
+	var sprint model.Sprint
+	err := r.postgres.Pool.QueryRow(ctx, query, sprintID).Scan(
+		&sprint.ID,
+		&sprint.Title,
+		&sprint.Status,
+		&sprint.StartDate,
+		&sprint.EndDate,
+		&sprint.CreatedAt,
+	)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return model.Sprint{}, model.NotFound("sprint with ID %d not found", sprintID)
+		}
+		return model.Sprint{}, fmt.Errorf("failed to retrieve sprint by ID: %w", err)
+	}
+	return sprint, nil
+}
+
+// добавление задачи в спринт
+func (r *Repo) AddTaskToSprint(ctx context.Context, sprintID int, taskID int) error {
+	const op = "storage.postgres.AddTaskToSprint"
+	log := r.log.With(slog.String("op", op), slog.Int("sprintID", sprintID), slog.Int("taskID", taskID))
+	log.Info("adding a task to a sprint")
+
+	query := "INSERT INTO sprint_tasks (sprint_id, task_id) VALUES ($1, $2)"
+	_, err := r.postgres.Pool.Exec(ctx, query, sprintID, taskID)
+	if err != nil {
+		log.Error("failed to add task to sprint", sl.Err(err))
+		return fmt.Errorf("failed to add task to sprint: %w", pgError(err))
+	}
+	log.Info("task successfully added to sprint")
+	return nil
+}
+
+// запуск спринта, запустить можно только запланированный спринт
+func (r *Repo) StartSprint(ctx context.Context, sprintID int) error {
+	const op = "storage.postgres.StartSprint"
+	log := r.log.With(slog.String("op", op), slog.Int("sprintID", sprintID))
+	log.Info("starting the sprint")
+
+	query := "UPDATE sprints SET status = $1 WHERE sprint_id = $2 AND status = $3"
+	tag, err := r.postgres.Pool.Exec(ctx, query, model.SprintActive, sprintID, model.SprintPlanned)
+	if err != nil {
+		log.Error("failed to start sprint", sl.Err(err))
+		return fmt.Errorf("failed to start sprint: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return model.Conflict("sprint with ID %d not found or is not planned", sprintID)
+	}
+	log.Info("sprint started successfully")
+	return nil
+}
+
+// Завершение спринта. Незавершённые задачи переносятся в nextSprintID,
+// а если он не задан - в ближайший запланированный спринт: они удаляются из завершённого спринта
+// и добавляются в следующий с пометкой carried_over. Если незавершённые задачи есть, а спринта для
+// переноса нет, спринт не завершается. Возвращает ID перенесённых задач.
+func (r *Repo) CompleteSprint(ctx context.Context, sprintID int, nextSprintID int) ([]int, error) {
+	const op = "storage.postgres.CompleteSprint"
+	log := r.log.With(slog.String("op", op), slog.Int("sprintID", sprintID))
+	log.Info("completing the sprint")
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var endDate time.Time
+	query := "UPDATE sprints SET status = $1 WHERE sprint_id = $2 AND status = $3 RETURNING end_date"
+	err = tx.QueryRow(ctx, query, model.SprintCompleted, sprintID, model.SprintActive).Scan(&endDate)
+	if err != nil {
+		log.Error("failed to complete sprint", sl.Err(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.Conflict("sprint with ID %d not found or is not active", sprintID)
+		}
+		return nil, fmt.Errorf("failed to complete sprint: %w", err)
+	}
+
+	if nextSprintID == 0 {
+		next := `SELECT sprint_id FROM sprints
+                 WHERE status = $1 AND sprint_id <> $2 AND start_date >= $3
+                 ORDER BY start_date, sprint_id LIMIT 1`
+		err = tx.QueryRow(ctx, next, model.SprintPlanned, sprintID, endDate).Scan(&nextSprintID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			log.Error("failed to find next sprint", sl.Err(err))
+			return nil, fmt.Errorf("failed to find next sprint: %w", err)
+		}
+	}
+
+	// задача остаётся только в одном спринте: строка завершённого спринта удаляется,
+	// а если задача уже есть в следующем спринте, новая строка не добавляется
+	carry := `WITH moved AS (
+                  DELETE FROM sprint_tasks st
+                  USING tasks t
+                  WHERE st.sprint_id = $2 AND t.task_id = st.task_id AND t.status IS DISTINCT FROM $3
+                  RETURNING st.task_id),
+              added AS (
+                  INSERT INTO sprint_tasks (sprint_id, task_id, carried_over)
+                  SELECT $1, task_id, TRUE FROM moved
+                  WHERE $1 <> 0
+                  ON CONFLICT (sprint_id, task_id) DO NOTHING)
+              SELECT task_id FROM moved ORDER BY task_id`
+	rows, err := tx.Query(ctx, carry, nextSprintID, sprintID, model.TaskStatusDone)
+	if err != nil {
+		log.Error("failed to carry over tasks", sl.Err(err))
+		return nil, fmt.Errorf("failed to carry over tasks: %w", pgError(err))
+	}
+	var carried []int
+	for rows.Next() {
+		var taskID int
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		carried = append(carried, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	if nextSprintID == 0 && len(carried) > 0 {
+		// транзакция откатывается: спринт остаётся активным, задачи - в нём
+		log.Info("no planned sprint to carry over unfinished tasks", slog.Int("unfinished", len(carried)))
+		return nil, model.Conflict("sprint %d has unfinished tasks and no next sprint", sprintID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("sprint completed successfully", slog.Int("nextSprintID", nextSprintID), slog.Int("carriedOver", len(carried)))
+	return carried, nil
+}
+
+// создание вехи
+func (r *Repo) CreateMilestone(ctx context.Context, milestone model.Milestone) (int, error) {
+	const op = "storage.postgres.CreateMilestone"
+	log := r.log.With(slog.String("op", op))
+	log.Info("creating a new milestone")
+
+	query := "INSERT INTO milestones (title, start_date, end_date) VALUES ($1, $2, $3) RETURNING milestone_id"
+	err := r.postgres.Pool.QueryRow(ctx, query, milestone.Title, milestone.StartDate.UTC(), milestone.EndDate.UTC()).Scan(&milestone.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create milestone: %w", pgError(err))
+	}
+	log.Info("milestone created successfully", slog.Int("milestoneID", milestone.ID))
+	return milestone.ID, nil
+}
+
+// привязка задачи к вехе
+func (r *Repo) SetTaskMilestone(ctx context.Context, taskID int, milestoneID int) error {
+	const op = "storage.postgres.SetTaskMilestone"
+	log := r.log.With(slog.String("op", op), slog.Int("taskID", taskID), slog.Int("milestoneID", milestoneID))
+	log.Info("attaching task to milestone")
+
+	query := "UPDATE tasks SET milestone_id = $1, updated_at = CURRENT_TIMESTAMP, version = version + 1 WHERE task_id = $2"
+	tag, err := r.postgres.Pool.Exec(ctx, query, milestoneID, taskID)
+	if err != nil {
+		log.Error("failed to attach task to milestone", sl.Err(err))
+		return fmt.Errorf("failed to attach task to milestone: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("task with ID %d not found", taskID)
+	}
+	log.Info("task attached to milestone successfully")
+	return nil
+}
+
+// получение задач пользователя, дедлайн которых позже даты их вехи
+func (r *Repo) TaskMilestoneOverrun(ctx context.Context, userID int) ([]model.Task, error) {
+	const op = "storage.postgres.TaskMilestoneOverrun"
+	log := r.log.With(slog.String("op", op))
+	log.Info("retrieving tasks with a deadline after their milestone")
+
+	query := `SELECT ` + taskColumns + `
+              FROM tasks t
+              JOIN task_assignments ta ON t.task_id = ta.task_id
+              JOIN milestones m ON t.milestone_id = m.milestone_id
+              WHERE ta.user_id = $1
+              AND t.deadline > m.end_date;`
+
+	rows, err := r.postgres.Pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var tasks []model.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	log.Info("successfully retrieved tasks", slog.Int("taskCount", len(tasks)))
+	return tasks, nil
+}
@@ -0,0 +1,97 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/model"
+)
+
+// sprintTaskIDs ID задач спринта по возрастанию
+func sprintTaskIDs(t *testing.T, repo *Repo, sprintID int) []int {
+	t.Helper()
+	page, err := repo.ListTasks(context.Background(), model.TaskFilter{SprintID: sprintID}, model.PageRequest{})
+	require.NoError(t, err)
+	ids := []int{}
+	for _, task := range page.Items {
+		ids = append(ids, task.ID)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func createTestSprint(t *testing.T, repo *Repo, start time.Time, active bool) int {
+	t.Helper()
+	ctx := context.Background()
+	sprintID, err := repo.CreateSprint(ctx, model.Sprint{Title: "sprint", StartDate: start, EndDate: start.Add(14 * 24 * time.Hour)})
+	require.NoError(t, err)
+	if active {
+		require.NoError(t, repo.StartSprint(ctx, sprintID))
+	}
+	return sprintID
+}
+
+func TestRepo_CompleteSprint(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	start := time.Now().UTC().Truncate(time.Second)
+
+	current := createTestSprint(t, repo, start, true)
+	next := createTestSprint(t, repo, start.Add(14*24*time.Hour), false)
+	done := createTestTask(t, repo, "done")
+	open := createTestTask(t, repo, "open")
+	// задача уже запланирована и в следующий спринт
+	planned := createTestTask(t, repo, "planned")
+	for _, taskID := range []int{done, open, planned} {
+		require.NoError(t, repo.AddTaskToSprint(ctx, current, taskID))
+	}
+	require.NoError(t, repo.AddTaskToSprint(ctx, next, planned))
+	_, err := repo.TaskUpdateStatus(ctx, model.TaskStatusDone, done, 0)
+	require.NoError(t, err)
+
+	carried, err := repo.CompleteSprint(ctx, current, next)
+	require.NoError(t, err)
+	assert.Equal(t, []int{open, planned}, carried)
+	// в завершённом спринте остаются только выполненные задачи, перенесённые - только в следующем
+	assert.Equal(t, []int{done}, sprintTaskIDs(t, repo, current))
+	assert.Equal(t, []int{open, planned}, sprintTaskIDs(t, repo, next))
+
+	sprint, err := repo.SprintByID(ctx, current)
+	require.NoError(t, err)
+	assert.Equal(t, model.SprintCompleted, sprint.Status)
+}
+
+func TestRepo_CompleteSprint_NoNextSprint(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	// после такого спринта запланированных спринтов нет
+	start := time.Date(9000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	current := createTestSprint(t, repo, start, true)
+	open := createTestTask(t, repo, "open")
+	require.NoError(t, repo.AddTaskToSprint(ctx, current, open))
+
+	_, err := repo.CompleteSprint(ctx, current, 0)
+	if !errors.Is(err, model.ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	// спринт остаётся активным, задача - в нём
+	assert.Equal(t, []int{open}, sprintTaskIDs(t, repo, current))
+	sprint, err := repo.SprintByID(ctx, current)
+	require.NoError(t, err)
+	assert.Equal(t, model.SprintActive, sprint.Status)
+
+	// без незавершённых задач спринт завершается и без следующего
+	_, err = repo.TaskUpdateStatus(ctx, model.TaskStatusDone, open, 0)
+	require.NoError(t, err)
+	carried, err := repo.CompleteSprint(ctx, current, 0)
+	require.NoError(t, err)
+	assert.Empty(t, carried)
+	assert.Equal(t, []int{open}, sprintTaskIDs(t, repo, current))
+}
@@ -0,0 +1,45 @@
+package repoStorage
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// TaskCounts незавершённые задачи по статусам и число просроченных среди них
+func (r *Repo) TaskCounts(ctx context.Context, now time.Time) (model.TaskCounts, error) {
+	const op = "storage.postgres.TaskCounts"
+	log := r.log.With(slog.String("op", op))
+
+	query := `SELECT t.status, count(*), count(*) FILTER (WHERE t.deadline < $1)
+              FROM tasks t
+              WHERE t.status IS DISTINCT FROM $2
+              GROUP BY t.status`
+	rows, err := r.postgres.Pool.Query(ctx, query, now.UTC(), model.TaskStatusDone)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return model.TaskCounts{}, fmt.Errorf("failed to count tasks: %w", pgError(err))
+	}
+	defer rows.Close()
+
+	counts := model.TaskCounts{ByStatus: make(map[string]int)}
+	for rows.Next() {
+		var status string
+		var total, overdue int
+		if err := rows.Scan(&status, &total, &overdue); err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return model.TaskCounts{}, fmt.Errorf("failed to scan row: %w", err)
+		}
+		counts.ByStatus[status] = total
+		counts.Overdue += overdue
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return model.TaskCounts{}, fmt.Errorf("row iteration error: %w", err)
+	}
+	return counts, nil
+}
@@ -0,0 +1,222 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// SyncChanges изменения задач, видимых пользователю scope.UserID, с номерами после since, не больше limit.
+// since = 0 или номер до горизонта удалённых надгробий - все видимые задачи без надгробий, Reset.
+func (r *Repo) SyncChanges(ctx context.Context, scope model.EventScope, since int64, limit int) (model.SyncChanges, error) {
+	const op = "storage.postgres.SyncChanges"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", scope.UserID), slog.Int64("since", since))
+
+	changes := model.SyncChanges{Tombstones: []model.Tombstone{}}
+	watermark, err := r.syncWatermark(ctx)
+	if errors.Is(err, errWatermarkPending) {
+		// долгая транзакция ещё не завершилась: клиент получит новые изменения в следующий раз
+		log.Warn("change numbers are still in progress, no new changes returned")
+		watermark, err = since, nil
+	}
+	if err != nil {
+		log.Error("failed to get sync watermark", sl.Err(err))
+		return changes, fmt.Errorf("failed to get sync watermark: %w", err)
+	}
+
+	// изменения и задачи читаются из одного снимка, иначе задача могла бы измениться между запросами
+	tx, err := r.postgres.Pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return changes, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var horizon int64
+	if err := tx.QueryRow(ctx, "SELECT horizon FROM sync_horizon").Scan(&horizon); err != nil {
+		log.Error("failed to get sync horizon", sl.Err(err))
+		return changes, fmt.Errorf("failed to get sync horizon: %w", err)
+	}
+	if since < horizon {
+		if since > 0 {
+			log.Info("sync token is older than the tombstone horizon", slog.Int64("horizon", horizon))
+		}
+		since = 0
+	}
+	changes.Reset = since == 0
+
+	// Надгробие видно тем, кто мог видеть задачу: исполнителям на момент удаления, их командам
+	// и руководителю проекта. Снятие исполнителя - надгробие, только если задача ещё существует
+	// и больше не видна пользователю.
+	query := `SELECT seq, task_id, reason, deleted_at FROM (
+                  SELECT t.change_seq AS seq, t.task_id, '' AS reason, NULL::timestamp AS deleted_at
+                  FROM tasks t
+                  WHERE t.change_seq > $2 AND t.change_seq <= $3 AND ` + fmt.Sprintf(visibleTo, 1) + `
+                  UNION ALL
+                  SELECT s.change_seq, s.task_id,
+                         CASE s.entity WHEN 'task' THEN '` + model.TombstoneDeleted + `' ELSE '` + model.TombstoneHidden + `' END,
+                         s.deleted_at
+                  FROM sync_tombstones s
+                  WHERE $2 > 0 AND s.change_seq > $2 AND s.change_seq <= $3
+                    AND ($5 OR s.project_id = ANY($6) OR s.assignees && $7 OR $1 = ANY(s.assignees))
+                    AND (s.entity = 'task' OR NOT EXISTS (
+                         SELECT 1 FROM tasks t WHERE t.task_id = s.task_id AND ` + fmt.Sprintf(visibleTo, 1) + `))
+              ) c
+              ORDER BY seq
+              LIMIT $4`
+	rows, err := tx.Query(ctx, query, scope.UserID, since, watermark, limit+1, scope.Admin, scope.Projects, scope.Users)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return changes, fmt.Errorf("failed to get changes: %w", pgError(err))
+	}
+	var taskIDs []int
+	for rows.Next() {
+		var seq int64
+		var taskID int
+		var reason string
+		var deletedAt *time.Time
+		if err := rows.Scan(&seq, &taskID, &reason, &deletedAt); err != nil {
+			rows.Close()
+			log.Error("failed to scan row", sl.Err(err))
+			return changes, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if len(taskIDs)+len(changes.Tombstones) == limit {
+			changes.More = true
+			break
+		}
+		changes.Seq = seq
+		if reason == "" {
+			taskIDs = append(taskIDs, taskID)
+			continue
+		}
+		changes.Tombstones = append(changes.Tombstones, model.Tombstone{TaskID: taskID, Reason: reason, DeletedAt: *deletedAt})
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		log.Error("failed to read rows", sl.Err(rows.Err()))
+		return changes, fmt.Errorf("failed to read rows: %w", rows.Err())
+	}
+	if !changes.More {
+		// изменений больше нет: следующая синхронизация продолжается с watermark
+		changes.Seq = watermark
+	}
+
+	changes.Tasks, changes.Assignments, err = syncTasks(ctx, tx, taskIDs)
+	if err != nil {
+		log.Error("failed to get changed tasks", sl.Err(err))
+		return changes, fmt.Errorf("failed to get changed tasks: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return changes, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("sync changes retrieved", slog.Int("tasks", len(changes.Tasks)), slog.Int("tombstones", len(changes.Tombstones)))
+	return changes, nil
+}
+
+// syncWatermarkWait сколько синхронизация ждёт транзакции, получившие номера изменений
+const syncWatermarkWait = 2 * time.Second
+
+// errWatermarkPending транзакции с выданными номерами не завершились за syncWatermarkWait
+var errWatermarkPending = errors.New("transactions with change numbers are still in progress")
+
+// syncWatermark наибольший номер изменения, до которого включительно все изменения зафиксированы или отменены.
+// next_change_seq получает xid транзакции до номера, поэтому каждая транзакция с номером не больше last
+// есть в снимке, снятом после чтения last: достаточно дождаться тех из них, что ещё выполняются.
+// Пишущие транзакции при этом не блокируются.
+func (r *Repo) syncWatermark(ctx context.Context) (int64, error) {
+	var last int64
+	var called bool
+	if err := r.postgres.Pool.QueryRow(ctx, "SELECT last_value, is_called FROM change_seq").Scan(&last, &called); err != nil {
+		return 0, err
+	}
+	if !called {
+		return 0, nil
+	}
+	var snapshot string
+	if err := r.postgres.Pool.QueryRow(ctx, "SELECT pg_current_snapshot()::text").Scan(&snapshot); err != nil {
+		return 0, err
+	}
+
+	query := `SELECT NOT EXISTS (
+                  SELECT 1 FROM pg_snapshot_xip($1::pg_snapshot) x WHERE pg_xact_status(x) = 'in progress')`
+	deadline := time.Now().Add(syncWatermarkWait)
+	for {
+		var done bool
+		if err := r.postgres.Pool.QueryRow(ctx, query, snapshot).Scan(&done); err != nil {
+			return 0, err
+		}
+		if done {
+			return last, nil
+		}
+		if time.Now().After(deadline) {
+			return 0, errWatermarkPending
+		}
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(20 * time.Millisecond):
+		}
+	}
+}
+
+// syncTasks задачи и их исполнители в порядке номеров изменений
+func syncTasks(ctx context.Context, tx pgx.Tx, taskIDs []int) ([]model.Task, []model.TaskAssignees, error) {
+	tasks := make([]model.Task, 0, len(taskIDs))
+	assignments := make([]model.TaskAssignees, 0, len(taskIDs))
+	if len(taskIDs) == 0 {
+		return tasks, assignments, nil
+	}
+	query := `SELECT ` + taskColumns + `,
+                     ARRAY(SELECT ta.user_id FROM task_assignments ta WHERE ta.task_id = t.task_id ORDER BY ta.user_id)
+              FROM tasks t
+              WHERE t.task_id = ANY($1)
+              ORDER BY t.change_seq`
+	rows, err := tx.Query(ctx, query, taskIDs)
+	if err != nil {
+		return nil, nil, pgError(err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		assignees := model.TaskAssignees{}
+		task, err := scanTask(rows, &assignees.UserIDs)
+		if err != nil {
+			return nil, nil, err
+		}
+		assignees.TaskID = task.ID
+		tasks = append(tasks, task)
+		assignments = append(assignments, assignees)
+	}
+	return tasks, assignments, rows.Err()
+}
+
+// PruneSyncTombstones удаляет надгробия старше before и сдвигает горизонт синхронизации:
+// клиенты с более старым токеном получат все задачи заново
+func (r *Repo) PruneSyncTombstones(ctx context.Context, before time.Time) (int, error) {
+	const op = "storage.postgres.PruneSyncTombstones"
+	log := r.log.With(slog.String("op", op))
+
+	query := `WITH pruned AS (
+                  DELETE FROM sync_tombstones WHERE deleted_at < $1 RETURNING change_seq
+              ), horizon AS (
+                  UPDATE sync_horizon SET horizon = GREATEST(horizon, (SELECT max(change_seq) FROM pruned))
+                  WHERE EXISTS (SELECT 1 FROM pruned)
+              )
+              SELECT count(*) FROM pruned`
+	var pruned int
+	if err := r.postgres.Pool.QueryRow(ctx, query, before.UTC()).Scan(&pruned); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to prune sync tombstones: %w", pgError(err))
+	}
+	if pruned > 0 {
+		log.Info("sync tombstones pruned", slog.Int("pruned", pruned))
+	}
+	return pruned, nil
+}
@@ -0,0 +1,37 @@
+package repoStorage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepo_SyncWatermark_LongTransaction(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+	taskID := createTestTask(t, repo, "watermark")
+
+	// долгая транзакция получила номер изменения и ещё не завершилась
+	tx, err := repo.postgres.Pool.Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+	var pending int64
+	err = tx.QueryRow(ctx, "UPDATE tasks SET version = version WHERE task_id = $1 RETURNING change_seq", taskID).Scan(&pending)
+	require.NoError(t, err)
+
+	// синхронизация ждёт долгую транзакцию, но запись других транзакций не ждёт синхронизацию
+	waited := make(chan error, 1)
+	go func() {
+		_, err := repo.syncWatermark(ctx)
+		waited <- err
+	}()
+	createTestTask(t, repo, "watermark concurrent")
+	assert.ErrorIs(t, <-waited, errWatermarkPending)
+
+	require.NoError(t, tx.Commit(ctx))
+	watermark, err := repo.syncWatermark(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, watermark, pending)
+}
@@ -0,0 +1,111 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// получение часового пояса пользователя
+func (r *Repo) UserTimezone(ctx context.Context, userID int) (string, error) {
+	const op = "storage.postgres.UserTimezone"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	var timezone string
+	err := r.postgres.Pool.QueryRow(ctx, "SELECT timezone FROM users WHERE user_id = $1", userID).Scan(&timezone)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", model.NotFound("user with ID %d not found", userID)
+		}
+		return "", fmt.Errorf("failed to retrieve user timezone: %w", err)
+	}
+	return timezone, nil
+}
+
+// изменение часового пояса пользователя
+func (r *Repo) SetUserTimezone(ctx context.Context, userID int, timezone string) error {
+	const op = "storage.postgres.SetUserTimezone"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+	log.Info("updating user timezone")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "UPDATE users SET timezone = $1 WHERE user_id = $2", timezone, userID)
+	if err != nil {
+		log.Error("failed to update user timezone", sl.Err(err))
+		return fmt.Errorf("failed to update user timezone: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("user with ID %d not found", userID)
+	}
+	return nil
+}
+
+// получение языка пользователя для уведомлений
+func (r *Repo) UserLanguage(ctx context.Context, userID int) (string, error) {
+	const op = "storage.postgres.UserLanguage"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+
+	var language string
+	err := r.postgres.Pool.QueryRow(ctx, "SELECT language FROM users WHERE user_id = $1", userID).Scan(&language)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", model.NotFound("user with ID %d not found", userID)
+		}
+		return "", fmt.Errorf("failed to retrieve user language: %w", err)
+	}
+	return language, nil
+}
+
+// изменение языка пользователя
+func (r *Repo) SetUserLanguage(ctx context.Context, userID int, language string) error {
+	const op = "storage.postgres.SetUserLanguage"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+	log.Info("updating user language")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "UPDATE users SET language = $1 WHERE user_id = $2", language, userID)
+	if err != nil {
+		log.Error("failed to update user language", sl.Err(err))
+		return fmt.Errorf("failed to update user language: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("user with ID %d not found", userID)
+	}
+	return nil
+}
+
+// создание команды
+func (r *Repo) CreateTeam(ctx context.Context, team model.Team) (int, error) {
+	const op = "storage.postgres.CreateTeam"
+	log := r.log.With(slog.String("op", op))
+	log.Info("creating a new team")
+
+	err := r.postgres.Pool.QueryRow(ctx, "INSERT INTO teams (name) VALUES ($1) RETURNING team_id", team.Name).Scan(&team.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create team: %w", pgError(err))
+	}
+	log.Info("team created successfully", slog.Int("teamID", team.ID))
+	return team.ID, nil
+}
+
+// добавление пользователя в команду
+func (r *Repo) AddTeamMember(ctx context.Context, teamID int, userID int) error {
+	const op = "storage.postgres.AddTeamMember"
+	log := r.log.With(slog.String("op", op), slog.Int("teamID", teamID), slog.Int("userID", userID))
+	log.Info("adding a user to a team")
+
+	_, err := r.postgres.Pool.Exec(ctx, "INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)", teamID, userID)
+	if err != nil {
+		log.Error("failed to add user to team", sl.Err(err))
+		return fmt.Errorf("failed to add user to team: %w", pgError(err))
+	}
+	log.Info("user successfully added to team")
+	return nil
+}
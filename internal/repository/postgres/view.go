@@ -0,0 +1,352 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const viewColumns = `v.view_id, v.owner_id, COALESCE(v.project_id, 0), v.name, v.query, v.sort, v.sort_desc,
+                     v.columns, v.is_default, v.created_at, v.updated_at`
+
+func scanView(row pgx.Row, extra ...any) (model.View, error) {
+	var v model.View
+	dest := append([]any{&v.ID, &v.OwnerID, &v.ProjectID, &v.Name, &v.Query, &v.Sort, &v.Desc,
+		&v.Columns, &v.IsDefault, &v.CreatedAt, &v.UpdatedAt}, extra...)
+	err := row.Scan(dest...)
+	return v, err
+}
+
+// projectMember условие участия пользователя user в проекте project (выражения SQL): он администратор,
+// руководит проектом или назначен на одну из его задач
+func projectMember(user, project string) string {
+	return `(EXISTS (SELECT 1 FROM users u WHERE u.user_id = ` + user + ` AND u.access_level >= ` + strconv.Itoa(model.AccessLevelAdmin) + `)
+     OR EXISTS (SELECT 1 FROM projects p WHERE p.project_id = ` + project + ` AND p.manager_id = ` + user + `)
+     OR EXISTS (SELECT 1 FROM tasks pt JOIN task_assignments ta ON ta.task_id = pt.task_id
+                WHERE pt.project_id = ` + project + ` AND ta.user_id = ` + user + `))`
+}
+
+// clearDefaultView снимает отметку "по умолчанию" с остальных представлений владельца
+func clearDefaultView(ctx context.Context, tx pgx.Tx, view model.View) error {
+	_, err := tx.Exec(ctx, "UPDATE views SET is_default = FALSE WHERE owner_id = $1 AND is_default AND view_id <> $2",
+		view.OwnerID, view.ID)
+	return err
+}
+
+// создание представления. Новое представление по умолчанию заменяет прежнее.
+func (r *Repo) CreateView(ctx context.Context, view model.View) (int, error) {
+	const op = "storage.postgres.CreateView"
+	log := r.log.With(slog.String("op", op), slog.Int("ownerID", view.OwnerID))
+	log.Info("creating a new view")
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if view.IsDefault {
+		if err := clearDefaultView(ctx, tx, view); err != nil {
+			log.Error("failed to execute query", sl.Err(err))
+			return 0, fmt.Errorf("failed to reset default view: %w", pgError(err))
+		}
+	}
+	query := `INSERT INTO views (owner_id, project_id, name, query, sort, sort_desc, columns, is_default)
+              VALUES ($1, NULLIF($2, 0), $3, $4, $5, $6, $7, $8) RETURNING view_id`
+	err = tx.QueryRow(ctx, query, view.OwnerID, view.ProjectID, view.Name, view.Query, view.Sort, view.Desc,
+		view.Columns, view.IsDefault).Scan(&view.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create view: %w", pgError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("view created successfully", slog.Int("viewID", view.ID))
+	return view.ID, nil
+}
+
+// изменение представления. Владелец не меняется.
+func (r *Repo) UpdateView(ctx context.Context, view model.View) error {
+	const op = "storage.postgres.UpdateView"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", view.ID))
+	log.Info("updating the view")
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if view.IsDefault {
+		if err := clearDefaultView(ctx, tx, view); err != nil {
+			log.Error("failed to execute query", sl.Err(err))
+			return fmt.Errorf("failed to reset default view: %w", pgError(err))
+		}
+	}
+	query := `UPDATE views SET project_id = NULLIF($2, 0), name = $3, query = $4, sort = $5, sort_desc = $6,
+                                columns = $7, is_default = $8, updated_at = CURRENT_TIMESTAMP
+              WHERE view_id = $1`
+	tag, err := tx.Exec(ctx, query, view.ID, view.ProjectID, view.Name, view.Query, view.Sort, view.Desc,
+		view.Columns, view.IsDefault)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to update view: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("view with ID %d not found", view.ID)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	log.Info("view updated successfully")
+	return nil
+}
+
+// удаление представления вместе с подписками на него
+func (r *Repo) DeleteView(ctx context.Context, viewID int) error {
+	const op = "storage.postgres.DeleteView"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID))
+	log.Info("deleting the view")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "DELETE FROM views WHERE view_id = $1", viewID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to delete view: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("view with ID %d not found", viewID)
+	}
+	log.Info("view deleted successfully")
+	return nil
+}
+
+// получение представления по ID
+func (r *Repo) ViewByID(ctx context.Context, viewID int) (model.View, error) {
+	const op = "storage.postgres.ViewByID"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID))
+	log.Debug("retrieving the view")
+
+	view, err := scanView(r.postgres.Pool.QueryRow(ctx, "SELECT "+viewColumns+" FROM views v WHERE v.view_id = $1", viewID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return view, model.NotFound("view with ID %d not found", viewID)
+		}
+		log.Error("failed to execute query", sl.Err(err))
+		return view, fmt.Errorf("failed to get view: %w", pgError(err))
+	}
+	return view, nil
+}
+
+// представления, доступные пользователю: собственные и открытые для проектов, в которых он участвует.
+// Представление по умолчанию идёт первым.
+func (r *Repo) Views(ctx context.Context, userID int) ([]model.View, error) {
+	const op = "storage.postgres.Views"
+	log := r.log.With(slog.String("op", op), slog.Int("userID", userID))
+	log.Debug("retrieving views")
+
+	query := "SELECT " + viewColumns + ` FROM views v
+              WHERE v.owner_id = $1 OR (v.project_id IS NOT NULL AND ` + projectMember("$1", "v.project_id") + `)
+              ORDER BY (v.owner_id = $1 AND v.is_default) DESC, v.name, v.view_id`
+	rows, err := r.postgres.Pool.Query(ctx, query, userID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", pgError(err))
+	}
+	defer rows.Close()
+	var views []model.View
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		views = append(views, view)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", pgError(err))
+	}
+	return views, nil
+}
+
+// ProjectMember сообщает, участвует ли пользователь в проекте
+func (r *Repo) ProjectMember(ctx context.Context, projectID int, userID int) (bool, error) {
+	const op = "storage.postgres.ProjectMember"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID), slog.Int("userID", userID))
+
+	var member bool
+	query := "SELECT " + projectMember("$1", "$2")
+	if err := r.postgres.Pool.QueryRow(ctx, query, userID, projectID).Scan(&member); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return false, fmt.Errorf("failed to check project membership: %w", pgError(err))
+	}
+	return member, nil
+}
+
+// подписка пользователя на представление, повторная подписка ничего не меняет
+func (r *Repo) SubscribeView(ctx context.Context, viewID int, userID int) error {
+	const op = "storage.postgres.SubscribeView"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID), slog.Int("userID", userID))
+	log.Info("subscribing to the view")
+
+	query := "INSERT INTO view_subscriptions (view_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING"
+	if _, err := r.postgres.Pool.Exec(ctx, query, viewID, userID); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to subscribe to view: %w", pgError(err))
+	}
+	return nil
+}
+
+// отмена подписки на представление
+func (r *Repo) UnsubscribeView(ctx context.Context, viewID int, userID int) error {
+	const op = "storage.postgres.UnsubscribeView"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID), slog.Int("userID", userID))
+	log.Info("unsubscribing from the view")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "DELETE FROM view_subscriptions WHERE view_id = $1 AND user_id = $2", viewID, userID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to unsubscribe from view: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("user with ID %d is not subscribed to view %d", userID, viewID)
+	}
+	return nil
+}
+
+// ViewSubscriptions подписки на представления, к которым у подписчика сохранился доступ
+func (r *Repo) ViewSubscriptions(ctx context.Context) ([]model.ViewSubscription, error) {
+	const op = "storage.postgres.ViewSubscriptions"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("retrieving view subscriptions")
+
+	query := "SELECT " + viewColumns + `, s.user_id
+              FROM view_subscriptions s
+              JOIN views v ON v.view_id = s.view_id
+              WHERE s.user_id = v.owner_id
+                 OR (v.project_id IS NOT NULL AND ` + projectMember("s.user_id", "v.project_id") + `)
+              ORDER BY s.view_id, s.user_id`
+
+	rows, err := r.postgres.Pool.Query(ctx, query)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", pgError(err))
+	}
+	defer rows.Close()
+	var subscriptions []model.ViewSubscription
+	for rows.Next() {
+		var s model.ViewSubscription
+		s.View, err = scanView(rows, &s.UserID)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", pgError(err))
+	}
+	return subscriptions, nil
+}
+
+// UpdateViewMatches запоминает задачи, которые сейчас видит подписчик в представлении, и возвращает
+// задачи, которых в нём не было при прошлом вычислении. Первое вычисление после подписки только
+// запоминает задачи. Строка подписки блокируется, поэтому несколько экземпляров планировщика
+// не уведомят о задаче дважды.
+func (r *Repo) UpdateViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) ([]int, error) {
+	const op = "storage.postgres.UpdateViewMatches"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID), slog.Int("userID", userID))
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var evaluated bool
+	query := "SELECT evaluated_at IS NOT NULL FROM view_subscriptions WHERE view_id = $1 AND user_id = $2 FOR UPDATE"
+	if err := tx.QueryRow(ctx, query, viewID, userID).Scan(&evaluated); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.NotFound("user with ID %d is not subscribed to view %d", userID, viewID)
+		}
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to lock view subscription: %w", pgError(err))
+	}
+
+	// задачи, покинувшие представление, при возвращении снова попадут в уведомления
+	query = "DELETE FROM view_matches WHERE view_id = $1 AND user_id = $2 AND NOT (task_id = ANY($3))"
+	if _, err := tx.Exec(ctx, query, viewID, userID, taskIDs); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to delete view matches: %w", pgError(err))
+	}
+	// задачу могли удалить после выборки, поэтому идентификаторы проверяются по таблице задач
+	query = `INSERT INTO view_matches (view_id, user_id, task_id)
+             SELECT $1, $2, t.task_id FROM tasks t WHERE t.task_id = ANY($3)
+             ON CONFLICT DO NOTHING
+             RETURNING task_id`
+	rows, err := tx.Query(ctx, query, viewID, userID, taskIDs)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to insert view matches: %w", pgError(err))
+	}
+	var entered []int
+	for rows.Next() {
+		var taskID int
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		entered = append(entered, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", pgError(err))
+	}
+
+	query = "UPDATE view_subscriptions SET evaluated_at = CURRENT_TIMESTAMP WHERE view_id = $1 AND user_id = $2"
+	if _, err := tx.Exec(ctx, query, viewID, userID); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to update view subscription: %w", pgError(err))
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	if !evaluated {
+		return nil, nil
+	}
+	return entered, nil
+}
+
+// ReleaseViewMatches забывает задачи, о попадании которых в представление подписчик не был уведомлен,
+// чтобы уведомление ушло при следующем вычислении
+func (r *Repo) ReleaseViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) error {
+	const op = "storage.postgres.ReleaseViewMatches"
+	log := r.log.With(slog.String("op", op), slog.Int("viewID", viewID), slog.Int("userID", userID))
+
+	query := "DELETE FROM view_matches WHERE view_id = $1 AND user_id = $2 AND task_id = ANY($3)"
+	if _, err := r.postgres.Pool.Exec(ctx, query, viewID, userID, taskIDs); err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to release view matches: %w", pgError(err))
+	}
+	return nil
+}
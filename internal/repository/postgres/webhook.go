@@ -0,0 +1,346 @@
+package repoStorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+const webhookColumns = `w.webhook_id, w.project_id, w.url, w.events, w.secret, w.active, w.failures, w.disabled_at,
+                        COALESCE(w.created_by, 0), w.created_at, w.updated_at`
+
+func scanWebhook(row pgx.Row) (model.Webhook, error) {
+	var w model.Webhook
+	err := row.Scan(&w.ID, &w.ProjectID, &w.URL, &w.Events, &w.Secret, &w.Active, &w.Failures, &w.DisabledAt,
+		&w.CreatedBy, &w.CreatedAt, &w.UpdatedAt)
+	return w, err
+}
+
+const deliveryColumns = `d.delivery_id, d.webhook_id, d.event_id, d.event_type, d.payload, d.status, d.attempts,
+                         d.next_attempt_at, COALESCE(d.response_status, 0), d.last_error, COALESCE(d.replay_of, 0),
+                         d.created_at, d.delivered_at`
+
+func scanDelivery(row pgx.Row, extra ...any) (model.WebhookDelivery, error) {
+	var d model.WebhookDelivery
+	dest := append([]any{&d.ID, &d.WebhookID, &d.EventID, &d.EventType, &d.Payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.ReplayOf, &d.CreatedAt, &d.DeliveredAt}, extra...)
+	err := row.Scan(dest...)
+	return d, err
+}
+
+// создание вебхука проекта
+func (r *Repo) CreateWebhook(ctx context.Context, webhook model.Webhook) (int, error) {
+	const op = "storage.postgres.CreateWebhook"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", webhook.ProjectID))
+	log.Info("creating a new webhook")
+
+	query := `INSERT INTO webhooks (project_id, url, events, secret, created_by)
+              VALUES ($1, $2, $3, $4, NULLIF($5, 0)) RETURNING webhook_id`
+	err := r.postgres.Pool.QueryRow(ctx, query, webhook.ProjectID, webhook.URL, webhook.Events, webhook.Secret,
+		webhook.CreatedBy).Scan(&webhook.ID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to create webhook: %w", pgError(err))
+	}
+	log.Info("webhook created successfully", slog.Int("webhookID", webhook.ID))
+	return webhook.ID, nil
+}
+
+// изменение вебхука. Secret = "" - ключ подписи не меняется. Включение сбрасывает счётчик неудач.
+func (r *Repo) UpdateWebhook(ctx context.Context, webhook model.Webhook) error {
+	const op = "storage.postgres.UpdateWebhook"
+	log := r.log.With(slog.String("op", op), slog.Int("webhookID", webhook.ID))
+	log.Info("updating the webhook")
+
+	query := `UPDATE webhooks
+              SET url = $2, events = $3, secret = COALESCE(NULLIF($4, ''), secret), active = $5,
+                  failures = CASE WHEN $5 AND NOT active THEN 0 ELSE failures END,
+                  disabled_at = CASE WHEN $5 THEN NULL ELSE COALESCE(disabled_at, CURRENT_TIMESTAMP) END,
+                  updated_at = CURRENT_TIMESTAMP
+              WHERE webhook_id = $1`
+	tag, err := r.postgres.Pool.Exec(ctx, query, webhook.ID, webhook.URL, webhook.Events, webhook.Secret, webhook.Active)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to update webhook: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("webhook with ID %d not found", webhook.ID)
+	}
+	log.Info("webhook updated successfully")
+	return nil
+}
+
+// удаление вебхука вместе с журналом доставок
+func (r *Repo) DeleteWebhook(ctx context.Context, webhookID int) error {
+	const op = "storage.postgres.DeleteWebhook"
+	log := r.log.With(slog.String("op", op), slog.Int("webhookID", webhookID))
+	log.Info("deleting the webhook")
+
+	tag, err := r.postgres.Pool.Exec(ctx, "DELETE FROM webhooks WHERE webhook_id = $1", webhookID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return fmt.Errorf("failed to delete webhook: %w", pgError(err))
+	}
+	if tag.RowsAffected() == 0 {
+		return model.NotFound("webhook with ID %d not found", webhookID)
+	}
+	log.Info("webhook deleted successfully")
+	return nil
+}
+
+// получение вебхука по ID
+func (r *Repo) WebhookByID(ctx context.Context, webhookID int) (model.Webhook, error) {
+	const op = "storage.postgres.WebhookByID"
+	log := r.log.With(slog.String("op", op), slog.Int("webhookID", webhookID))
+
+	webhook, err := scanWebhook(r.postgres.Pool.QueryRow(ctx,
+		"SELECT "+webhookColumns+" FROM webhooks w WHERE w.webhook_id = $1", webhookID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return webhook, model.NotFound("webhook with ID %d not found", webhookID)
+	}
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return webhook, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// вебхуки проекта
+func (r *Repo) Webhooks(ctx context.Context, projectID int) ([]model.Webhook, error) {
+	const op = "storage.postgres.Webhooks"
+	log := r.log.With(slog.String("op", op), slog.Int("projectID", projectID))
+	log.Info("getting project webhooks")
+
+	rows, err := r.postgres.Pool.Query(ctx,
+		"SELECT "+webhookColumns+" FROM webhooks w WHERE w.project_id = $1 ORDER BY w.webhook_id", projectID)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	var webhooks []model.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return webhooks, nil
+}
+
+// постановка события в очередь доставки всем включённым вебхукам его проекта, подписанным на его тип.
+// Событие, уже поставленное в очередь другим экземпляром приложения, пропускается.
+func (r *Repo) EnqueueWebhookDeliveries(ctx context.Context, event model.TaskEvent, payload []byte) (int, error) {
+	const op = "storage.postgres.EnqueueWebhookDeliveries"
+	log := r.log.With(slog.String("op", op), slog.String("eventID", event.ID))
+
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload)
+              SELECT w.webhook_id, $2, $3, $4
+              FROM webhooks w
+              WHERE w.project_id = $1 AND w.active AND (cardinality(w.events) = 0 OR $3 = ANY(w.events))
+              ON CONFLICT (webhook_id, event_id) WHERE replay_of IS NULL DO NOTHING`
+	tag, err := r.postgres.Pool.Exec(ctx, query, event.ProjectID, event.ID, event.Type, payload)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to enqueue webhook deliveries: %w", pgError(err))
+	}
+	if tag.RowsAffected() > 0 {
+		log.Info("webhook deliveries enqueued", slog.Int64("count", tag.RowsAffected()))
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// взятие в работу не больше limit доставок, срок попытки которых наступил. Попытка считается
+// сразу, а следующая назначается через lease: если экземпляр приложения остановится, не записав
+// результат, доставку возьмёт другой. Доставки отключённых вебхуков ждут их включения.
+func (r *Repo) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookJob, error) {
+	const op = "storage.postgres.ClaimWebhookDeliveries"
+	log := r.log.With(slog.String("op", op))
+
+	query := `WITH due AS (
+                  SELECT d.delivery_id
+                  FROM webhook_deliveries d
+                  JOIN webhooks w ON w.webhook_id = d.webhook_id
+                  WHERE d.status = 'pending' AND d.next_attempt_at <= CURRENT_TIMESTAMP AND w.active
+                  ORDER BY d.next_attempt_at
+                  LIMIT $1
+                  FOR UPDATE OF d SKIP LOCKED
+              ), claimed AS (
+                  UPDATE webhook_deliveries d
+                  SET attempts = d.attempts + 1,
+                      next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $2)
+                  FROM due
+                  WHERE d.delivery_id = due.delivery_id
+                  RETURNING d.*
+              )
+              SELECT ` + deliveryColumns + `, w.url, w.secret
+              FROM claimed d
+              JOIN webhooks w ON w.webhook_id = d.webhook_id
+              ORDER BY d.delivery_id`
+	rows, err := r.postgres.Pool.Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+	var jobs []model.WebhookJob
+	for rows.Next() {
+		var job model.WebhookJob
+		job.Delivery, err = scanDelivery(rows, &job.URL, &job.Secret)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+	return jobs, nil
+}
+
+// запись результата попытки доставки. Успешная попытка обнуляет счётчик неудач вебхука, неудачная
+// увеличивает его; на disableAfter-й неудаче подряд вебхук отключается, тогда возвращается true.
+func (r *Repo) RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, disableAfter int) (bool, error) {
+	const op = "storage.postgres.RecordWebhookAttempt"
+	log := r.log.With(slog.String("op", op), slog.Int("deliveryID", attempt.DeliveryID))
+
+	tx, err := r.postgres.Pool.Begin(ctx)
+	if err != nil {
+		log.Error("failed to begin transaction", sl.Err(err))
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE webhook_deliveries
+              SET status = $2, response_status = NULLIF($3, 0), last_error = $4,
+                  next_attempt_at = CURRENT_TIMESTAMP + make_interval(secs => $5),
+                  delivered_at = CASE WHEN $2 = 'succeeded' THEN CURRENT_TIMESTAMP END
+              WHERE delivery_id = $1`
+	_, err = tx.Exec(ctx, query, attempt.DeliveryID, attempt.Status, attempt.ResponseStatus, attempt.Error,
+		attempt.RetryIn.Seconds())
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return false, fmt.Errorf("failed to record webhook attempt: %w", pgError(err))
+	}
+
+	var disabled bool
+	if attempt.Status == model.DeliverySucceeded {
+		_, err = tx.Exec(ctx, "UPDATE webhooks SET failures = 0 WHERE webhook_id = $1 AND failures > 0", attempt.WebhookID)
+	} else {
+		query = `UPDATE webhooks w
+                 SET failures = w.failures + 1,
+                     active = w.active AND w.failures + 1 < $2,
+                     disabled_at = CASE WHEN w.active AND w.failures + 1 >= $2 THEN CURRENT_TIMESTAMP ELSE w.disabled_at END
+                 FROM (SELECT active FROM webhooks WHERE webhook_id = $1 FOR UPDATE) old
+                 WHERE w.webhook_id = $1
+                 RETURNING old.active AND NOT w.active`
+		err = tx.QueryRow(ctx, query, attempt.WebhookID, disableAfter).Scan(&disabled)
+		if errors.Is(err, pgx.ErrNoRows) {
+			// вебхук удалили во время доставки
+			err = nil
+		}
+	}
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return false, fmt.Errorf("failed to update webhook failures: %w", pgError(err))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		log.Error("failed to commit transaction", sl.Err(err))
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return disabled, nil
+}
+
+// журнал доставок вебхука, новые первыми
+func (r *Repo) WebhookDeliveries(ctx context.Context, webhookID int, page model.PageRequest) (model.Page[model.WebhookDelivery], error) {
+	const op = "storage.postgres.WebhookDeliveries"
+	log := r.log.With(slog.String("op", op), slog.Int("webhookID", webhookID))
+	log.Info("getting webhook deliveries")
+
+	var result model.Page[model.WebhookDelivery]
+	if page.WithTotal {
+		var total int
+		err := r.postgres.Pool.QueryRow(ctx, "SELECT count(*) FROM webhook_deliveries WHERE webhook_id = $1", webhookID).Scan(&total)
+		if err != nil {
+			log.Error("failed to execute query", sl.Err(err))
+			return result, fmt.Errorf("failed to count deliveries: %w", err)
+		}
+		result.Total = &total
+	}
+
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries d WHERE d.webhook_id = $1"
+	args := []any{webhookID}
+	if page.After != nil {
+		args = append(args, page.After.ID)
+		query += fmt.Sprintf(" AND d.delivery_id < $%d", len(args))
+	}
+	query += " ORDER BY d.delivery_id DESC"
+	if page.Limit > 0 {
+		args = append(args, page.Limit+1)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+
+	rows, err := r.postgres.Pool.Query(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return result, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			log.Error("failed to scan row", sl.Err(err))
+			return result, fmt.Errorf("failed to scan row: %w", err)
+		}
+		result.Items = append(result.Items, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		log.Error("row iteration error", sl.Err(err))
+		return result, fmt.Errorf("row iteration error: %w", err)
+	}
+	if page.Limit > 0 && len(result.Items) > page.Limit {
+		result.Items = result.Items[:page.Limit]
+		result.Next = &model.Cursor{ID: result.Items[len(result.Items)-1].ID}
+	}
+	return result, nil
+}
+
+// повтор доставки: новая запись журнала с тем же событием, первая попытка - сразу
+func (r *Repo) ReplayWebhookDelivery(ctx context.Context, webhookID int, deliveryID int) (int, error) {
+	const op = "storage.postgres.ReplayWebhookDelivery"
+	log := r.log.With(slog.String("op", op), slog.Int("webhookID", webhookID), slog.Int("deliveryID", deliveryID))
+	log.Info("replaying webhook delivery")
+
+	query := `INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, replay_of)
+              SELECT webhook_id, event_id, event_type, payload, delivery_id
+              FROM webhook_deliveries
+              WHERE delivery_id = $1 AND webhook_id = $2
+              RETURNING delivery_id`
+	var replayID int
+	err := r.postgres.Pool.QueryRow(ctx, query, deliveryID, webhookID).Scan(&replayID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, model.NotFound("delivery with ID %d not found", deliveryID)
+	}
+	if err != nil {
+		log.Error("failed to execute query", sl.Err(err))
+		return 0, fmt.Errorf("failed to replay delivery: %w", pgError(err))
+	}
+	log.Info("webhook delivery replayed", slog.Int("replayID", replayID))
+	return replayID, nil
+}
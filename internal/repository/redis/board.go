@@ -0,0 +1,154 @@
+package repoCache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+	"Tasks/internal/storage/redis"
+)
+
+// boardSignals канал Pub/Sub сигналов всех досок
+const boardSignals = "board:signals"
+
+// presenceEntry запись присутствия: у полей хэша нет своего срока жизни, поэтому он хранится в записи
+type presenceEntry struct {
+	Presence  model.Presence
+	ExpiresAt time.Time
+}
+
+func NewBoard(storage *redis.Storage, log *slog.Logger) interfaces.BoardRepository {
+	return &Repo{redis: storage, log: log}
+}
+
+func presenceKey(projectID int) string {
+	return fmt.Sprintf("board:%d:presence", projectID)
+}
+
+func (r *Repo) SetPresence(ctx context.Context, projectID int, connID string, presence model.Presence, ttl time.Duration) error {
+	const op = "repository.redis.SetPresence"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("setting presence", slog.Int("project_id", projectID), slog.String("conn_id", connID))
+
+	value, err := json.Marshal(presenceEntry{Presence: presence, ExpiresAt: time.Now().Add(ttl)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal presence: %w", err)
+	}
+	key := presenceKey(projectID)
+	_, err = r.redis.Client.TxPipelined(ctx, func(pipe redis2.Pipeliner) error {
+		pipe.HSet(ctx, key, connID, value)
+		// доска, на которой никого не осталось, удаляется целиком
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set presence: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) RemovePresence(ctx context.Context, projectID int, connID string) error {
+	const op = "repository.redis.RemovePresence"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("removing presence", slog.Int("project_id", projectID), slog.String("conn_id", connID))
+
+	if err := r.redis.Client.HDel(ctx, presenceKey(projectID), connID).Err(); err != nil {
+		return fmt.Errorf("failed to remove presence: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) Presence(ctx context.Context, projectID int) ([]model.Presence, error) {
+	const op = "repository.redis.Presence"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("getting presence", slog.Int("project_id", projectID))
+
+	key := presenceKey(projectID)
+	fields, err := r.redis.Client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+	now := time.Now()
+	var (
+		presence []model.Presence
+		expired  []string
+	)
+	for connID, value := range fields {
+		var entry presenceEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil || entry.ExpiresAt.Before(now) {
+			// соединения экземпляра, остановленного без закрытия, не продлевают записи
+			expired = append(expired, connID)
+			continue
+		}
+		presence = append(presence, entry.Presence)
+	}
+	if len(expired) > 0 {
+		if err := r.redis.Client.HDel(ctx, key, expired...).Err(); err != nil {
+			log.Warn("failed to remove expired presence", sl.Err(err))
+		}
+	}
+	return presence, nil
+}
+
+func (r *Repo) PublishBoardSignal(ctx context.Context, signal model.BoardSignal) error {
+	const op = "repository.redis.PublishBoardSignal"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("publishing board signal", slog.String("type", signal.Type), slog.Int("project_id", signal.ProjectID))
+
+	value, err := json.Marshal(signal)
+	if err != nil {
+		return fmt.Errorf("failed to marshal board signal: %w", err)
+	}
+	if err := r.redis.Client.Publish(ctx, boardSignals, value).Err(); err != nil {
+		return fmt.Errorf("failed to publish board signal: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) BoardSignals(ctx context.Context) (<-chan model.BoardSignal, error) {
+	const op = "repository.redis.BoardSignals"
+	log := r.log.With(slog.String("op", op))
+
+	pubsub := r.redis.Client.Subscribe(ctx, boardSignals)
+	// Receive дожидается подтверждения подписки, иначе ошибка подключения проявилась бы только в канале
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, fmt.Errorf("failed to subscribe to board signals: %w", err)
+	}
+	log.Info("subscribed to board signals")
+
+	signals := make(chan model.BoardSignal)
+	go func() {
+		defer close(signals)
+		defer pubsub.Close()
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				var signal model.BoardSignal
+				if err := json.Unmarshal([]byte(message.Payload), &signal); err != nil {
+					log.Warn("skipping malformed board signal", sl.Err(err))
+					continue
+				}
+				select {
+				case signals <- signal:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return signals, nil
+}
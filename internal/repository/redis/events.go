@@ -0,0 +1,131 @@
+package repoCache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+
+	"Tasks/internal/events"
+	"Tasks/internal/interfaces"
+	"Tasks/internal/model"
+	"Tasks/internal/storage/redis"
+)
+
+// eventsStream ключ журнала событий задач (Redis Stream), ID записей журнала - ID событий
+const eventsStream = "events:tasks"
+
+// NewEvents журнал событий, в котором хранится примерно maxEvents последних событий
+func NewEvents(storage *redis.Storage, log *slog.Logger, maxEvents int64) interfaces.EventStream {
+	return &Repo{redis: storage, log: log, maxEvents: maxEvents}
+}
+
+func (r *Repo) PublishEvent(ctx context.Context, event model.TaskEvent) error {
+	const op = "repository.redis.PublishEvent"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("publishing task event", slog.String("type", event.Type), slog.Int("task_id", event.TaskID))
+
+	// ID события - ID записи, в самой записи он не хранится
+	event.ID = ""
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	err = r.redis.Client.XAdd(ctx, &redis2.XAddArgs{
+		Stream: eventsStream,
+		MaxLen: r.maxEvents,
+		Approx: true,
+		Values: map[string]any{"event": value},
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) EventsAfter(ctx context.Context, lastID string, count int64) ([]model.TaskEvent, bool, error) {
+	const op = "repository.redis.EventsAfter"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("reading events after", slog.String("last_id", lastID))
+
+	// первая запись журнала не позже lastID - всё, что было после lastID, ещё в журнале
+	first, err := r.redis.Client.XRangeN(ctx, eventsStream, "-", "+", 1).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read first event: %w", err)
+	}
+	complete := len(first) == 0 || !events.After(first[0].ID, lastID)
+
+	messages, err := r.redis.Client.XRangeN(ctx, eventsStream, "("+lastID, "+", count+1).Result()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read events: %w", err)
+	}
+	if int64(len(messages)) > count {
+		messages = messages[:count]
+		complete = false
+	}
+	result, err := decodeEvents(messages)
+	if err != nil {
+		return nil, false, err
+	}
+	return result, complete, nil
+}
+
+func (r *Repo) LastEventID(ctx context.Context) (string, error) {
+	const op = "repository.redis.LastEventID"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("reading last event id")
+
+	messages, err := r.redis.Client.XRevRangeN(ctx, eventsStream, "+", "-", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read last event: %w", err)
+	}
+	if len(messages) == 0 {
+		return "0-0", nil
+	}
+	return messages[0].ID, nil
+}
+
+func (r *Repo) ReadEvents(ctx context.Context, lastID string, block time.Duration) ([]model.TaskEvent, error) {
+	const op = "repository.redis.ReadEvents"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("waiting for events", slog.String("last_id", lastID))
+
+	streams, err := r.redis.Client.XRead(ctx, &redis2.XReadArgs{
+		Streams: []string{eventsStream, lastID},
+		Block:   block,
+	}).Result()
+	// истечение block - не ошибка, новых событий просто нет
+	if errors.Is(err, redis2.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read events: %w", err)
+	}
+	var result []model.TaskEvent
+	for _, stream := range streams {
+		decoded, err := decodeEvents(stream.Messages)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, decoded...)
+	}
+	return result, nil
+}
+
+func decodeEvents(messages []redis2.XMessage) ([]model.TaskEvent, error) {
+	result := make([]model.TaskEvent, 0, len(messages))
+	for _, message := range messages {
+		value, _ := message.Values["event"].(string)
+		var event model.TaskEvent
+		if err := json.Unmarshal([]byte(value), &event); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %s: %w", message.ID, err)
+		}
+		event.ID = message.ID
+		result = append(result, event)
+	}
+	return result, nil
+}
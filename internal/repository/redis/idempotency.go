@@ -0,0 +1,85 @@
+package repoCache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	redis2 "github.com/redis/go-redis/v9"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/model"
+	"Tasks/internal/storage/redis"
+)
+
+func NewIdempotency(storage *redis.Storage, log *slog.Logger) interfaces.IdempotencyRepository {
+	return &Repo{redis: storage, log: log}
+}
+
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
+
+func (r *Repo) ReserveIdempotencyKey(ctx context.Context, key string, fingerprint string, ttl time.Duration) (bool, error) {
+	const op = "repository.redis.ReserveIdempotencyKey"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("reserving idempotency key")
+
+	value, err := json.Marshal(model.IdempotentResponse{Fingerprint: fingerprint})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+	ok, err := r.redis.Client.SetNX(ctx, idempotencyKey(key), value, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *Repo) IdempotentResponse(ctx context.Context, key string) (model.IdempotentResponse, bool, error) {
+	const op = "repository.redis.IdempotentResponse"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("getting idempotent response")
+
+	var response model.IdempotentResponse
+	value, err := r.redis.Client.Get(ctx, idempotencyKey(key)).Bytes()
+	if errors.Is(err, redis2.Nil) {
+		return response, false, nil
+	}
+	if err != nil {
+		return response, false, fmt.Errorf("failed to get idempotent response: %w", err)
+	}
+	if err := json.Unmarshal(value, &response); err != nil {
+		return response, false, fmt.Errorf("failed to unmarshal idempotent response: %w", err)
+	}
+	return response, true, nil
+}
+
+func (r *Repo) SaveIdempotentResponse(ctx context.Context, key string, response model.IdempotentResponse, ttl time.Duration) error {
+	const op = "repository.redis.SaveIdempotentResponse"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("saving idempotent response")
+
+	value, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotent response: %w", err)
+	}
+	if err := r.redis.Client.Set(ctx, idempotencyKey(key), value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *Repo) ReleaseIdempotencyKey(ctx context.Context, key string) error {
+	const op = "repository.redis.ReleaseIdempotencyKey"
+	log := r.log.With(slog.String("op", op))
+	log.Debug("releasing idempotency key")
+
+	if err := r.redis.Client.Del(ctx, idempotencyKey(key)).Err(); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
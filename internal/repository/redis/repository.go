@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	redis2 "github.com/redis/go-redis/v9"
@@ -13,9 +15,14 @@ import (
 	"Tasks/internal/storage/redis"
 )
 
+// labelSeparator разделитель меток в кэше, в самих метках он запрещён
+const labelSeparator = ","
+
 type Repo struct {
 	redis *redis.Storage
 	log   *slog.Logger
+	// maxEvents примерная длина журнала событий
+	maxEvents int64
 }
 
 func NewCache(storage *redis.Storage, log *slog.Logger) interfaces.CacheRepository {
@@ -32,9 +39,13 @@ func (r *Repo) InsertingCache(ctx context.Context, task model.Task) error {
 		rdb.HSet(ctx, key, "NameTask", task.NameTask)
 		rdb.HSet(ctx, key, "Description", task.Description)
 		rdb.HSet(ctx, key, "Status", task.Status)
+		rdb.HSet(ctx, key, "Priority", task.Priority)
+		rdb.HSet(ctx, key, "ProjectID", task.ProjectID)
 		rdb.HSet(ctx, key, "Deadline", task.Deadline.Format(time.RFC3339))
 		rdb.HSet(ctx, key, "CreatedAt", task.CreatedAt.Format(time.RFC3339))
 		rdb.HSet(ctx, key, "UpdatedAt", task.UpdatedAt.Format(time.RFC3339))
+		rdb.HSet(ctx, key, "Version", task.Version)
+		rdb.HSet(ctx, key, "Labels", strings.Join(task.Labels, labelSeparator))
 		return nil
 	})
 	if err != nil {
@@ -54,6 +65,10 @@ func (r *Repo) GetTaskFromCache(ctx context.Context, taskID int) (model.Task, er
 	if err != nil {
 		return model.Task{}, fmt.Errorf("failed to get task from cache: %w", err)
 	}
+	// HGetAll не возвращает redis.Nil для отсутствующего ключа
+	if len(fields) == 0 {
+		return model.Task{}, redis2.Nil
+	}
 
 	deadline, err := time.Parse(time.RFC3339, fields["Deadline"])
 	if err != nil {
@@ -68,33 +83,39 @@ func (r *Repo) GetTaskFromCache(ctx context.Context, taskID int) (model.Task, er
 		return model.Task{}, fmt.Errorf("failed to parse UpdatedAt: %w", err)
 	}
 
+	projectID, err := strconv.Atoi(fields["ProjectID"])
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to parse ProjectID: %w", err)
+	}
+	// записи, сохранённые до появления версий, не содержат Version и перечитываются из базы
+	version, err := strconv.Atoi(fields["Version"])
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to parse Version: %w", err)
+	}
+
+	// записи без Labels сохранены до появления меток, тогда меток у задач ещё не было
+	var labels []string
+	if fields["Labels"] != "" {
+		labels = strings.Split(fields["Labels"], labelSeparator)
+	}
+
 	task := model.Task{
 		ID:          taskID,
 		NameTask:    fields["NameTask"],
 		Description: fields["Description"],
 		Status:      fields["Status"],
+		Priority:    fields["Priority"],
+		ProjectID:   projectID,
 		Deadline:    deadline,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+		Version:     version,
+		Labels:      labels,
 	}
 
 	return task, nil
 }
 
-func (r *Repo) UpdateTaskStatusInCache(ctx context.Context, taskID int, status string) error {
-	const op = "repository.redis.UpdateTaskStatusInCache"
-	log := r.log.With(slog.String("op", op))
-	log.Info("updating task status in cache")
-
-	key := fmt.Sprintf("task:%d", taskID)
-
-	_, err := r.redis.Client.HSet(ctx, key, "Status", status).Result()
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func (r *Repo) DeleteTaskFromCache(ctx context.Context, taskID int) error {
 	const op = "repository.redis.DeleteTaskFromCache"
 	log := r.log.With(slog.String("op", op))
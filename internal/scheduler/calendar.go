@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/calendar"
+	"Tasks/internal/lib/logger/sl"
+)
+
+// calendarCache кэширует рабочие календари в пределах одного запуска задачи.
+// При ошибке загрузки используется круглосуточный календарь (nil).
+type calendarCache struct {
+	log   *slog.Logger
+	repo  interfaces.StorageRepository
+	items map[[2]int]*calendar.Calendar
+}
+
+func newCalendarCache(log *slog.Logger, repo interfaces.StorageRepository) *calendarCache {
+	return &calendarCache{log: log, repo: repo, items: make(map[[2]int]*calendar.Calendar)}
+}
+
+func (c *calendarCache) get(ctx context.Context, projectID int, userID int) *calendar.Calendar {
+	key := [2]int{projectID, userID}
+	if cal, ok := c.items[key]; ok {
+		return cal
+	}
+
+	var cal *calendar.Calendar
+	m, ok, err := c.repo.CalendarFor(ctx, projectID, userID)
+	if err != nil {
+		c.log.Error("failed to load calendar", slog.Int("project_id", projectID), slog.Int("user_id", userID), sl.Err(err))
+	}
+	if ok {
+		if cal, err = calendar.FromModel(m); err != nil {
+			c.log.Error("invalid calendar", slog.Int("calendar_id", m.ID), sl.Err(err))
+		}
+	}
+	c.items[key] = cal
+	return cal
+}
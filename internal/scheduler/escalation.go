@@ -0,0 +1,123 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// Escalation применяет правила эскалации проектов к просроченным задачам.
+// Просрочка считается по рабочему календарю проекта, без выходных и праздников.
+// Каждая эскалация фиксируется в истории задачи и выполняется не более одного раза
+// для пары правило-задача (при изменении дедлайна - заново). Уведомление руководителю
+// сохраняется вместе с эскалацией и отправляется, пока Kafka его не примет.
+type Escalation struct {
+	log       *slog.Logger
+	repo      interfaces.StorageRepository
+	escalator Escalator
+	producer  interfaces.Broker
+}
+
+// Escalator выполняет эскалацию, обычно Service: назначение руководителя должно сбросить кэш задачи
+// и попасть в поток событий, как назначение через API
+type Escalator interface {
+	EscalateTask(ctx context.Context, rule model.EscalationRule, task model.Task, details string) (bool, error)
+}
+
+func NewEscalation(log *slog.Logger, repo interfaces.StorageRepository, escalator Escalator, producer interfaces.Broker) *Escalation {
+	return &Escalation{
+		log:       log.With(slog.String("job", "escalation")),
+		repo:      repo,
+		escalator: escalator,
+		producer:  producer,
+	}
+}
+
+func (e *Escalation) Name() string {
+	return "escalation"
+}
+
+func (e *Escalation) Tick(ctx context.Context, now time.Time) error {
+	const op = "scheduler.Escalation.Tick"
+	log := e.log.With(slog.String("op", op))
+
+	rules, err := e.repo.EscalationRules(ctx, 0)
+	if err != nil {
+		return err
+	}
+
+	calendars := newCalendarCache(e.log, e.repo)
+	for _, rule := range rules {
+		// по часам задача просрочена не меньше, чем по рабочему календарю,
+		// поэтому запрос возвращает кандидатов, а календарь проверяется ниже
+		tasks, err := e.repo.TasksToEscalate(ctx, rule, now.Add(-rule.OverdueBy))
+		if err != nil {
+			log.Error("failed to retrieve tasks to escalate", slog.Int("rule_id", rule.ID), sl.Err(err))
+			continue
+		}
+		for _, task := range tasks {
+			overdue := calendars.get(ctx, rule.ProjectID, 0).BusinessDuration(task.Deadline, now)
+			if overdue < rule.OverdueBy {
+				continue
+			}
+			if err := e.escalate(ctx, rule, task); err != nil {
+				log.Error("failed to escalate task", slog.Int("rule_id", rule.ID), slog.Int("task_id", task.ID), sl.Err(err))
+			}
+		}
+	}
+	return e.notify(ctx, now)
+}
+
+func (e *Escalation) escalate(ctx context.Context, rule model.EscalationRule, task model.Task) error {
+	details := fmt.Sprintf("rule %d: %s task overdue by more than %s", rule.ID, rule.Priority, rule.OverdueBy)
+	if rule.AssignManager && rule.ManagerID != 0 {
+		details += fmt.Sprintf(", project manager %d assigned", rule.ManagerID)
+	}
+
+	escalated, err := e.escalator.EscalateTask(ctx, rule, task, details)
+	if err != nil || !escalated {
+		return err
+	}
+	e.log.Info("task escalated", slog.Int("rule_id", rule.ID), slog.Int("task_id", task.ID))
+	return nil
+}
+
+// notify отправляет сохранённые уведомления об эскалациях, в том числе не принятые Kafka на прошлых запусках
+func (e *Escalation) notify(ctx context.Context, now time.Time) error {
+	const op = "scheduler.Escalation.notify"
+	log := e.log.With(slog.String("op", op))
+
+	notices, err := e.repo.ClaimEscalationNotices(ctx)
+	if err != nil {
+		return err
+	}
+	for _, notice := range notices {
+		msg := i18n.Notification(model.NotificationMessage{
+			Event:     model.EventTaskEscalated,
+			Timestamp: now.UTC(),
+			TaskID:    notice.TaskID,
+			UserID:    notice.UserID,
+			Deadline:  notice.Deadline,
+		}, userLanguage(ctx, e.log, e.repo, notice.UserID))
+
+		msgJSON, err := json.Marshal(msg)
+		if err == nil {
+			err = e.producer.Produce(msgJSON, "notification")
+		}
+		if err != nil {
+			log.Error("failed to send escalation notice", slog.Int("rule_id", notice.RuleID), slog.Int("task_id", notice.TaskID), sl.Err(err))
+			// возвращаем уведомление в очередь, чтобы оно ушло на следующем запуске
+			if relErr := e.repo.ReleaseEscalationNotice(ctx, notice); relErr != nil {
+				log.Error("failed to release escalation notice", sl.Err(relErr))
+			}
+		}
+	}
+	return nil
+}
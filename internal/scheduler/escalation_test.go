@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	"Tasks/internal/service"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestEscalation_Tick(t *testing.T) {
+	now := time.Date(2025, 3, 30, 12, 0, 0, 0, time.UTC)
+	rule := model.EscalationRule{
+		ID:            1,
+		ProjectID:     1,
+		ManagerID:     2,
+		Priority:      model.PriorityCritical,
+		OverdueBy:     4 * time.Hour,
+		AssignManager: true,
+		NotifyManager: true,
+		Enabled:       true,
+	}
+	task := model.Task{ID: 10, Priority: model.PriorityCritical, ProjectID: 1, Deadline: now.Add(-5 * time.Hour)}
+
+	notice := model.EscalationNotice{RuleID: rule.ID, TaskID: task.ID, UserID: rule.ManagerID, Deadline: task.Deadline}
+
+	tests := []struct {
+		name    string
+		rule    model.EscalationRule
+		already bool
+		notify  bool
+		// produceErr ошибка Kafka, после неё уведомление возвращается в очередь
+		produceErr error
+	}{
+		{name: "escalate and notify the manager", rule: rule, notify: true},
+		{name: "kafka fails", rule: rule, notify: true, produceErr: errors.New("message timed out")},
+		{name: "already escalated", rule: rule, already: true},
+		{name: "escalate without notification", rule: func() model.EscalationRule {
+			r := rule
+			r.NotifyManager = false
+			return r
+		}()},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("EscalationRules", mock.Anything, 0).Return([]model.EscalationRule{tt.rule}, nil)
+			storageMock.On("TasksToEscalate", mock.Anything, tt.rule, now.Add(-tt.rule.OverdueBy)).Return([]model.Task{task}, nil)
+			storageMock.On("CalendarFor", mock.Anything, tt.rule.ProjectID, 0).Return(model.Calendar{}, false, nil)
+			storageMock.On("EscalateTask", mock.Anything, tt.rule, task, mock.Anything).Return(!tt.already, nil)
+
+			// назначенный руководитель сбрасывает кэш задачи и попадает в поток событий
+			cacheMock := mockery.NewCacheRepository(t)
+			eventsMock := mockery.NewEventStream(t)
+			if !tt.already {
+				cacheMock.On("DeleteTaskFromCache", mock.Anything, task.ID).Return(nil).Once()
+				cacheMock.On("GetTaskFromCache", mock.Anything, task.ID).Return(task, nil).Once()
+				storageMock.On("UserByID", mock.Anything, task.ID).Return([]int{5, tt.rule.ManagerID}, nil).Once()
+				eventsMock.On("PublishEvent", mock.Anything, mock.MatchedBy(func(event model.TaskEvent) bool {
+					return event.Type == model.TaskEventAssigned && event.TaskID == task.ID &&
+						event.UserID == tt.rule.ManagerID && event.ProjectID == tt.rule.ProjectID
+				})).Return(nil).Once()
+			}
+
+			brokerMock := mockery.NewBroker(t)
+			if tt.notify {
+				storageMock.On("ClaimEscalationNotices", mock.Anything).Return([]model.EscalationNotice{notice}, nil).Once()
+				storageMock.On("UserLanguage", mock.Anything, tt.rule.ManagerID).Return(i18n.Russian, nil)
+				brokerMock.On("Produce", mock.MatchedBy(func(payload []byte) bool {
+					var msg model.NotificationMessage
+					return json.Unmarshal(payload, &msg) == nil &&
+						msg.Language == i18n.Russian && msg.Text == "Задача #10 просрочена и передана вам"
+				}), "notification").Return(tt.produceErr).Once()
+			} else {
+				storageMock.On("ClaimEscalationNotices", mock.Anything).Return(nil, nil).Once()
+			}
+			if tt.produceErr != nil {
+				storageMock.On("ReleaseEscalationNotice", mock.Anything, notice).Return(nil).Once()
+			}
+
+			svc := service.NewService(slogdiscard.NewDiscardLogger(), storageMock, cacheMock, brokerMock, eventsMock)
+			e := NewEscalation(slogdiscard.NewDiscardLogger(), storageMock, svc, brokerMock)
+			if err := e.Tick(context.Background(), now); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEscalation_Tick_SkipsNonWorkingDays(t *testing.T) {
+	// суббота 05:00, дедлайн в пятницу 23:00: по часам просрочка 6 часов, по календарю - 1 час
+	now := time.Date(2025, 1, 11, 5, 0, 0, 0, time.UTC)
+	rule := model.EscalationRule{ID: 1, ProjectID: 1, ManagerID: 2, Priority: model.PriorityCritical,
+		OverdueBy: 4 * time.Hour, AssignManager: true, Enabled: true}
+	task := model.Task{ID: 10, Priority: model.PriorityCritical, ProjectID: 1, Deadline: time.Date(2025, 1, 10, 23, 0, 0, 0, time.UTC)}
+	cal := model.Calendar{ID: 1, Timezone: "UTC", WorkStart: "09:00", WorkEnd: "18:00", Weekends: []int{0, 6}}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("EscalationRules", mock.Anything, 0).Return([]model.EscalationRule{rule}, nil)
+	storageMock.On("TasksToEscalate", mock.Anything, rule, now.Add(-rule.OverdueBy)).Return([]model.Task{task}, nil)
+	storageMock.On("CalendarFor", mock.Anything, rule.ProjectID, 0).Return(cal, true, nil)
+	storageMock.On("ClaimEscalationNotices", mock.Anything).Return(nil, nil)
+
+	svc := service.NewService(slogdiscard.NewDiscardLogger(), storageMock, mockery.NewCacheRepository(t), nil, nil)
+	e := NewEscalation(slogdiscard.NewDiscardLogger(), storageMock, svc, mockery.NewBroker(t))
+	if err := e.Tick(context.Background(), now); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	storageMock.AssertNotCalled(t, "EscalateTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+)
+
+// userLanguage язык уведомлений из профиля пользователя, при ошибке - язык по умолчанию
+func userLanguage(ctx context.Context, log *slog.Logger, repo interfaces.StorageRepository, userID int) string {
+	language, err := repo.UserLanguage(ctx, userID)
+	if err != nil {
+		log.Warn("failed to get user language, falling back to default", slog.Int("user_id", userID), sl.Err(err))
+		return i18n.Default
+	}
+	return language
+}
@@ -0,0 +1,146 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// calendarSlack запас на нерабочие дни при выборке задач-кандидатов
+const calendarSlack = 14 * 24 * time.Hour
+
+// Reminder рассылает напоминания исполнителям задач, дедлайн которых пересёк один из порогов.
+// Время до дедлайна считается по рабочему календарю проекта или исполнителя, без выходных и праздников.
+// Отправленные напоминания фиксируются в базе, поэтому при перезапуске и
+// при нескольких экземплярах планировщика напоминания не дублируются.
+type Reminder struct {
+	log        *slog.Logger
+	repo       interfaces.StorageRepository
+	producer   interfaces.Broker
+	thresholds []time.Duration
+}
+
+func NewReminder(log *slog.Logger,
+	repo interfaces.StorageRepository,
+	producer interfaces.Broker,
+	thresholds []time.Duration) *Reminder {
+	sorted := make([]time.Duration, 0, len(thresholds))
+	for _, t := range thresholds {
+		if t > 0 {
+			sorted = append(sorted, t)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return &Reminder{
+		log:        log.With(slog.String("job", "deadline_reminder")),
+		repo:       repo,
+		producer:   producer,
+		thresholds: sorted,
+	}
+}
+
+func (r *Reminder) Name() string {
+	return "deadline_reminder"
+}
+
+func (r *Reminder) Tick(ctx context.Context, now time.Time) error {
+	const op = "scheduler.Reminder.Tick"
+	log := r.log.With(slog.String("op", op))
+
+	// по рабочему календарю до дедлайна может оставаться меньше времени, чем по часам,
+	// поэтому задачи выбираются с запасом на выходные и праздники
+	horizon := now
+	if len(r.thresholds) > 0 {
+		horizon = now.Add(r.thresholds[len(r.thresholds)-1] + calendarSlack)
+	}
+	calendars := newCalendarCache(r.log, r.repo)
+
+	tasks, err := r.repo.TasksDueBefore(ctx, horizon)
+	if err != nil {
+		return err
+	}
+
+	sent := 0
+	for _, task := range tasks {
+		users, err := r.repo.UserByID(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		for _, user := range users {
+			// просрочка определяется по часам: до дедлайна в нерабочее время рабочих часов может не остаться,
+			// но задача ещё не просрочена
+			remaining := calendars.get(ctx, task.ProjectID, user).BusinessDuration(now, task.Deadline)
+			threshold, ok := reminderThreshold(remaining, !now.Before(task.Deadline), r.thresholds)
+			if !ok {
+				continue
+			}
+			ok, err := r.remind(ctx, task, user, threshold, now)
+			if err != nil {
+				log.Error("failed to send reminder", slog.Int("task_id", task.ID), slog.Int("user_id", user), sl.Err(err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	if sent > 0 {
+		log.Info("deadline reminders sent", slog.Int("count", sent))
+	}
+	return nil
+}
+
+func (r *Reminder) remind(ctx context.Context, task model.Task, userID int, threshold time.Duration, now time.Time) (bool, error) {
+	claimed, err := r.repo.ClaimReminder(ctx, task.ID, userID, threshold, task.Deadline)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	event := model.EventDeadlineApproaching
+	if threshold == 0 {
+		event = model.EventDeadlineMissed
+	}
+	msg := i18n.Notification(model.NotificationMessage{
+		Event:     event,
+		Timestamp: now.UTC(),
+		TaskID:    task.ID,
+		UserID:    userID,
+		Deadline:  task.Deadline,
+	}, userLanguage(ctx, r.log, r.repo, userID))
+
+	msgJSON, err := json.Marshal(msg)
+	if err == nil {
+		err = r.producer.Produce(msgJSON, "notification")
+	}
+	if err != nil {
+		// снимаем резерв, чтобы напоминание ушло на следующем запуске
+		if relErr := r.repo.ReleaseReminder(ctx, task.ID, userID, threshold, task.Deadline); relErr != nil {
+			r.log.Error("failed to release reminder", sl.Err(relErr))
+		}
+		return false, fmt.Errorf("failed to produce message: %w", err)
+	}
+	return true, nil
+}
+
+// reminderThreshold возвращает наименьший порог, который уже пересечён.
+// Нулевой порог означает, что дедлайн просрочен.
+func reminderThreshold(remaining time.Duration, overdue bool, thresholds []time.Duration) (time.Duration, bool) {
+	if overdue {
+		return 0, true
+	}
+	for _, t := range thresholds {
+		if remaining <= t {
+			return t, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestReminderThreshold(t *testing.T) {
+	thresholds := []time.Duration{time.Hour, 24 * time.Hour, 72 * time.Hour}
+
+	tests := []struct {
+		name      string
+		remaining time.Duration
+		overdue   bool
+		expected  time.Duration
+		ok        bool
+	}{
+		{name: "far away", remaining: 100 * time.Hour, ok: false},
+		{name: "crossed 72h", remaining: 50 * time.Hour, expected: 72 * time.Hour, ok: true},
+		{name: "exactly 24h", remaining: 24 * time.Hour, expected: 24 * time.Hour, ok: true},
+		{name: "crossed 72h and 24h at once", remaining: 2 * time.Hour, expected: 24 * time.Hour, ok: true},
+		{name: "crossed 1h", remaining: 30 * time.Minute, expected: time.Hour, ok: true},
+		// до дедлайна в выходные рабочих часов не осталось, но он ещё не наступил
+		{name: "no working time left", remaining: 0, expected: time.Hour, ok: true},
+		{name: "overdue", remaining: -time.Minute, overdue: true, expected: 0, ok: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := reminderThreshold(tt.remaining, tt.overdue, thresholds)
+			if ok != tt.ok || got != tt.expected {
+				t.Errorf("expected (%v, %v), got (%v, %v)", tt.expected, tt.ok, got, ok)
+			}
+		})
+	}
+}
+
+func TestReminder_Tick(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	thresholds := []time.Duration{time.Hour, 24 * time.Hour}
+
+	tests := []struct {
+		name      string
+		deadline  time.Time
+		threshold time.Duration
+		event     string
+		claimed   bool
+		// produceErr ошибка Kafka, после неё резерв снимается
+		produceErr error
+	}{
+		{name: "deadline approaching", deadline: now.Add(30 * time.Minute), threshold: time.Hour,
+			event: model.EventDeadlineApproaching, claimed: true},
+		{name: "deadline missed", deadline: now.Add(-time.Minute), threshold: 0,
+			event: model.EventDeadlineMissed, claimed: true},
+		// напоминание уже отправлено этим или другим экземпляром планировщика
+		{name: "already sent", deadline: now.Add(30 * time.Minute), threshold: time.Hour},
+		{name: "kafka fails", deadline: now.Add(30 * time.Minute), threshold: time.Hour,
+			event: model.EventDeadlineApproaching, claimed: true, produceErr: errors.New("message timed out")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			task := model.Task{ID: 7, Deadline: tt.deadline}
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("TasksDueBefore", mock.Anything, now.Add(24*time.Hour+calendarSlack)).Return([]model.Task{task}, nil)
+			storageMock.On("UserByID", mock.Anything, 7).Return([]int{5}, nil)
+			storageMock.On("CalendarFor", mock.Anything, 0, 5).Return(model.Calendar{}, false, nil)
+			storageMock.On("ClaimReminder", mock.Anything, 7, 5, tt.threshold, tt.deadline).Return(tt.claimed, nil).Once()
+
+			brokerMock := mockery.NewBroker(t)
+			if tt.claimed {
+				storageMock.On("UserLanguage", mock.Anything, 5).Return(i18n.English, nil)
+				brokerMock.On("Produce", mock.MatchedBy(func(payload []byte) bool {
+					var msg model.NotificationMessage
+					return json.Unmarshal(payload, &msg) == nil &&
+						msg.Event == tt.event && msg.TaskID == 7 && msg.UserID == 5 && msg.Deadline.Equal(tt.deadline)
+				}), "notification").Return(tt.produceErr).Once()
+			}
+			if tt.produceErr != nil {
+				storageMock.On("ReleaseReminder", mock.Anything, 7, 5, tt.threshold, tt.deadline).Return(nil).Once()
+			}
+
+			r := NewReminder(slogdiscard.NewDiscardLogger(), storageMock, brokerMock, thresholds)
+			if err := r.Tick(context.Background(), now); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestReminder_Tick_NotYetDue(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("TasksDueBefore", mock.Anything, mock.Anything).
+		Return([]model.Task{{ID: 7, Deadline: now.Add(48 * time.Hour)}}, nil)
+	storageMock.On("UserByID", mock.Anything, 7).Return([]int{5}, nil)
+	storageMock.On("CalendarFor", mock.Anything, 0, 5).Return(model.Calendar{}, false, nil)
+
+	// ни один порог не пересечён: резерв не берётся и сообщение не отправляется
+	r := NewReminder(slogdiscard.NewDiscardLogger(), storageMock, mockery.NewBroker(t), []time.Duration{time.Hour, 24 * time.Hour})
+	if err := r.Tick(context.Background(), now); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
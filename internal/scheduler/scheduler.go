@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/lib/logger/sl"
+)
+
+// Job периодическая задача планировщика
+type Job interface {
+	Name() string
+	Tick(ctx context.Context, now time.Time) error
+}
+
+type Scheduler struct {
+	log      *slog.Logger
+	interval time.Duration
+	jobs     []Job
+}
+
+func New(log *slog.Logger, interval time.Duration, jobs ...Job) *Scheduler {
+	return &Scheduler{log: log, interval: interval, jobs: jobs}
+}
+
+// Run запускает все задачи сразу и затем с заданным интервалом, пока не будет отменён ctx
+func (s *Scheduler) Run(ctx context.Context) {
+	s.log.Info("starting scheduler", slog.String("interval", s.interval.String()), slog.Int("jobs", len(s.jobs)))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			s.log.Info("scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	// колонки дедлайнов без часового пояса хранят UTC, поэтому и время запуска передаётся в UTC
+	now := time.Now().UTC()
+	for _, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := job.Tick(ctx, now); err != nil {
+			s.log.Error("job failed", slog.String("job", job.Name()), sl.Err(err))
+		}
+	}
+}
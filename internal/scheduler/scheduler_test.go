@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+)
+
+type nowJob struct {
+	now time.Time
+}
+
+func (j *nowJob) Name() string {
+	return "now"
+}
+
+func (j *nowJob) Tick(_ context.Context, now time.Time) error {
+	j.now = now
+	return nil
+}
+
+func TestScheduler_TickUTC(t *testing.T) {
+	job := &nowJob{}
+	s := New(slogdiscard.NewDiscardLogger(), time.Minute, job)
+	s.tick(context.Background())
+	// задачи сравнивают время запуска с колонками в UTC
+	if job.now.Location() != time.UTC {
+		t.Errorf("expected UTC, got %v", job.now.Location())
+	}
+}
@@ -0,0 +1,34 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/interfaces"
+)
+
+// SyncTombstones удаляет надгробия синхронизации старше ttl. Клиент, не синхронизировавшийся дольше ttl,
+// получает все задачи заново: удалённые надгробия уже не скажут ему, какие задачи исчезли.
+type SyncTombstones struct {
+	log  *slog.Logger
+	repo interfaces.StorageRepository
+	ttl  time.Duration
+}
+
+func NewSyncTombstones(log *slog.Logger, repo interfaces.StorageRepository, ttl time.Duration) *SyncTombstones {
+	return &SyncTombstones{
+		log:  log.With(slog.String("job", "sync_tombstones")),
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+func (s *SyncTombstones) Name() string {
+	return "sync_tombstones"
+}
+
+func (s *SyncTombstones) Tick(ctx context.Context, now time.Time) error {
+	_, err := s.repo.PruneSyncTombstones(ctx, now.Add(-s.ttl))
+	return err
+}
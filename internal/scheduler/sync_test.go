@@ -0,0 +1,22 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestSyncTombstones_Tick(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("PruneSyncTombstones", mock.Anything, now.Add(-30*24*time.Hour)).Return(3, nil).Once()
+
+	job := NewSyncTombstones(slogdiscard.NewDiscardLogger(), storageMock, 30*24*time.Hour)
+	require.NoError(t, job.Tick(context.Background(), now))
+}
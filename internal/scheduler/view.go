@@ -0,0 +1,97 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/lib/query"
+	"Tasks/internal/model"
+)
+
+// ViewSubscriptions уведомляет подписчиков представлений о задачах, которые попали в представление
+// с прошлого вычисления. Представление вычисляется для подписчика: me - это он, учитываются только
+// видимые ему задачи. Задача, покинувшая представление, при возвращении снова вызывает уведомление.
+type ViewSubscriptions struct {
+	log      *slog.Logger
+	repo     interfaces.StorageRepository
+	producer interfaces.Broker
+}
+
+func NewViewSubscriptions(log *slog.Logger, repo interfaces.StorageRepository, producer interfaces.Broker) *ViewSubscriptions {
+	return &ViewSubscriptions{
+		log:      log.With(slog.String("job", "view_subscriptions")),
+		repo:     repo,
+		producer: producer,
+	}
+}
+
+func (v *ViewSubscriptions) Name() string {
+	return "view_subscriptions"
+}
+
+func (v *ViewSubscriptions) Tick(ctx context.Context, now time.Time) error {
+	const op = "scheduler.ViewSubscriptions.Tick"
+	log := v.log.With(slog.String("op", op))
+
+	subscriptions, err := v.repo.ViewSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, sub := range subscriptions {
+		if err := v.evaluate(ctx, sub, now); err != nil {
+			log.Error("failed to evaluate view", slog.Int("view_id", sub.View.ID), slog.Int("user_id", sub.UserID), sl.Err(err))
+		}
+	}
+	return nil
+}
+
+func (v *ViewSubscriptions) evaluate(ctx context.Context, sub model.ViewSubscription, now time.Time) error {
+	q, err := query.View(sub.View, query.Env{Now: now, UserID: sub.UserID})
+	if err != nil {
+		return fmt.Errorf("failed to parse view query: %w", err)
+	}
+	tasks, err := v.repo.ListTasks(ctx, model.TaskFilter{VisibleTo: sub.UserID, Query: &q}, model.PageRequest{})
+	if err != nil {
+		return err
+	}
+	taskIDs := make([]int, 0, len(tasks.Items))
+	for _, task := range tasks.Items {
+		taskIDs = append(taskIDs, task.ID)
+	}
+
+	entered, err := v.repo.UpdateViewMatches(ctx, sub.View.ID, sub.UserID, taskIDs)
+	if err != nil || len(entered) == 0 {
+		return err
+	}
+
+	lang := userLanguage(ctx, v.log, v.repo, sub.UserID)
+	for i, taskID := range entered {
+		msg := i18n.Notification(model.NotificationMessage{
+			Event:     model.EventTaskEnteredView,
+			Timestamp: now.UTC(),
+			TaskID:    taskID,
+			UserID:    sub.UserID,
+			ViewID:    sub.View.ID,
+		}, lang)
+		msgJSON, err := json.Marshal(msg)
+		if err == nil {
+			err = v.producer.Produce(msgJSON, "notification")
+		}
+		if err != nil {
+			// забываем задачи без уведомления, чтобы они снова попали в entered на следующем запуске
+			if relErr := v.repo.ReleaseViewMatches(ctx, sub.View.ID, sub.UserID, entered[i:]); relErr != nil {
+				v.log.Error("failed to release view matches", sl.Err(relErr))
+			}
+			return fmt.Errorf("failed to produce message: %w", err)
+		}
+	}
+	v.log.Info("view subscribers notified", slog.Int("view_id", sub.View.ID), slog.Int("user_id", sub.UserID),
+		slog.Int("tasks", len(entered)))
+	return nil
+}
@@ -0,0 +1,75 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestViewSubscriptions_Tick(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	sub := model.ViewSubscription{
+		View:   model.View{ID: 3, OwnerID: 5, Query: "assignee = me AND deadline < now()+1d"},
+		UserID: 6,
+	}
+	broken := model.ViewSubscription{View: model.View{ID: 4, OwnerID: 6, Query: "status ="}, UserID: 6}
+
+	tests := []struct {
+		name     string
+		entered  []int
+		produced int
+		released []int
+	}{
+		{name: "notify about tasks that entered the view", entered: []int{11, 12}, produced: 2},
+		{name: "nothing new"},
+		// задачи без уведомления забываются, чтобы уведомить о них на следующем запуске
+		{name: "kafka fails", entered: []int{11, 12}, produced: 1, released: []int{12}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("ViewSubscriptions", mock.Anything).Return([]model.ViewSubscription{broken, sub}, nil)
+			// me и now() вычисляются для подписчика в момент запуска
+			storageMock.On("ListTasks", mock.Anything, mock.MatchedBy(func(f model.TaskFilter) bool {
+				and, ok := f.Query.Where.(model.QueryAnd)
+				return ok && f.VisibleTo == 6 &&
+					and.Left.(model.QueryCond).Values[0] == 6 &&
+					and.Right.(model.QueryCond).Values[0] == now.Add(24*time.Hour)
+			}), model.PageRequest{}).Return(model.Page[model.Task]{Items: []model.Task{{ID: 10}, {ID: 11}, {ID: 12}}}, nil)
+			storageMock.On("UpdateViewMatches", mock.Anything, 3, 6, []int{10, 11, 12}).Return(tt.entered, nil)
+
+			brokerMock := mockery.NewBroker(t)
+			if len(tt.entered) > 0 {
+				storageMock.On("UserLanguage", mock.Anything, 6).Return(i18n.English, nil)
+				brokerMock.On("Produce", mock.MatchedBy(func(payload []byte) bool {
+					var msg model.NotificationMessage
+					return json.Unmarshal(payload, &msg) == nil &&
+						msg.Event == model.EventTaskEnteredView && msg.ViewID == 3 && msg.UserID == 6 &&
+						(msg.TaskID == 11 || msg.TaskID == 12) &&
+						msg.Text == fmt.Sprintf("Task #%d now matches a view you follow", msg.TaskID)
+				}), "notification").Return(nil).Times(tt.produced)
+			}
+			if tt.released != nil {
+				brokerMock.On("Produce", mock.Anything, "notification").Return(errors.New("message timed out")).Once()
+				storageMock.On("ReleaseViewMatches", mock.Anything, 3, 6, tt.released).Return(nil).Once()
+			}
+
+			v := NewViewSubscriptions(slogdiscard.NewDiscardLogger(), storageMock, brokerMock)
+			if err := v.Tick(context.Background(), now); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// maxLabelLength наибольшая длина метки в символах
+const maxLabelLength = 50
+
+// BatchTasks применяет одно изменение к группе задач. Ошибка отдельной задачи возвращается в её результате
+// и не отменяет изменения остальных. Каждый затронутый пользователь получает одно уведомление:
+// о единственном изменении - обычное, о нескольких - сводное EventTasksChanged.
+func (s *Service) BatchTasks(ctx context.Context, batch model.TaskBatch) ([]model.TaskBatchResult, error) {
+	const op = "service.BatchTasks"
+	log := s.log.With(slog.String("op", op))
+
+	batch, err := prepareBatch(batch)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.repo.BatchTasks(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[int][]model.NotificationChange)
+	for _, res := range results {
+		if res.Err != nil {
+			continue
+		}
+		switch {
+		case batch.Delete:
+			s.invalidateCache(ctx, log, res.TaskID)
+		case res.Task.ID != 0:
+			s.refreshCache(ctx, log, res.Task)
+		}
+		s.publishBatch(ctx, log, batch, res)
+		for userID, change := range batchChanges(batch, res) {
+			changes[userID] = append(changes[userID], change...)
+		}
+	}
+
+	now := time.Now().UTC()
+	for userID, userChanges := range changes {
+		msg := model.NotificationMessage{Event: model.EventTasksChanged, Timestamp: now, UserID: userID, Changes: userChanges}
+		if len(userChanges) == 1 {
+			change := userChanges[0]
+			msg = model.NotificationMessage{Event: change.Event, Timestamp: now, UserID: userID, TaskID: change.TaskID, ChangeStatus: change.ChangeStatus}
+		}
+		if err := s.notify(ctx, msg); err != nil {
+			log.Error("failed to send notification", slog.Int("user_id", userID), sl.Err(err))
+		}
+	}
+	return results, nil
+}
+
+// batchChanges изменения задачи по пользователям: назначенные и снятые узнают об этом,
+// остальные исполнители - об удалении или смене статуса. Изменение меток уведомлений не вызывает.
+func batchChanges(batch model.TaskBatch, res model.TaskBatchResult) map[int][]model.NotificationChange {
+	changes := make(map[int][]model.NotificationChange)
+	if batch.Delete {
+		for _, userID := range res.Assignees {
+			changes[userID] = append(changes[userID], model.NotificationChange{Event: model.EventDeleteTask, TaskID: res.TaskID})
+		}
+		return changes
+	}
+
+	removed := make(map[int]bool, len(res.Removed))
+	for _, userID := range res.Removed {
+		removed[userID] = true
+		changes[userID] = append(changes[userID], model.NotificationChange{Event: model.EventRemoveUser, TaskID: res.TaskID})
+	}
+	for _, userID := range res.Added {
+		changes[userID] = append(changes[userID], model.NotificationChange{Event: model.EventAddUser, TaskID: res.TaskID})
+	}
+	if batch.Status != "" {
+		for _, users := range [][]int{res.Assignees, res.Added} {
+			for _, userID := range users {
+				if !removed[userID] {
+					changes[userID] = append(changes[userID], model.NotificationChange{
+						Event: model.EventChangeStatus, TaskID: res.TaskID, ChangeStatus: batch.Status,
+					})
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// prepareBatch проверяет операцию и приводит метки к виду, в котором они хранятся: без пробелов по краям,
+// без повторов, по алфавиту
+func prepareBatch(batch model.TaskBatch) (model.TaskBatch, error) {
+	if len(batch.TaskIDs) == 0 {
+		return batch, model.Invalid("task list is empty")
+	}
+	if len(batch.TaskIDs) > model.MaxBatchTasks {
+		return batch, model.Invalid("batch must contain at most %d tasks", model.MaxBatchTasks)
+	}
+	seen := make(map[int]bool, len(batch.TaskIDs))
+	for _, taskID := range batch.TaskIDs {
+		if seen[taskID] {
+			return batch, model.Invalid("task %d is listed more than once", taskID)
+		}
+		seen[taskID] = true
+	}
+
+	changes := batch.Status != "" || len(batch.AddAssignees) > 0 || len(batch.RemoveAssignees) > 0 || batch.Labels != nil
+	switch {
+	case batch.Delete && changes:
+		return batch, model.Invalid("delete cannot be combined with other changes")
+	case !batch.Delete && !changes:
+		return batch, model.Invalid("batch contains no changes")
+	}
+	if batch.Status != "" && !model.ValidStatus(batch.Status) {
+		return batch, model.Invalid("unknown task status %q", batch.Status)
+	}
+
+	if batch.Labels != nil {
+		labels := make([]string, 0, len(batch.Labels))
+		unique := make(map[string]bool, len(batch.Labels))
+		for _, label := range batch.Labels {
+			label = strings.TrimSpace(label)
+			if label == "" || utf8.RuneCountInString(label) > maxLabelLength || strings.Contains(label, ",") {
+				return batch, model.Invalid("invalid label %q", label)
+			}
+			if !unique[label] {
+				unique[label] = true
+				labels = append(labels, label)
+			}
+		}
+		sort.Strings(labels)
+		batch.Labels = labels
+	}
+	return batch, nil
+}
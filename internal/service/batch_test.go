@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_BatchTasks_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		batch model.TaskBatch
+	}{
+		{name: "no tasks", batch: model.TaskBatch{Status: model.TaskStatusDone}},
+		{name: "duplicate task", batch: model.TaskBatch{TaskIDs: []int{1, 1}, Status: model.TaskStatusDone}},
+		{name: "no changes", batch: model.TaskBatch{TaskIDs: []int{1}}},
+		{name: "delete with changes", batch: model.TaskBatch{TaskIDs: []int{1}, Delete: true, Status: model.TaskStatusDone}},
+		{name: "unknown status", batch: model.TaskBatch{TaskIDs: []int{1}, Status: "closed"}},
+		{name: "blank label", batch: model.TaskBatch{TaskIDs: []int{1}, Labels: []string{" "}}},
+		{name: "label with separator", batch: model.TaskBatch{TaskIDs: []int{1}, Labels: []string{"a,b"}}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: mockery.NewStorageRepository(t)}
+			_, err := s.BatchTasks(context.Background(), tt.batch)
+			require.True(t, errors.Is(err, model.ErrValidation), "unexpected error: %v", err)
+		})
+	}
+}
+
+func TestService_BatchTasks(t *testing.T) {
+	batch := model.TaskBatch{TaskIDs: []int{1, 2, 3}, Status: model.TaskStatusDone, AddAssignees: []int{7}, Labels: []string{"ui", " bug", "ui"}}
+	// метки очищены и упорядочены до обращения к базе
+	prepared := batch
+	prepared.Labels = []string{"bug", "ui"}
+
+	task1 := model.Task{ID: 1, Status: model.TaskStatusDone, Version: 2, Labels: prepared.Labels}
+	task2 := model.Task{ID: 2, Status: model.TaskStatusDone, Version: 5, Labels: prepared.Labels}
+	results := []model.TaskBatchResult{
+		{TaskID: 1, Task: task1, Assignees: []int{5}, Added: []int{7}},
+		{TaskID: 2, Task: task2, Assignees: []int{5, 7}},
+		{TaskID: 3, Err: model.NotFound("task with ID %d not found", 3)},
+	}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("BatchTasks", mock.Anything, prepared).Return(results, nil)
+	storageMock.On("UserLanguage", mock.Anything, mock.Anything).Return(i18n.English, nil)
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("InsertingCache", mock.Anything, task1).Return(nil)
+	cacheMock.On("InsertingCache", mock.Anything, task2).Return(nil)
+
+	sent := make(map[int]model.NotificationMessage)
+	brokerMock := mockery.NewBroker(t)
+	brokerMock.On("Produce", mock.Anything, "notification").Run(func(args mock.Arguments) {
+		var msg model.NotificationMessage
+		require.NoError(t, json.Unmarshal(args.Get(0).([]byte), &msg))
+		_, dup := sent[msg.UserID]
+		require.False(t, dup, "user %d notified twice", msg.UserID)
+		sent[msg.UserID] = msg
+	}).Return(nil)
+
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, producer: brokerMock}
+	got, err := s.BatchTasks(context.Background(), batch)
+	require.NoError(t, err)
+	require.Equal(t, results, got)
+
+	// пользователь 5: статус двух задач, пользователь 7: назначение и статус задачи 1, статус задачи 2
+	require.Len(t, sent, 2)
+	require.Equal(t, model.EventTasksChanged, sent[5].Event)
+	require.Len(t, sent[5].Changes, 2)
+	require.Equal(t, "2 of your tasks have been changed", sent[5].Text)
+	require.Equal(t, model.EventTasksChanged, sent[7].Event)
+	require.Equal(t, []model.NotificationChange{
+		{Event: model.EventAddUser, TaskID: 1, Text: "You have been assigned to task #1"},
+		{Event: model.EventChangeStatus, TaskID: 1, ChangeStatus: model.TaskStatusDone, Text: `Task #1 status changed to "done"`},
+		{Event: model.EventChangeStatus, TaskID: 2, ChangeStatus: model.TaskStatusDone, Text: `Task #2 status changed to "done"`},
+	}, sent[7].Changes)
+}
+
+func TestService_BatchTasks_SingleChange(t *testing.T) {
+	batch := model.TaskBatch{TaskIDs: []int{4}, Delete: true}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("BatchTasks", mock.Anything, batch).Return([]model.TaskBatchResult{{TaskID: 4, Assignees: []int{5}}}, nil)
+	storageMock.On("UserLanguage", mock.Anything, 5).Return(i18n.English, nil)
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("DeleteTaskFromCache", mock.Anything, 4).Return(nil)
+	brokerMock := mockery.NewBroker(t)
+	brokerMock.On("Produce", mock.MatchedBy(func(payload []byte) bool {
+		var msg model.NotificationMessage
+		return json.Unmarshal(payload, &msg) == nil &&
+			msg.Event == model.EventDeleteTask && msg.TaskID == 4 && msg.UserID == 5 && msg.Changes == nil
+	}), "notification").Return(nil).Once()
+
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, producer: brokerMock}
+	_, err := s.BatchTasks(context.Background(), batch)
+	require.NoError(t, err)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"Tasks/internal/lib/calendar"
+	"Tasks/internal/model"
+)
+
+func (s *Service) CreateCalendar(ctx context.Context, cal model.Calendar) (int, error) {
+	if cal.Timezone == "" {
+		cal.Timezone = "UTC"
+	}
+	if cal.WorkStart == "" {
+		cal.WorkStart = "09:00"
+	}
+	if cal.WorkEnd == "" {
+		cal.WorkEnd = "18:00"
+	}
+	if cal.Weekends == nil {
+		cal.Weekends = []int{int(time.Saturday), int(time.Sunday)}
+	}
+	if _, err := calendar.FromModel(cal); err != nil {
+		return -1, err
+	}
+	return s.repo.CreateCalendar(ctx, cal)
+}
+
+// ImportHolidays добавляет в календарь праздники из файла iCalendar и возвращает их количество
+func (s *Service) ImportHolidays(ctx context.Context, calendarID int, ical io.Reader) (int, error) {
+	holidays, err := calendar.ParseICal(ical)
+	if errors.Is(err, model.ErrValidation) {
+		return 0, err
+	}
+	if err != nil {
+		// файл приходит в теле запроса, ошибка чтения - ошибка клиента
+		return 0, model.Invalid("failed to read iCalendar file")
+	}
+	if len(holidays) == 0 {
+		return 0, nil
+	}
+	if _, err := s.repo.CalendarByID(ctx, calendarID); err != nil {
+		return 0, err
+	}
+	if err := s.repo.AddCalendarHolidays(ctx, calendarID, holidays); err != nil {
+		return 0, err
+	}
+	return len(holidays), nil
+}
+
+func (s *Service) CalendarByID(ctx context.Context, calendarID int) (model.Calendar, error) {
+	return s.repo.CalendarByID(ctx, calendarID)
+}
+
+func (s *Service) SetProjectCalendar(ctx context.Context, projectID int, calendarID int) error {
+	return s.repo.SetProjectCalendar(ctx, projectID, calendarID)
+}
+
+func (s *Service) SetUserCalendar(ctx context.Context, userID int, calendarID int) error {
+	return s.repo.SetUserCalendar(ctx, userID, calendarID)
+}
+
+// ResolveDeadline вычисляет абсолютный дедлайн из относительного выражения ("+3 business days")
+// по календарю проекта. Без календаря рабочие дни совпадают с календарными.
+// Дедлайн возвращается в UTC: так он хранится в базе.
+func (s *Service) ResolveDeadline(ctx context.Context, projectID int, expr string) (time.Time, error) {
+	rel, err := calendar.ParseRelative(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	cal, err := s.calendarFor(ctx, projectID, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return rel.From(time.Now(), cal).UTC(), nil
+}
+
+func (s *Service) calendarFor(ctx context.Context, projectID int, userID int) (*calendar.Calendar, error) {
+	m, ok, err := s.repo.CalendarFor(ctx, projectID, userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	cal, err := calendar.FromModel(m)
+	if err != nil {
+		return nil, fmt.Errorf("calendar %d is invalid: %w", m.ID, err)
+	}
+	return cal, nil
+}
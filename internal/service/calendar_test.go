@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_ResolveDeadline(t *testing.T) {
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("CalendarFor", mock.Anything, 1, 0).Return(model.Calendar{
+		ID: 1, Timezone: "Europe/Moscow", WorkStart: "09:00", WorkEnd: "18:00",
+	}, true, nil)
+	ct := Service{
+		log:  slogdiscard.NewDiscardLogger(),
+		repo: storageMock,
+	}
+
+	deadline, err := ct.ResolveDeadline(context.Background(), 1, "+3 business days")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// колонка дедлайна без часового пояса, поэтому дедлайн отдаётся в UTC:
+	// конец рабочего дня 18:00 по Москве - 15:00 UTC
+	if deadline.Location() != time.UTC {
+		t.Errorf("expected deadline in UTC, got %v", deadline.Location())
+	}
+	if deadline.Hour() != 15 || deadline.Minute() != 0 {
+		t.Errorf("expected 15:00 UTC, got %v", deadline)
+	}
+}
+
+func TestService_ResolveDeadline_Invalid(t *testing.T) {
+	ct := Service{
+		log:  slogdiscard.NewDiscardLogger(),
+		repo: mockery.NewStorageRepository(t),
+	}
+
+	_, err := ct.ResolveDeadline(context.Background(), 1, "+3 fortnights")
+	// ошибка разбора - доменная ошибка с форматом из каталога переводов
+	var domainErr *model.Error
+	if !errors.As(err, &domainErr) || !errors.Is(err, model.ErrValidation) {
+		t.Fatalf("expected validation error, got %v", err)
+	}
+	want := `неизвестная единица в относительном дедлайне "+3 fortnights"`
+	if got := i18n.T(i18n.Russian, domainErr.Format, domainErr.Args...); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
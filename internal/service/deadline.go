@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"Tasks/internal/lib/deadline"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// ListTasks возвращает страницу задач по фильтру. Окно дедлайнов, если задано,
+// вычисляется в часовом поясе из профиля вызывающего пользователя callerID.
+func (s *Service) ListTasks(ctx context.Context, callerID int, filter model.TaskFilter, window model.DeadlineWindow,
+	page model.PageRequest) (model.Page[model.Task], error) {
+	if err := validatePage(&page); err != nil {
+		return model.Page[model.Task]{}, err
+	}
+	for _, status := range filter.Statuses {
+		if !model.ValidStatus(status) {
+			return model.Page[model.Task]{}, model.Invalid("unknown task status %q", status)
+		}
+	}
+
+	var (
+		r   deadline.Range
+		err error
+	)
+	switch {
+	case window.Name != "":
+		r, err = deadline.Window(window.Name, window.Days, time.Now(), s.userLocation(ctx, callerID))
+	case !window.From.IsZero() || !window.To.IsZero():
+		r, err = deadline.Custom(window.From, window.To)
+	}
+	if err != nil {
+		return model.Page[model.Task]{}, err
+	}
+
+	filter.DeadlineFrom = r.From
+	filter.DeadlineTo = r.To
+	filter.UnfinishedOnly = filter.UnfinishedOnly || r.UnfinishedOnly
+	return s.repo.ListTasks(ctx, filter, page)
+}
+
+// validatePage проверяет поле сортировки и то, что курсор выдан для того же порядка сортировки
+func validatePage(page *model.PageRequest) error {
+	if page.Sort == "" {
+		page.Sort = model.SortDeadline
+	}
+	if !model.ValidSortField(page.Sort) {
+		return model.Invalid("unknown sort field %q", page.Sort)
+	}
+	if page.After != nil && (page.After.Sort != page.Sort || page.After.Desc != page.Desc) {
+		return model.Invalid("cursor does not match the requested sort order")
+	}
+	return nil
+}
+
+func (s *Service) SetUserTimezone(ctx context.Context, userID int, timezone string) error {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return model.Invalid("unknown timezone %q", timezone)
+	}
+	return s.repo.SetUserTimezone(ctx, userID, timezone)
+}
+
+func (s *Service) CreateTeam(ctx context.Context, team model.Team) (int, error) {
+	return s.repo.CreateTeam(ctx, team)
+}
+
+func (s *Service) AddTeamMember(ctx context.Context, teamID int, userID int) error {
+	return s.repo.AddTeamMember(ctx, teamID, userID)
+}
+
+// userLocation часовой пояс пользователя, при любой ошибке - UTC
+func (s *Service) userLocation(ctx context.Context, userID int) *time.Location {
+	if userID == 0 {
+		return time.UTC
+	}
+	log := s.log.With(slog.String("op", "service.userLocation"), slog.Int("user_id", userID))
+
+	timezone, err := s.repo.UserTimezone(ctx, userID)
+	if err != nil {
+		log.Warn("failed to get user timezone, falling back to UTC", sl.Err(err))
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		log.Warn("invalid user timezone, falling back to UTC", sl.Err(err))
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_ListTasks(t *testing.T) {
+	cursor := &model.Cursor{Sort: model.SortDeadline, Value: "2025-01-15T10:00:00", ID: 7}
+
+	tests := []struct {
+		name     string
+		filter   model.TaskFilter
+		page     model.PageRequest
+		wantPage model.PageRequest
+		wantErr  bool
+	}{
+		{
+			name:     "default sort by deadline",
+			page:     model.PageRequest{Limit: 10},
+			wantPage: model.PageRequest{Limit: 10, Sort: model.SortDeadline},
+		},
+		{
+			name:     "next page in the same order",
+			page:     model.PageRequest{Limit: 10, After: cursor},
+			wantPage: model.PageRequest{Limit: 10, Sort: model.SortDeadline, After: cursor},
+		},
+		{
+			name:    "cursor from another sort order",
+			page:    model.PageRequest{Limit: 10, Sort: model.SortPriority, After: cursor},
+			wantErr: true,
+		},
+		{
+			name:    "cursor from another direction",
+			page:    model.PageRequest{Limit: 10, Desc: true, After: cursor},
+			wantErr: true,
+		},
+		{
+			name:    "unknown sort field",
+			page:    model.PageRequest{Sort: "title"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown status",
+			filter:  model.TaskFilter{Statuses: []string{model.TaskStatusDone, "archived"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			if !tt.wantErr {
+				storageMock.On("ListTasks", mock.Anything, tt.filter, tt.wantPage).
+					Return(model.Page[model.Task]{Items: []model.Task{{ID: 1}}}, nil)
+			}
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+			page, err := s.ListTasks(context.Background(), 0, tt.filter, model.DeadlineWindow{}, tt.page)
+			if tt.wantErr {
+				require.True(t, errors.Is(err, model.ErrValidation), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, page.Items, 1)
+		})
+	}
+}
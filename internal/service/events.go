@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"time"
+
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// EventScope что пользователь видит в потоке событий
+func (s *Service) EventScope(ctx context.Context, userID int) (model.EventScope, error) {
+	return s.repo.EventScope(ctx, userID)
+}
+
+// publish публикует событие задачи. Поток событий вспомогательный: ошибка публикации
+// записывается в журнал и не отменяет уже выполненное изменение.
+func (s *Service) publish(ctx context.Context, log *slog.Logger, event model.TaskEvent) {
+	if s.events == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if err := s.events.PublishEvent(ctx, event); err != nil {
+		log.Error("failed to publish task event", slog.String("type", event.Type), slog.Int("task_id", event.TaskID), sl.Err(err))
+	}
+}
+
+// publishTask публикует событие задачи, которое нужно дополнить самой задачей и её исполнителями.
+// userID - назначенный или снятый исполнитель, снятый тоже получает событие.
+func (s *Service) publishTask(ctx context.Context, log *slog.Logger, eventType string, taskID int, userID int) {
+	if s.events == nil {
+		return
+	}
+	task, err := s.TaskByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get task for event", slog.Int("task_id", taskID), sl.Err(err))
+		return
+	}
+	assignees, err := s.repo.UserByID(ctx, taskID)
+	if err != nil {
+		log.Error("failed to get task assignees for event", slog.Int("task_id", taskID), sl.Err(err))
+		return
+	}
+	if userID != 0 && !slices.Contains(assignees, userID) {
+		assignees = append(assignees, userID)
+	}
+	s.publish(ctx, log, model.TaskEvent{
+		Type: eventType, TaskID: taskID, ProjectID: task.ProjectID, UserID: userID, Task: &task, Assignees: assignees,
+	})
+}
+
+// publishBatch события одной задачи групповой операции
+func (s *Service) publishBatch(ctx context.Context, log *slog.Logger, batch model.TaskBatch, res model.TaskBatchResult) {
+	if batch.Delete {
+		s.publish(ctx, log, model.TaskEvent{Type: model.TaskEventDeleted, TaskID: res.TaskID, ProjectID: res.ProjectID, Assignees: res.Assignees})
+		return
+	}
+
+	// видят событие все, кто был исполнителем до или стал после операции
+	assignees := slices.Concat(res.Assignees, res.Added)
+	event := model.TaskEvent{TaskID: res.TaskID, ProjectID: res.ProjectID, Assignees: assignees}
+	if res.Task.ID != 0 {
+		event.Task = &res.Task
+	}
+	for _, userID := range res.Added {
+		event.Type, event.UserID = model.TaskEventAssigned, userID
+		s.publish(ctx, log, event)
+	}
+	for _, userID := range res.Removed {
+		event.Type, event.UserID = model.TaskEventUnassigned, userID
+		s.publish(ctx, log, event)
+	}
+	if event.Task != nil {
+		event.Type, event.UserID = model.TaskEventUpdated, 0
+		if batch.Status != "" {
+			event.Type = model.TaskEventStatusChanged
+		}
+		s.publish(ctx, log, event)
+	}
+}
+
+// BoardScope проверяет доступ к доске проекта: она открыта участникам проекта. Возвращает
+// область видимости пользователя, по которой доска отбирает события задач.
+func (s *Service) BoardScope(ctx context.Context, projectID int, userID int) (model.EventScope, error) {
+	if _, err := s.repo.ProjectByID(ctx, projectID); err != nil {
+		return model.EventScope{}, err
+	}
+	member, err := s.repo.ProjectMember(ctx, projectID, userID)
+	if err != nil {
+		return model.EventScope{}, err
+	}
+	if !member {
+		return model.EventScope{}, model.Forbidden("user with ID %d is not a member of project %d", userID, projectID)
+	}
+	return s.repo.EventScope(ctx, userID)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_DeleteTask_PublishesEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		publishErr error
+	}{
+		{name: "event published"},
+		{name: "failed publish does not fail the request", publishErr: errors.New("redis is down")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("DeleteTask", mock.Anything, 3, 0).Return(4, []int{7}, nil)
+			storageMock.On("UserLanguage", mock.Anything, 7).Return(i18n.English, nil)
+			cacheMock := mockery.NewCacheRepository(t)
+			cacheMock.On("DeleteTaskFromCache", mock.Anything, 3).Return(nil)
+			brokerMock := mockery.NewBroker(t)
+			brokerMock.On("Produce", mock.Anything, "notification").Return(nil)
+			eventsMock := mockery.NewEventStream(t)
+			// удалённой задачи в событии нет, видимость определяют проект и бывшие исполнители
+			eventsMock.On("PublishEvent", mock.Anything, mock.MatchedBy(func(e model.TaskEvent) bool {
+				return e.Type == model.TaskEventDeleted && e.TaskID == 3 && e.ProjectID == 4 &&
+					e.Task == nil && len(e.Assignees) == 1 && e.Assignees[0] == 7 && !e.Timestamp.IsZero()
+			})).Return(tt.publishErr).Once()
+
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, producer: brokerMock, events: eventsMock}
+			require.NoError(t, s.DeleteTask(context.Background(), 3, 0))
+		})
+	}
+}
+
+func TestService_CreateTask_PublishesStoredTask(t *testing.T) {
+	created := model.Task{ID: 3, NameTask: "task", ProjectID: 4, Status: model.TaskStatusTodo,
+		Priority: model.PriorityMedium, Version: 1, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("CreateNewTask", mock.Anything, mock.Anything).Return(created, nil)
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("InsertingCache", mock.Anything, created).Return(nil)
+	eventsMock := mockery.NewEventStream(t)
+	// подписчики получают статус и даты, заданные базой
+	eventsMock.On("PublishEvent", mock.Anything, mock.MatchedBy(func(e model.TaskEvent) bool {
+		return e.Type == model.TaskEventCreated && e.TaskID == 3 && e.ProjectID == 4 &&
+			e.Task != nil && reflect.DeepEqual(*e.Task, created)
+	})).Return(nil).Once()
+
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, events: eventsMock}
+	taskID, err := s.CreateTask(context.Background(), model.Task{NameTask: "task", ProjectID: 4, Deadline: time.Now().Add(time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, 3, taskID)
+}
+
+func TestService_RemoveUserFromTask_PublishesEvent(t *testing.T) {
+	task := model.Task{ID: 3, ProjectID: 4, Version: 2}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("RemoveUserFromTask", mock.Anything, 7, 3).Return(nil)
+	storageMock.On("UserByID", mock.Anything, 3).Return([]int{5}, nil)
+	storageMock.On("UserLanguage", mock.Anything, 7).Return(i18n.English, nil)
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("GetTaskFromCache", mock.Anything, 3).Return(task, nil)
+	brokerMock := mockery.NewBroker(t)
+	brokerMock.On("Produce", mock.Anything, "notification").Return(nil)
+	eventsMock := mockery.NewEventStream(t)
+	// снятый исполнитель тоже должен увидеть событие
+	eventsMock.On("PublishEvent", mock.Anything, mock.MatchedBy(func(e model.TaskEvent) bool {
+		return e.Type == model.TaskEventUnassigned && e.UserID == 7 && e.Task != nil && e.Task.Version == 2 &&
+			len(e.Assignees) == 2 && e.Assignees[1] == 7
+	})).Return(nil).Once()
+
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, producer: brokerMock, events: eventsMock}
+	require.NoError(t, s.RemoveUserFromTask(context.Background(), 7, 3))
+}
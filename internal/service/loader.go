@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+
+	"Tasks/internal/model"
+)
+
+// Пакетная загрузка для GraphQL: один запрос к базе на все ID, собранные за время разбора уровня запроса.
+// Отсутствующие ID пропускаются, вызывающий сам решает, ошибка ли это.
+
+func (s *Service) TasksByIDs(ctx context.Context, taskIDs []int) ([]model.Task, error) {
+	return s.repo.TasksByIDs(ctx, taskIDs)
+}
+
+func (s *Service) UsersByIDs(ctx context.Context, userIDs []int) ([]model.User, error) {
+	return s.repo.UsersByIDs(ctx, userIDs)
+}
+
+func (s *Service) ProjectsByIDs(ctx context.Context, projectIDs []int) ([]model.Project, error) {
+	return s.repo.ProjectsByIDs(ctx, projectIDs)
+}
+
+func (s *Service) AssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]model.User, error) {
+	return s.repo.AssigneesByTaskIDs(ctx, taskIDs)
+}
+
+// TasksByAssignees не больше limit задач каждого пользователя по ближайшему дедлайну
+func (s *Service) TasksByAssignees(ctx context.Context, userIDs []int, limit int) (map[int][]model.Task, error) {
+	if limit <= 0 || limit > model.MaxPageLimit {
+		return nil, model.Invalid("limit must be between 1 and %d", model.MaxPageLimit)
+	}
+	return s.repo.TasksByAssignees(ctx, userIDs, limit)
+}
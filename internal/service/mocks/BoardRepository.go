@@ -0,0 +1,146 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "Tasks/internal/model"
+
+	time "time"
+)
+
+// BoardRepository is an autogenerated mock type for the BoardRepository type
+type BoardRepository struct {
+	mock.Mock
+}
+
+// BoardSignals provides a mock function with given fields: ctx
+func (_m *BoardRepository) BoardSignals(ctx context.Context) (<-chan model.BoardSignal, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BoardSignals")
+	}
+
+	var r0 <-chan model.BoardSignal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (<-chan model.BoardSignal, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) <-chan model.BoardSignal); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan model.BoardSignal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Presence provides a mock function with given fields: ctx, projectID
+func (_m *BoardRepository) Presence(ctx context.Context, projectID int) ([]model.Presence, error) {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Presence")
+	}
+
+	var r0 []model.Presence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Presence, error)); ok {
+		return rf(ctx, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Presence); ok {
+		r0 = rf(ctx, projectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Presence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishBoardSignal provides a mock function with given fields: ctx, signal
+func (_m *BoardRepository) PublishBoardSignal(ctx context.Context, signal model.BoardSignal) error {
+	ret := _m.Called(ctx, signal)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishBoardSignal")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.BoardSignal) error); ok {
+		r0 = rf(ctx, signal)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemovePresence provides a mock function with given fields: ctx, projectID, connID
+func (_m *BoardRepository) RemovePresence(ctx context.Context, projectID int, connID string) error {
+	ret := _m.Called(ctx, projectID, connID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemovePresence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, projectID, connID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetPresence provides a mock function with given fields: ctx, projectID, connID, presence, ttl
+func (_m *BoardRepository) SetPresence(ctx context.Context, projectID int, connID string, presence model.Presence, ttl time.Duration) error {
+	ret := _m.Called(ctx, projectID, connID, presence, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPresence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string, model.Presence, time.Duration) error); ok {
+		r0 = rf(ctx, projectID, connID, presence, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewBoardRepository creates a new instance of BoardRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewBoardRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *BoardRepository {
+	mock := &BoardRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -79,24 +79,6 @@ func (_m *CacheRepository) InsertingCache(ctx context.Context, task model.Task)
 	return r0
 }
 
-// UpdateTaskStatusInCache provides a mock function with given fields: ctx, taskID, status
-func (_m *CacheRepository) UpdateTaskStatusInCache(ctx context.Context, taskID int, status string) error {
-	ret := _m.Called(ctx, taskID, status)
-
-	if len(ret) == 0 {
-		panic("no return value specified for UpdateTaskStatusInCache")
-	}
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
-		r0 = rf(ctx, taskID, status)
-	} else {
-		r0 = ret.Error(0)
-	}
-
-	return r0
-}
-
 // NewCacheRepository creates a new instance of CacheRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCacheRepository(t interface {
@@ -0,0 +1,145 @@
+// Code generated by mockery v2.51.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	model "Tasks/internal/model"
+
+	time "time"
+)
+
+// EventStream is an autogenerated mock type for the EventStream type
+type EventStream struct {
+	mock.Mock
+}
+
+// EventsAfter provides a mock function with given fields: ctx, lastID, count
+func (_m *EventStream) EventsAfter(ctx context.Context, lastID string, count int64) ([]model.TaskEvent, bool, error) {
+	ret := _m.Called(ctx, lastID, count)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EventsAfter")
+	}
+
+	var r0 []model.TaskEvent
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) ([]model.TaskEvent, bool, error)); ok {
+		return rf(ctx, lastID, count)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) []model.TaskEvent); ok {
+		r0 = rf(ctx, lastID, count)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) bool); ok {
+		r1 = rf(ctx, lastID, count)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, int64) error); ok {
+		r2 = rf(ctx, lastID, count)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// LastEventID provides a mock function with given fields: ctx
+func (_m *EventStream) LastEventID(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LastEventID")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PublishEvent provides a mock function with given fields: ctx, event
+func (_m *EventStream) PublishEvent(ctx context.Context, event model.TaskEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublishEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadEvents provides a mock function with given fields: ctx, lastID, block
+func (_m *EventStream) ReadEvents(ctx context.Context, lastID string, block time.Duration) ([]model.TaskEvent, error) {
+	ret := _m.Called(ctx, lastID, block)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadEvents")
+	}
+
+	var r0 []model.TaskEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) ([]model.TaskEvent, error)); ok {
+		return rf(ctx, lastID, block)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) []model.TaskEvent); ok {
+		r0 = rf(ctx, lastID, block)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, lastID, block)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEventStream creates a new instance of EventStream. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventStream(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventStream {
+	mock := &EventStream{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -8,24 +8,1598 @@ import (
 	mock "github.com/stretchr/testify/mock"
 
 	model "Tasks/internal/model"
+
+	time "time"
 )
 
-// StorageRepository is an autogenerated mock type for the StorageRepository type
-type StorageRepository struct {
-	mock.Mock
+// StorageRepository is an autogenerated mock type for the StorageRepository type
+type StorageRepository struct {
+	mock.Mock
+}
+
+// AddCalendarHolidays provides a mock function with given fields: ctx, calendarID, holidays
+func (_m *StorageRepository) AddCalendarHolidays(ctx context.Context, calendarID int, holidays []model.Holiday) error {
+	ret := _m.Called(ctx, calendarID, holidays)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddCalendarHolidays")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, []model.Holiday) error); ok {
+		r0 = rf(ctx, calendarID, holidays)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddNewUserTask provides a mock function with given fields: ctx, userID, taskID
+func (_m *StorageRepository) AddNewUserTask(ctx context.Context, userID int, taskID int) error {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddNewUserTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTaskToSprint provides a mock function with given fields: ctx, sprintID, taskID
+func (_m *StorageRepository) AddTaskToSprint(ctx context.Context, sprintID int, taskID int) error {
+	ret := _m.Called(ctx, sprintID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTaskToSprint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, sprintID, taskID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTeamMember provides a mock function with given fields: ctx, teamID, userID
+func (_m *StorageRepository) AddTeamMember(ctx context.Context, teamID int, userID int) error {
+	ret := _m.Called(ctx, teamID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTeamMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, teamID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AssigneesByTaskIDs provides a mock function with given fields: ctx, taskIDs
+func (_m *StorageRepository) AssigneesByTaskIDs(ctx context.Context, taskIDs []int) (map[int][]model.User, error) {
+	ret := _m.Called(ctx, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssigneesByTaskIDs")
+	}
+
+	var r0 map[int][]model.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) (map[int][]model.User, error)); ok {
+		return rf(ctx, taskIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) map[int][]model.User); ok {
+		r0 = rf(ctx, taskIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]model.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, taskIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BatchTasks provides a mock function with given fields: ctx, batch
+func (_m *StorageRepository) BatchTasks(ctx context.Context, batch model.TaskBatch) ([]model.TaskBatchResult, error) {
+	ret := _m.Called(ctx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BatchTasks")
+	}
+
+	var r0 []model.TaskBatchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskBatch) ([]model.TaskBatchResult, error)); ok {
+		return rf(ctx, batch)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskBatch) []model.TaskBatchResult); ok {
+		r0 = rf(ctx, batch)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskBatchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskBatch) error); ok {
+		r1 = rf(ctx, batch)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalendarByID provides a mock function with given fields: ctx, calendarID
+func (_m *StorageRepository) CalendarByID(ctx context.Context, calendarID int) (model.Calendar, error) {
+	ret := _m.Called(ctx, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalendarByID")
+	}
+
+	var r0 model.Calendar
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Calendar, error)); ok {
+		return rf(ctx, calendarID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.Calendar); ok {
+		r0 = rf(ctx, calendarID)
+	} else {
+		r0 = ret.Get(0).(model.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, calendarID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CalendarFor provides a mock function with given fields: ctx, projectID, userID
+func (_m *StorageRepository) CalendarFor(ctx context.Context, projectID int, userID int) (model.Calendar, bool, error) {
+	ret := _m.Called(ctx, projectID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CalendarFor")
+	}
+
+	var r0 model.Calendar
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (model.Calendar, bool, error)); ok {
+		return rf(ctx, projectID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) model.Calendar); ok {
+		r0 = rf(ctx, projectID, userID)
+	} else {
+		r0 = ret.Get(0).(model.Calendar)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) bool); ok {
+		r1 = rf(ctx, projectID, userID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, projectID, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ClaimEscalationNotices provides a mock function with given fields: ctx
+func (_m *StorageRepository) ClaimEscalationNotices(ctx context.Context) ([]model.EscalationNotice, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimEscalationNotices")
+	}
+
+	var r0 []model.EscalationNotice
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.EscalationNotice, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.EscalationNotice); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.EscalationNotice)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimReminder provides a mock function with given fields: ctx, taskID, userID, threshold, deadline
+func (_m *StorageRepository) ClaimReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) (bool, error) {
+	ret := _m.Called(ctx, taskID, userID, threshold, deadline)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimReminder")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Duration, time.Time) (bool, error)); ok {
+		return rf(ctx, taskID, userID, threshold, deadline)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Duration, time.Time) bool); ok {
+		r0 = rf(ctx, taskID, userID, threshold, deadline)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, time.Duration, time.Time) error); ok {
+		r1 = rf(ctx, taskID, userID, threshold, deadline)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimWebhookDeliveries provides a mock function with given fields: ctx, limit, lease
+func (_m *StorageRepository) ClaimWebhookDeliveries(ctx context.Context, limit int, lease time.Duration) ([]model.WebhookJob, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimWebhookDeliveries")
+	}
+
+	var r0 []model.WebhookJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]model.WebhookJob, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []model.WebhookJob); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.WebhookJob)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompleteSprint provides a mock function with given fields: ctx, sprintID, nextSprintID
+func (_m *StorageRepository) CompleteSprint(ctx context.Context, sprintID int, nextSprintID int) ([]int, error) {
+	ret := _m.Called(ctx, sprintID, nextSprintID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteSprint")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) ([]int, error)); ok {
+		return rf(ctx, sprintID, nextSprintID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) []int); ok {
+		r0 = rf(ctx, sprintID, nextSprintID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, sprintID, nextSprintID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateCalendar provides a mock function with given fields: ctx, calendar
+func (_m *StorageRepository) CreateCalendar(ctx context.Context, calendar model.Calendar) (int, error) {
+	ret := _m.Called(ctx, calendar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateCalendar")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Calendar) (int, error)); ok {
+		return rf(ctx, calendar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Calendar) int); ok {
+		r0 = rf(ctx, calendar)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Calendar) error); ok {
+		r1 = rf(ctx, calendar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateEscalationRule provides a mock function with given fields: ctx, rule
+func (_m *StorageRepository) CreateEscalationRule(ctx context.Context, rule model.EscalationRule) (int, error) {
+	ret := _m.Called(ctx, rule)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateEscalationRule")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule) (int, error)); ok {
+		return rf(ctx, rule)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule) int); ok {
+		r0 = rf(ctx, rule)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.EscalationRule) error); ok {
+		r1 = rf(ctx, rule)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateMilestone provides a mock function with given fields: ctx, milestone
+func (_m *StorageRepository) CreateMilestone(ctx context.Context, milestone model.Milestone) (int, error) {
+	ret := _m.Called(ctx, milestone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateMilestone")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Milestone) (int, error)); ok {
+		return rf(ctx, milestone)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Milestone) int); ok {
+		r0 = rf(ctx, milestone)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Milestone) error); ok {
+		r1 = rf(ctx, milestone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateNewTask provides a mock function with given fields: ctx, task
+func (_m *StorageRepository) CreateNewTask(ctx context.Context, task model.Task) (model.Task, error) {
+	ret := _m.Called(ctx, task)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateNewTask")
+	}
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Task) (model.Task, error)); ok {
+		return rf(ctx, task)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Task) model.Task); ok {
+		r0 = rf(ctx, task)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Task) error); ok {
+		r1 = rf(ctx, task)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateProject provides a mock function with given fields: ctx, project
+func (_m *StorageRepository) CreateProject(ctx context.Context, project model.Project) (int, error) {
+	ret := _m.Called(ctx, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateProject")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Project) (int, error)); ok {
+		return rf(ctx, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Project) int); ok {
+		r0 = rf(ctx, project)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Project) error); ok {
+		r1 = rf(ctx, project)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateSprint provides a mock function with given fields: ctx, sprint
+func (_m *StorageRepository) CreateSprint(ctx context.Context, sprint model.Sprint) (int, error) {
+	ret := _m.Called(ctx, sprint)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateSprint")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Sprint) (int, error)); ok {
+		return rf(ctx, sprint)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Sprint) int); ok {
+		r0 = rf(ctx, sprint)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Sprint) error); ok {
+		r1 = rf(ctx, sprint)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateTeam provides a mock function with given fields: ctx, team
+func (_m *StorageRepository) CreateTeam(ctx context.Context, team model.Team) (int, error) {
+	ret := _m.Called(ctx, team)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTeam")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Team) (int, error)); ok {
+		return rf(ctx, team)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Team) int); ok {
+		r0 = rf(ctx, team)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Team) error); ok {
+		r1 = rf(ctx, team)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateView provides a mock function with given fields: ctx, view
+func (_m *StorageRepository) CreateView(ctx context.Context, view model.View) (int, error) {
+	ret := _m.Called(ctx, view)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateView")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.View) (int, error)); ok {
+		return rf(ctx, view)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.View) int); ok {
+		r0 = rf(ctx, view)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.View) error); ok {
+		r1 = rf(ctx, view)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateWebhook provides a mock function with given fields: ctx, webhook
+func (_m *StorageRepository) CreateWebhook(ctx context.Context, webhook model.Webhook) (int, error) {
+	ret := _m.Called(ctx, webhook)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateWebhook")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Webhook) (int, error)); ok {
+		return rf(ctx, webhook)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Webhook) int); ok {
+		r0 = rf(ctx, webhook)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Webhook) error); ok {
+		r1 = rf(ctx, webhook)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteEscalationRule provides a mock function with given fields: ctx, ruleID
+func (_m *StorageRepository) DeleteEscalationRule(ctx context.Context, ruleID int) error {
+	ret := _m.Called(ctx, ruleID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteEscalationRule")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, ruleID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTask provides a mock function with given fields: ctx, taskID, version
+func (_m *StorageRepository) DeleteTask(ctx context.Context, taskID int, version int) (int, []int, error) {
+	ret := _m.Called(ctx, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteTask")
+	}
+
+	var r0 int
+	var r1 []int
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int, []int, error)); ok {
+		return rf(ctx, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int); ok {
+		r0 = rf(ctx, taskID, version)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) []int); ok {
+		r1 = rf(ctx, taskID, version)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int, int) error); ok {
+		r2 = rf(ctx, taskID, version)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// DeleteView provides a mock function with given fields: ctx, viewID
+func (_m *StorageRepository) DeleteView(ctx context.Context, viewID int) error {
+	ret := _m.Called(ctx, viewID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteView")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, viewID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteWebhook provides a mock function with given fields: ctx, webhookID
+func (_m *StorageRepository) DeleteWebhook(ctx context.Context, webhookID int) error {
+	ret := _m.Called(ctx, webhookID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteWebhook")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, webhookID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnqueueWebhookDeliveries provides a mock function with given fields: ctx, event, payload
+func (_m *StorageRepository) EnqueueWebhookDeliveries(ctx context.Context, event model.TaskEvent, payload []byte) (int, error) {
+	ret := _m.Called(ctx, event, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnqueueWebhookDeliveries")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskEvent, []byte) (int, error)); ok {
+		return rf(ctx, event, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskEvent, []byte) int); ok {
+		r0 = rf(ctx, event, payload)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskEvent, []byte) error); ok {
+		r1 = rf(ctx, event, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EscalateTask provides a mock function with given fields: ctx, rule, task, details
+func (_m *StorageRepository) EscalateTask(ctx context.Context, rule model.EscalationRule, task model.Task, details string) (bool, error) {
+	ret := _m.Called(ctx, rule, task, details)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EscalateTask")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule, model.Task, string) (bool, error)); ok {
+		return rf(ctx, rule, task, details)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule, model.Task, string) bool); ok {
+		r0 = rf(ctx, rule, task, details)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.EscalationRule, model.Task, string) error); ok {
+		r1 = rf(ctx, rule, task, details)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EscalationRules provides a mock function with given fields: ctx, projectID
+func (_m *StorageRepository) EscalationRules(ctx context.Context, projectID int) ([]model.EscalationRule, error) {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EscalationRules")
+	}
+
+	var r0 []model.EscalationRule
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.EscalationRule, error)); ok {
+		return rf(ctx, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.EscalationRule); ok {
+		r0 = rf(ctx, projectID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.EscalationRule)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// EventScope provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) EventScope(ctx context.Context, userID int) (model.EventScope, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EventScope")
+	}
+
+	var r0 model.EventScope
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.EventScope, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.EventScope); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(model.EventScope)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllUsersWorkTask provides a mock function with given fields: ctx, taskID, page
+func (_m *StorageRepository) GetAllUsersWorkTask(ctx context.Context, taskID int, page model.PageRequest) (model.Page[model.User], error) {
+	ret := _m.Called(ctx, taskID, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllUsersWorkTask")
+	}
+
+	var r0 model.Page[model.User]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) (model.Page[model.User], error)); ok {
+		return rf(ctx, taskID, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) model.Page[model.User]); ok {
+		r0 = rf(ctx, taskID, page)
+	} else {
+		r0 = ret.Get(0).(model.Page[model.User])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.PageRequest) error); ok {
+		r1 = rf(ctx, taskID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTasks provides a mock function with given fields: ctx, filter, page
+func (_m *StorageRepository) ListTasks(ctx context.Context, filter model.TaskFilter, page model.PageRequest) (model.Page[model.Task], error) {
+	ret := _m.Called(ctx, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListTasks")
+	}
+
+	var r0 model.Page[model.Task]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskFilter, model.PageRequest) (model.Page[model.Task], error)); ok {
+		return rf(ctx, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.TaskFilter, model.PageRequest) model.Page[model.Task]); ok {
+		r0 = rf(ctx, filter, page)
+	} else {
+		r0 = ret.Get(0).(model.Page[model.Task])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.TaskFilter, model.PageRequest) error); ok {
+		r1 = rf(ctx, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProjectByID provides a mock function with given fields: ctx, projectID
+func (_m *StorageRepository) ProjectByID(ctx context.Context, projectID int) (model.Project, error) {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProjectByID")
+	}
+
+	var r0 model.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Project, error)); ok {
+		return rf(ctx, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.Project); ok {
+		r0 = rf(ctx, projectID)
+	} else {
+		r0 = ret.Get(0).(model.Project)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, projectID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProjectMember provides a mock function with given fields: ctx, projectID, userID
+func (_m *StorageRepository) ProjectMember(ctx context.Context, projectID int, userID int) (bool, error) {
+	ret := _m.Called(ctx, projectID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProjectMember")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (bool, error)); ok {
+		return rf(ctx, projectID, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) bool); ok {
+		r0 = rf(ctx, projectID, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, projectID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ProjectsByIDs provides a mock function with given fields: ctx, projectIDs
+func (_m *StorageRepository) ProjectsByIDs(ctx context.Context, projectIDs []int) ([]model.Project, error) {
+	ret := _m.Called(ctx, projectIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProjectsByIDs")
+	}
+
+	var r0 []model.Project
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]model.Project, error)); ok {
+		return rf(ctx, projectIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []model.Project); ok {
+		r0 = rf(ctx, projectIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Project)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, projectIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PruneSyncTombstones provides a mock function with given fields: ctx, before
+func (_m *StorageRepository) PruneSyncTombstones(ctx context.Context, before time.Time) (int, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PruneSyncTombstones")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecordWebhookAttempt provides a mock function with given fields: ctx, attempt, disableAfter
+func (_m *StorageRepository) RecordWebhookAttempt(ctx context.Context, attempt model.WebhookAttempt, disableAfter int) (bool, error) {
+	ret := _m.Called(ctx, attempt, disableAfter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordWebhookAttempt")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.WebhookAttempt, int) (bool, error)); ok {
+		return rf(ctx, attempt, disableAfter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.WebhookAttempt, int) bool); ok {
+		r0 = rf(ctx, attempt, disableAfter)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.WebhookAttempt, int) error); ok {
+		r1 = rf(ctx, attempt, disableAfter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ReleaseEscalationNotice provides a mock function with given fields: ctx, notice
+func (_m *StorageRepository) ReleaseEscalationNotice(ctx context.Context, notice model.EscalationNotice) error {
+	ret := _m.Called(ctx, notice)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseEscalationNotice")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationNotice) error); ok {
+		r0 = rf(ctx, notice)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseReminder provides a mock function with given fields: ctx, taskID, userID, threshold, deadline
+func (_m *StorageRepository) ReleaseReminder(ctx context.Context, taskID int, userID int, threshold time.Duration, deadline time.Time) error {
+	ret := _m.Called(ctx, taskID, userID, threshold, deadline)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseReminder")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, time.Duration, time.Time) error); ok {
+		r0 = rf(ctx, taskID, userID, threshold, deadline)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReleaseViewMatches provides a mock function with given fields: ctx, viewID, userID, taskIDs
+func (_m *StorageRepository) ReleaseViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) error {
+	ret := _m.Called(ctx, viewID, userID, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseViewMatches")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []int) error); ok {
+		r0 = rf(ctx, viewID, userID, taskIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveUserFromTask provides a mock function with given fields: ctx, userID, taskID
+func (_m *StorageRepository) RemoveUserFromTask(ctx context.Context, userID int, taskID int) error {
+	ret := _m.Called(ctx, userID, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveUserFromTask")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, taskID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplayWebhookDelivery provides a mock function with given fields: ctx, webhookID, deliveryID
+func (_m *StorageRepository) ReplayWebhookDelivery(ctx context.Context, webhookID int, deliveryID int) (int, error) {
+	ret := _m.Called(ctx, webhookID, deliveryID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplayWebhookDelivery")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) (int, error)); ok {
+		return rf(ctx, webhookID, deliveryID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) int); ok {
+		r0 = rf(ctx, webhookID, deliveryID)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, int) error); ok {
+		r1 = rf(ctx, webhookID, deliveryID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchTasks provides a mock function with given fields: ctx, words, filter, page
+func (_m *StorageRepository) SearchTasks(ctx context.Context, words []string, filter model.TaskFilter, page model.PageRequest) (model.Page[model.SearchResult], error) {
+	ret := _m.Called(ctx, words, filter, page)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchTasks")
+	}
+
+	var r0 model.Page[model.SearchResult]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, model.TaskFilter, model.PageRequest) (model.Page[model.SearchResult], error)); ok {
+		return rf(ctx, words, filter, page)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, model.TaskFilter, model.PageRequest) model.Page[model.SearchResult]); ok {
+		r0 = rf(ctx, words, filter, page)
+	} else {
+		r0 = ret.Get(0).(model.Page[model.SearchResult])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, model.TaskFilter, model.PageRequest) error); ok {
+		r1 = rf(ctx, words, filter, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetProjectCalendar provides a mock function with given fields: ctx, projectID, calendarID
+func (_m *StorageRepository) SetProjectCalendar(ctx context.Context, projectID int, calendarID int) error {
+	ret := _m.Called(ctx, projectID, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetProjectCalendar")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, projectID, calendarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTaskMilestone provides a mock function with given fields: ctx, taskID, milestoneID
+func (_m *StorageRepository) SetTaskMilestone(ctx context.Context, taskID int, milestoneID int) error {
+	ret := _m.Called(ctx, taskID, milestoneID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTaskMilestone")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, taskID, milestoneID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserCalendar provides a mock function with given fields: ctx, userID, calendarID
+func (_m *StorageRepository) SetUserCalendar(ctx context.Context, userID int, calendarID int) error {
+	ret := _m.Called(ctx, userID, calendarID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserCalendar")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, userID, calendarID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserLanguage provides a mock function with given fields: ctx, userID, language
+func (_m *StorageRepository) SetUserLanguage(ctx context.Context, userID int, language string) error {
+	ret := _m.Called(ctx, userID, language)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserLanguage")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, language)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserTimezone provides a mock function with given fields: ctx, userID, timezone
+func (_m *StorageRepository) SetUserTimezone(ctx context.Context, userID int, timezone string) error {
+	ret := _m.Called(ctx, userID, timezone)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserTimezone")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, string) error); ok {
+		r0 = rf(ctx, userID, timezone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SprintByID provides a mock function with given fields: ctx, sprintID
+func (_m *StorageRepository) SprintByID(ctx context.Context, sprintID int) (model.Sprint, error) {
+	ret := _m.Called(ctx, sprintID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SprintByID")
+	}
+
+	var r0 model.Sprint
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Sprint, error)); ok {
+		return rf(ctx, sprintID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.Sprint); ok {
+		r0 = rf(ctx, sprintID)
+	} else {
+		r0 = ret.Get(0).(model.Sprint)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, sprintID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StartSprint provides a mock function with given fields: ctx, sprintID
+func (_m *StorageRepository) StartSprint(ctx context.Context, sprintID int) error {
+	ret := _m.Called(ctx, sprintID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartSprint")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = rf(ctx, sprintID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubscribeView provides a mock function with given fields: ctx, viewID, userID
+func (_m *StorageRepository) SubscribeView(ctx context.Context, viewID int, userID int) error {
+	ret := _m.Called(ctx, viewID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SubscribeView")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
+		r0 = rf(ctx, viewID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SyncChanges provides a mock function with given fields: ctx, scope, since, limit
+func (_m *StorageRepository) SyncChanges(ctx context.Context, scope model.EventScope, since int64, limit int) (model.SyncChanges, error) {
+	ret := _m.Called(ctx, scope, since, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncChanges")
+	}
+
+	var r0 model.SyncChanges
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EventScope, int64, int) (model.SyncChanges, error)); ok {
+		return rf(ctx, scope, since, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.EventScope, int64, int) model.SyncChanges); ok {
+		r0 = rf(ctx, scope, since, limit)
+	} else {
+		r0 = ret.Get(0).(model.SyncChanges)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.EventScope, int64, int) error); ok {
+		r1 = rf(ctx, scope, since, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskByID provides a mock function with given fields: ctx, taskID
+func (_m *StorageRepository) TaskByID(ctx context.Context, taskID int) (model.Task, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskByID")
+	}
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Task, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.Task); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskCounts provides a mock function with given fields: ctx, now
+func (_m *StorageRepository) TaskCounts(ctx context.Context, now time.Time) (model.TaskCounts, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskCounts")
+	}
+
+	var r0 model.TaskCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (model.TaskCounts, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) model.TaskCounts); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(model.TaskCounts)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskHistory provides a mock function with given fields: ctx, taskID
+func (_m *StorageRepository) TaskHistory(ctx context.Context, taskID int) ([]model.TaskHistory, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskHistory")
+	}
+
+	var r0 []model.TaskHistory
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.TaskHistory, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.TaskHistory); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TaskHistory)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskMilestoneOverrun provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) TaskMilestoneOverrun(ctx context.Context, userID int) ([]model.Task, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskMilestoneOverrun")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Task, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Task); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskShortDeadline provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskShortDeadline")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Task, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Task); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TaskUpdateStatus provides a mock function with given fields: ctx, newStatus, taskID, version
+func (_m *StorageRepository) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int, version int) (model.Task, error) {
+	ret := _m.Called(ctx, newStatus, taskID, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TaskUpdateStatus")
+	}
+
+	var r0 model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) (model.Task, error)); ok {
+		return rf(ctx, newStatus, taskID, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) model.Task); ok {
+		r0 = rf(ctx, newStatus, taskID, version)
+	} else {
+		r0 = ret.Get(0).(model.Task)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) error); ok {
+		r1 = rf(ctx, newStatus, taskID, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// AddNewUserTask provides a mock function with given fields: ctx, userID, taskID
-func (_m *StorageRepository) AddNewUserTask(ctx context.Context, userID int, taskID int) error {
-	ret := _m.Called(ctx, userID, taskID)
+// TasksByAssignees provides a mock function with given fields: ctx, userIDs, limit
+func (_m *StorageRepository) TasksByAssignees(ctx context.Context, userIDs []int, limit int) (map[int][]model.Task, error) {
+	ret := _m.Called(ctx, userIDs, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for AddNewUserTask")
+		panic("no return value specified for TasksByAssignees")
+	}
+
+	var r0 map[int][]model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int, int) (map[int][]model.Task, error)); ok {
+		return rf(ctx, userIDs, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int, int) map[int][]model.Task); ok {
+		r0 = rf(ctx, userIDs, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int][]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int, int) error); ok {
+		r1 = rf(ctx, userIDs, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TasksByIDs provides a mock function with given fields: ctx, taskIDs
+func (_m *StorageRepository) TasksByIDs(ctx context.Context, taskIDs []int) ([]model.Task, error) {
+	ret := _m.Called(ctx, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TasksByIDs")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]model.Task, error)); ok {
+		return rf(ctx, taskIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []model.Task); ok {
+		r0 = rf(ctx, taskIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, taskIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TasksDueBefore provides a mock function with given fields: ctx, before
+func (_m *StorageRepository) TasksDueBefore(ctx context.Context, before time.Time) ([]model.Task, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TasksDueBefore")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]model.Task, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []model.Task); ok {
+		r0 = rf(ctx, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TasksToEscalate provides a mock function with given fields: ctx, rule, deadlineBefore
+func (_m *StorageRepository) TasksToEscalate(ctx context.Context, rule model.EscalationRule, deadlineBefore time.Time) ([]model.Task, error) {
+	ret := _m.Called(ctx, rule, deadlineBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TasksToEscalate")
+	}
+
+	var r0 []model.Task
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule, time.Time) ([]model.Task, error)); ok {
+		return rf(ctx, rule, deadlineBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.EscalationRule, time.Time) []model.Task); ok {
+		r0 = rf(ctx, rule, deadlineBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Task)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.EscalationRule, time.Time) error); ok {
+		r1 = rf(ctx, rule, deadlineBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UnsubscribeView provides a mock function with given fields: ctx, viewID, userID
+func (_m *StorageRepository) UnsubscribeView(ctx context.Context, viewID int, userID int) error {
+	ret := _m.Called(ctx, viewID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnsubscribeView")
 	}
 
 	var r0 error
 	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = rf(ctx, userID, taskID)
+		r0 = rf(ctx, viewID, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -33,27 +1607,47 @@ func (_m *StorageRepository) AddNewUserTask(ctx context.Context, userID int, tas
 	return r0
 }
 
-// CreateNewTask provides a mock function with given fields: ctx, task
-func (_m *StorageRepository) CreateNewTask(ctx context.Context, task model.Task) (int, error) {
-	ret := _m.Called(ctx, task)
+// UpdateView provides a mock function with given fields: ctx, view
+func (_m *StorageRepository) UpdateView(ctx context.Context, view model.View) error {
+	ret := _m.Called(ctx, view)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateNewTask")
+		panic("no return value specified for UpdateView")
 	}
 
-	var r0 int
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.View) error); ok {
+		r0 = rf(ctx, view)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateViewMatches provides a mock function with given fields: ctx, viewID, userID, taskIDs
+func (_m *StorageRepository) UpdateViewMatches(ctx context.Context, viewID int, userID int, taskIDs []int) ([]int, error) {
+	ret := _m.Called(ctx, viewID, userID, taskIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateViewMatches")
+	}
+
+	var r0 []int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, model.Task) (int, error)); ok {
-		return rf(ctx, task)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []int) ([]int, error)); ok {
+		return rf(ctx, viewID, userID, taskIDs)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, model.Task) int); ok {
-		r0 = rf(ctx, task)
+	if rf, ok := ret.Get(0).(func(context.Context, int, int, []int) []int); ok {
+		r0 = rf(ctx, viewID, userID, taskIDs)
 	} else {
-		r0 = ret.Get(0).(int)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, model.Task) error); ok {
-		r1 = rf(ctx, task)
+	if rf, ok := ret.Get(1).(func(context.Context, int, int, []int) error); ok {
+		r1 = rf(ctx, viewID, userID, taskIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -61,17 +1655,17 @@ func (_m *StorageRepository) CreateNewTask(ctx context.Context, task model.Task)
 	return r0, r1
 }
 
-// DeleteTask provides a mock function with given fields: ctx, taskID
-func (_m *StorageRepository) DeleteTask(ctx context.Context, taskID int) error {
-	ret := _m.Called(ctx, taskID)
+// UpdateWebhook provides a mock function with given fields: ctx, webhook
+func (_m *StorageRepository) UpdateWebhook(ctx context.Context, webhook model.Webhook) error {
+	ret := _m.Called(ctx, webhook)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteTask")
+		panic("no return value specified for UpdateWebhook")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) error); ok {
-		r0 = rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Webhook) error); ok {
+		r0 = rf(ctx, webhook)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -79,25 +1673,53 @@ func (_m *StorageRepository) DeleteTask(ctx context.Context, taskID int) error {
 	return r0
 }
 
-// GetAllTasks provides a mock function with given fields: ctx, userID
-func (_m *StorageRepository) GetAllTasks(ctx context.Context, userID int) ([]model.Task, error) {
+// UserByID provides a mock function with given fields: ctx, taskID
+func (_m *StorageRepository) UserByID(ctx context.Context, taskID int) ([]int, error) {
+	ret := _m.Called(ctx, taskID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UserByID")
+	}
+
+	var r0 []int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
+		return rf(ctx, taskID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
+		r0 = rf(ctx, taskID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, taskID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserLanguage provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) UserLanguage(ctx context.Context, userID int) (string, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllTasks")
+		panic("no return value specified for UserLanguage")
 	}
 
-	var r0 []model.Task
+	var r0 string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Task, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Task); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
 		r0 = rf(ctx, userID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Task)
-		}
+		r0 = ret.Get(0).(string)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
@@ -109,29 +1731,57 @@ func (_m *StorageRepository) GetAllTasks(ctx context.Context, userID int) ([]mod
 	return r0, r1
 }
 
-// GetAllUsersWorkTask provides a mock function with given fields: ctx, taskID
-func (_m *StorageRepository) GetAllUsersWorkTask(ctx context.Context, taskID int) ([]model.User, error) {
-	ret := _m.Called(ctx, taskID)
+// UserTimezone provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) UserTimezone(ctx context.Context, userID int) (string, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllUsersWorkTask")
+		panic("no return value specified for UserTimezone")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (string, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) string); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UsersByIDs provides a mock function with given fields: ctx, userIDs
+func (_m *StorageRepository) UsersByIDs(ctx context.Context, userIDs []int) ([]model.User, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UsersByIDs")
 	}
 
 	var r0 []model.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.User, error)); ok {
-		return rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, []int) ([]model.User, error)); ok {
+		return rf(ctx, userIDs)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []model.User); ok {
-		r0 = rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, []int) []model.User); ok {
+		r0 = rf(ctx, userIDs)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, taskID)
+	if rf, ok := ret.Get(1).(func(context.Context, []int) error); ok {
+		r1 = rf(ctx, userIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -139,45 +1789,57 @@ func (_m *StorageRepository) GetAllUsersWorkTask(ctx context.Context, taskID int
 	return r0, r1
 }
 
-// RemoveUserFromTask provides a mock function with given fields: ctx, userID, taskID
-func (_m *StorageRepository) RemoveUserFromTask(ctx context.Context, userID int, taskID int) error {
-	ret := _m.Called(ctx, userID, taskID)
+// ViewByID provides a mock function with given fields: ctx, viewID
+func (_m *StorageRepository) ViewByID(ctx context.Context, viewID int) (model.View, error) {
+	ret := _m.Called(ctx, viewID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for RemoveUserFromTask")
+		panic("no return value specified for ViewByID")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, int) error); ok {
-		r0 = rf(ctx, userID, taskID)
+	var r0 model.View
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.View, error)); ok {
+		return rf(ctx, viewID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.View); ok {
+		r0 = rf(ctx, viewID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(model.View)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, viewID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// TaskByID provides a mock function with given fields: ctx, taskID
-func (_m *StorageRepository) TaskByID(ctx context.Context, taskID int) (model.Task, error) {
-	ret := _m.Called(ctx, taskID)
+// ViewSubscriptions provides a mock function with given fields: ctx
+func (_m *StorageRepository) ViewSubscriptions(ctx context.Context) ([]model.ViewSubscription, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for TaskByID")
+		panic("no return value specified for ViewSubscriptions")
 	}
 
-	var r0 model.Task
+	var r0 []model.ViewSubscription
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Task, error)); ok {
-		return rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.ViewSubscription, error)); ok {
+		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) model.Task); ok {
-		r0 = rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context) []model.ViewSubscription); ok {
+		r0 = rf(ctx)
 	} else {
-		r0 = ret.Get(0).(model.Task)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.ViewSubscription)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, taskID)
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -185,24 +1847,24 @@ func (_m *StorageRepository) TaskByID(ctx context.Context, taskID int) (model.Ta
 	return r0, r1
 }
 
-// TaskShortDeadline provides a mock function with given fields: ctx, userID
-func (_m *StorageRepository) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task, error) {
+// Views provides a mock function with given fields: ctx, userID
+func (_m *StorageRepository) Views(ctx context.Context, userID int) ([]model.View, error) {
 	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for TaskShortDeadline")
+		panic("no return value specified for Views")
 	}
 
-	var r0 []model.Task
+	var r0 []model.View
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Task, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.View, error)); ok {
 		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Task); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.View); ok {
 		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]model.Task)
+			r0 = ret.Get(0).([]model.View)
 		}
 	}
 
@@ -215,47 +1877,85 @@ func (_m *StorageRepository) TaskShortDeadline(ctx context.Context, userID int)
 	return r0, r1
 }
 
-// TaskUpdateStatus provides a mock function with given fields: ctx, newStatus, taskID
-func (_m *StorageRepository) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int) error {
-	ret := _m.Called(ctx, newStatus, taskID)
+// WebhookByID provides a mock function with given fields: ctx, webhookID
+func (_m *StorageRepository) WebhookByID(ctx context.Context, webhookID int) (model.Webhook, error) {
+	ret := _m.Called(ctx, webhookID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for TaskUpdateStatus")
+		panic("no return value specified for WebhookByID")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, int) error); ok {
-		r0 = rf(ctx, newStatus, taskID)
+	var r0 model.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) (model.Webhook, error)); ok {
+		return rf(ctx, webhookID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) model.Webhook); ok {
+		r0 = rf(ctx, webhookID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(model.Webhook)
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, webhookID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UserByID provides a mock function with given fields: ctx, taskID
-func (_m *StorageRepository) UserByID(ctx context.Context, taskID int) ([]int, error) {
-	ret := _m.Called(ctx, taskID)
+// WebhookDeliveries provides a mock function with given fields: ctx, webhookID, page
+func (_m *StorageRepository) WebhookDeliveries(ctx context.Context, webhookID int, page model.PageRequest) (model.Page[model.WebhookDelivery], error) {
+	ret := _m.Called(ctx, webhookID, page)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UserByID")
+		panic("no return value specified for WebhookDeliveries")
 	}
 
-	var r0 []int
+	var r0 model.Page[model.WebhookDelivery]
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int) ([]int, error)); ok {
-		return rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) (model.Page[model.WebhookDelivery], error)); ok {
+		return rf(ctx, webhookID, page)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int) []int); ok {
-		r0 = rf(ctx, taskID)
+	if rf, ok := ret.Get(0).(func(context.Context, int, model.PageRequest) model.Page[model.WebhookDelivery]); ok {
+		r0 = rf(ctx, webhookID, page)
+	} else {
+		r0 = ret.Get(0).(model.Page[model.WebhookDelivery])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, model.PageRequest) error); ok {
+		r1 = rf(ctx, webhookID, page)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Webhooks provides a mock function with given fields: ctx, projectID
+func (_m *StorageRepository) Webhooks(ctx context.Context, projectID int) ([]model.Webhook, error) {
+	ret := _m.Called(ctx, projectID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Webhooks")
+	}
+
+	var r0 []model.Webhook
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]model.Webhook, error)); ok {
+		return rf(ctx, projectID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []model.Webhook); ok {
+		r0 = rf(ctx, projectID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]int)
+			r0 = ret.Get(0).([]model.Webhook)
 		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = rf(ctx, taskID)
+		r1 = rf(ctx, projectID)
 	} else {
 		r1 = ret.Error(1)
 	}
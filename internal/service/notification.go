@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"Tasks/internal/lib/i18n"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+func (s *Service) SetUserLanguage(ctx context.Context, userID int, language string) error {
+	if !i18n.Supported(language) {
+		return model.Invalid("unknown language %q", language)
+	}
+	return s.repo.SetUserLanguage(ctx, userID, language)
+}
+
+// notify отправляет уведомление в Kafka с текстом на языке получателя msg.UserID
+func (s *Service) notify(ctx context.Context, msg model.NotificationMessage) error {
+	msg = i18n.Notification(msg, s.UserLanguage(ctx, msg.UserID))
+
+	msgJSON, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	if err := s.producer.Produce(msgJSON, "notification"); err != nil {
+		return fmt.Errorf("failed to produce message: %w", err)
+	}
+	return nil
+}
+
+// UserLanguage язык из профиля пользователя, при любой ошибке - язык по умолчанию
+func (s *Service) UserLanguage(ctx context.Context, userID int) string {
+	language, err := s.repo.UserLanguage(ctx, userID)
+	if err != nil {
+		s.log.Warn("failed to get user language, falling back to default",
+			slog.String("op", "service.UserLanguage"), slog.Int("user_id", userID), sl.Err(err))
+		return i18n.Default
+	}
+	return language
+}
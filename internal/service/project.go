@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"Tasks/internal/model"
+)
+
+func (s *Service) CreateProject(ctx context.Context, project model.Project) (int, error) {
+	return s.repo.CreateProject(ctx, project)
+}
+
+func (s *Service) CreateEscalationRule(ctx context.Context, rule model.EscalationRule) (int, error) {
+	if !model.ValidPriority(rule.Priority) {
+		return -1, model.Invalid("unknown task priority %q", rule.Priority)
+	}
+	if rule.OverdueBy < 0 {
+		return -1, model.Invalid("overdue interval must not be negative")
+	}
+	if !rule.AssignManager && !rule.NotifyManager {
+		return -1, model.Invalid("escalation rule must assign or notify the project manager")
+	}
+	if _, err := s.repo.ProjectByID(ctx, rule.ProjectID); err != nil {
+		return -1, err
+	}
+	return s.repo.CreateEscalationRule(ctx, rule)
+}
+
+func (s *Service) EscalationRules(ctx context.Context, projectID int) ([]model.EscalationRule, error) {
+	return s.repo.EscalationRules(ctx, projectID)
+}
+
+// EscalateTask фиксирует эскалацию задачи по правилу и, если правило велит, назначает руководителя проекта.
+// Назначение сбрасывает кэш задачи и, как назначение через AddUser, публикуется событием task_assigned
+// для потока событий, доски и вебхуков. Возвращает false, если задача уже эскалирована по этому правилу.
+func (s *Service) EscalateTask(ctx context.Context, rule model.EscalationRule, task model.Task, details string) (bool, error) {
+	escalated, err := s.repo.EscalateTask(ctx, rule, task, details)
+	if err != nil || !escalated {
+		return false, err
+	}
+	if rule.AssignManager && rule.ManagerID != 0 {
+		log := s.log.With(slog.String("op", "service.EscalateTask"))
+		s.invalidateCache(ctx, log, task.ID)
+		s.publishTask(ctx, log, model.TaskEventAssigned, task.ID, rule.ManagerID)
+	}
+	return true, nil
+}
+
+func (s *Service) DeleteEscalationRule(ctx context.Context, ruleID int) error {
+	return s.repo.DeleteEscalationRule(ctx, ruleID)
+}
+
+func (s *Service) TaskHistory(ctx context.Context, taskID int) ([]model.TaskHistory, error) {
+	return s.repo.TaskHistory(ctx, taskID)
+}
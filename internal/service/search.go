@@ -0,0 +1,43 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"Tasks/internal/model"
+)
+
+// maxSearchWords ограничивает размер tsquery, который строит репозиторий
+const maxSearchWords = 16
+
+var searchWord = regexp.MustCompile(`[\p{L}\p{N}]+`)
+
+// searchWords выделяет из строки запроса слова: буквы и цифры, остальные символы - разделители.
+// Операторы tsquery в запрос не попадают, поэтому строку пользователя не нужно экранировать.
+func searchWords(text string) ([]string, error) {
+	words := searchWord.FindAllString(strings.ToLower(text), -1)
+	if len(words) == 0 {
+		return nil, model.Invalid("search query must contain at least one word")
+	}
+	if len(words) > maxSearchWords {
+		return nil, model.Invalid("search query must contain at most %d words", maxSearchWords)
+	}
+	return words, nil
+}
+
+// Search ищет задачи, видимые пользователю callerID, по названию и описанию.
+// Результаты упорядочены по релевантности, каждое слово ищется по префиксу.
+func (s *Service) Search(ctx context.Context, callerID int, text string, page model.PageRequest) (model.Page[model.SearchResult], error) {
+	if page.Sort != "" && page.Sort != model.SortRank {
+		return model.Page[model.SearchResult]{}, model.Invalid("unknown sort field %q", page.Sort)
+	}
+	if page.After != nil && (page.After.Sort != model.SortRank || page.After.Desc != page.Desc) {
+		return model.Page[model.SearchResult]{}, model.Invalid("cursor does not match the requested sort order")
+	}
+	words, err := searchWords(text)
+	if err != nil {
+		return model.Page[model.SearchResult]{}, err
+	}
+	return s.repo.SearchTasks(ctx, words, model.TaskFilter{VisibleTo: callerID}, page)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_Search(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		page      model.PageRequest
+		wantWords []string
+		wantErr   bool
+	}{
+		{name: "words are lowercased", text: "Отчёт  Q3", wantWords: []string{"отчёт", "q3"}},
+		{name: "tsquery operators are dropped", text: "deploy & !prod | (stage):*", wantWords: []string{"deploy", "prod", "stage"}},
+		{
+			name:      "next page",
+			text:      "deploy",
+			page:      model.PageRequest{Limit: 10, After: &model.Cursor{Sort: model.SortRank, Value: "0.06", ID: 3}},
+			wantWords: []string{"deploy"},
+		},
+		{name: "no words", text: " &| ", wantErr: true},
+		{name: "too many words", text: strings.Repeat("word ", maxSearchWords+1), wantErr: true},
+		{name: "sort by deadline", text: "deploy", page: model.PageRequest{Sort: model.SortDeadline}, wantErr: true},
+		{
+			name:    "cursor from a task list",
+			text:    "deploy",
+			page:    model.PageRequest{After: &model.Cursor{Sort: model.SortDeadline, Value: "2025-01-15T10:00:00", ID: 3}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			if !tt.wantErr {
+				storageMock.On("SearchTasks", mock.Anything, tt.wantWords, model.TaskFilter{VisibleTo: 5}, tt.page).
+					Return(model.Page[model.SearchResult]{}, nil)
+			}
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+			_, err := s.Search(context.Background(), 5, tt.text, tt.page)
+			if tt.wantErr {
+				require.True(t, errors.Is(err, model.ErrValidation), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -20,30 +19,43 @@ type Service struct {
 	repo     interfaces.StorageRepository
 	cache    interfaces.CacheRepository
 	producer interfaces.Broker
+	// events журнал событий задач, без него события не публикуются
+	events interfaces.EventStream
 }
 
 func NewService(log *slog.Logger,
 	repo interfaces.StorageRepository,
 	repoCache interfaces.CacheRepository,
-	producer interfaces.Broker) *Service {
-	return &Service{log: log, repo: repo, cache: repoCache, producer: producer}
+	producer interfaces.Broker,
+	events interfaces.EventStream) *Service {
+	return &Service{log: log, repo: repo, cache: repoCache, producer: producer, events: events}
 }
 
 func (s *Service) CreateTask(ctx context.Context, task model.Task) (int, error) {
 	currentTime := time.Now()
 	if task.Deadline.Before(currentTime) {
-		return -1, fmt.Errorf("task deadline is too far in the past")
+		return -1, model.Invalid("task deadline is too far in the past")
+	}
+	if task.Priority == "" {
+		task.Priority = model.PriorityMedium
+	}
+	if !model.ValidPriority(task.Priority) {
+		return -1, model.Invalid("unknown task priority %q", task.Priority)
 	}
 
-	taskID, err := s.repo.CreateNewTask(ctx, task)
+	// событие и кэш получают записанную строку: статус, даты и версию задаёт база
+	created, err := s.repo.CreateNewTask(ctx, task)
 	if err != nil {
 		return -1, err
 	}
-	err = s.cache.InsertingCache(ctx, task)
+	s.publish(ctx, s.log.With(slog.String("op", "service.CreateTask")), model.TaskEvent{
+		Type: model.TaskEventCreated, TaskID: created.ID, ProjectID: created.ProjectID, Task: &created,
+	})
+	err = s.cache.InsertingCache(ctx, created)
 	if err != nil {
-		return taskID, fmt.Errorf("cache insertion failed: %w", err)
+		return created.ID, fmt.Errorf("cache insertion failed: %w", err)
 	}
-	return taskID, nil
+	return created.ID, nil
 }
 
 func (s *Service) AddUser(ctx context.Context, userID int, taskID int) error {
@@ -51,109 +63,112 @@ func (s *Service) AddUser(ctx context.Context, userID int, taskID int) error {
 	if err != nil {
 		return err
 	}
+	s.publishTask(ctx, s.log.With(slog.String("op", "service.AddUser")), model.TaskEventAssigned, taskID, userID)
 
-	msg := model.NotificationMessage{
-		Event:     "add_user",
+	return s.notify(ctx, model.NotificationMessage{
+		Event:     model.EventAddUser,
 		Timestamp: time.Now().UTC(),
 		TaskID:    taskID,
 		UserID:    userID,
-	}
+	})
+}
 
-	msgJSON, err := json.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+// AllUsersWorkTask страница исполнителей задачи. Исполнители упорядочены по ID, другой сортировки нет.
+func (s *Service) AllUsersWorkTask(ctx context.Context, taskID int, page model.PageRequest) (model.Page[model.User], error) {
+	if page.Sort != "" {
+		return model.Page[model.User]{}, model.Invalid("unknown sort field %q", page.Sort)
 	}
-
-	err = s.producer.Produce(msgJSON, "notification")
-	if err != nil {
-		return fmt.Errorf("failed to produce message: %w", err)
+	if page.After != nil && page.After.Sort != "" {
+		return model.Page[model.User]{}, model.Invalid("cursor does not match the requested sort order")
 	}
-	return nil
-}
-
-func (s *Service) AllUsersWorkTask(ctx context.Context, taskID int) ([]model.User, error) {
-	return s.repo.GetAllUsersWorkTask(ctx, taskID)
+	return s.repo.GetAllUsersWorkTask(ctx, taskID, page)
 }
 
+// AllTasks все задачи пользователя одним списком, для устаревшего маршрута без пагинации
 func (s *Service) AllTasks(ctx context.Context, userID int) ([]model.Task, error) {
-	return s.repo.GetAllTasks(ctx, userID)
+	page, err := s.repo.ListTasks(ctx, model.TaskFilter{UserID: userID}, model.PageRequest{})
+	return page.Items, err
 }
 
 func (s *Service) TaskShortDeadline(ctx context.Context, userID int) ([]model.Task, error) {
 	return s.repo.TaskShortDeadline(ctx, userID)
 }
 
-func (s *Service) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int) error {
+// TaskUpdateStatus меняет статус задачи. version - версия, которую видел клиент (If-Match),
+// 0 - без проверки. Сначала меняется база, затем кэш: при ошибке кэша запись удаляется,
+// чтобы следующее чтение взяло задачу из базы.
+func (s *Service) TaskUpdateStatus(ctx context.Context, newStatus string, taskID int, version int) (model.Task, error) {
 	const op = "service.TaskUpdateStatus"
 	log := s.log.With(slog.String("op", op))
-	err := s.cache.UpdateTaskStatusInCache(ctx, taskID, newStatus)
-	if err != nil {
-		return err
+	if !model.ValidStatus(newStatus) {
+		return model.Task{}, model.Invalid("unknown task status %q", newStatus)
 	}
-	err = s.repo.TaskUpdateStatus(ctx, newStatus, taskID)
+	task, err := s.repo.TaskUpdateStatus(ctx, newStatus, taskID, version)
 	if err != nil {
-		return err
+		return model.Task{}, err
 	}
+	s.refreshCache(ctx, log, task)
 
 	users, err := s.repo.UserByID(ctx, taskID)
 	if err != nil {
-		return err
+		return task, err
 	}
+	s.publish(ctx, log, model.TaskEvent{
+		Type: model.TaskEventStatusChanged, TaskID: taskID, ProjectID: task.ProjectID, Task: &task, Assignees: users,
+	})
 
 	for _, user := range users {
-		msg := model.NotificationMessage{
-			Event:        "change_status",
+		err := s.notify(ctx, model.NotificationMessage{
+			Event:        model.EventChangeStatus,
 			Timestamp:    time.Now().UTC(),
 			TaskID:       taskID,
 			UserID:       user,
 			ChangeStatus: newStatus,
-		}
-
-		msgJSON, err := json.Marshal(msg)
+		})
 		if err != nil {
-			return fmt.Errorf("failed to marshal message: %w", err)
-		}
-		err = s.producer.Produce(msgJSON, "notification")
-		if err != nil {
-			log.Error("failed to produce message", sl.Err(err))
+			log.Error("failed to send notification", sl.Err(err))
 		}
 	}
-	return nil
+	return task, nil
 }
 
-func (s *Service) DeleteTask(ctx context.Context, taskID int) error {
-	const op = "service.DeleteTask"
-	log := s.log.With(slog.String("op", op))
-
-	err := s.cache.DeleteTaskFromCache(ctx, taskID)
-	if err != nil {
-		return err
+// refreshCache кладёт в кэш задачу после изменения в базе. Если записать не удалось,
+// устаревшая запись удаляется, иначе ETag из кэша не совпал бы с версией в базе.
+func (s *Service) refreshCache(ctx context.Context, log *slog.Logger, task model.Task) {
+	if err := s.cache.InsertingCache(ctx, task); err != nil {
+		log.Warn("cache insertion failed", sl.Err(err))
+		s.invalidateCache(ctx, log, task.ID)
 	}
+}
 
-	if err := s.repo.DeleteTask(ctx, taskID); err != nil {
-		return err
+func (s *Service) invalidateCache(ctx context.Context, log *slog.Logger, taskID int) {
+	if err := s.cache.DeleteTaskFromCache(ctx, taskID); err != nil {
+		log.Error("failed to delete task from cache", slog.Int("task_id", taskID), sl.Err(err))
 	}
+}
 
-	users, err := s.repo.UserByID(ctx, taskID)
+// DeleteTask удаляет задачу; version - версия из If-Match, 0 - без проверки
+func (s *Service) DeleteTask(ctx context.Context, taskID int, version int) error {
+	const op = "service.DeleteTask"
+	log := s.log.With(slog.String("op", op))
+
+	// исполнители возвращаются из удаления: после него назначений задачи уже нет
+	projectID, users, err := s.repo.DeleteTask(ctx, taskID, version)
 	if err != nil {
 		return err
 	}
+	s.invalidateCache(ctx, log, taskID)
+	s.publish(ctx, log, model.TaskEvent{Type: model.TaskEventDeleted, TaskID: taskID, ProjectID: projectID, Assignees: users})
 
 	for _, user := range users {
-		msg := model.NotificationMessage{
-			Event:     "delete_task",
+		err := s.notify(ctx, model.NotificationMessage{
+			Event:     model.EventDeleteTask,
 			Timestamp: time.Now().UTC(),
 			TaskID:    taskID,
 			UserID:    user,
-		}
-
-		msgJSON, err := json.Marshal(msg)
+		})
 		if err != nil {
-			return fmt.Errorf("failed to marshal message: %w", err)
-		}
-		err = s.producer.Produce(msgJSON, "notification")
-		if err != nil {
-			log.Error("failed to produce message", sl.Err(err))
+			log.Error("failed to send notification", sl.Err(err))
 		}
 	}
 	return nil
@@ -166,41 +181,37 @@ func (s *Service) RemoveUserFromTask(ctx context.Context, userID int, taskID int
 		return err
 	}
 	log.Info("successful removal of the user from the database")
+	s.publishTask(ctx, log, model.TaskEventUnassigned, taskID, userID)
 
-	msg := model.NotificationMessage{
-		Event:     "remove_user",
+	err = s.notify(ctx, model.NotificationMessage{
+		Event:     model.EventRemoveUser,
 		Timestamp: time.Now().UTC(),
 		TaskID:    taskID,
 		UserID:    userID,
-	}
-
-	msgJSON, err := json.Marshal(msg)
-	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
-	}
-
-	err = s.producer.Produce(msgJSON, "notifications")
+	})
 	if err != nil {
-		return fmt.Errorf("failed to produce message: %w", err)
+		return err
 	}
 	log.Info("successfully sending a message to kafka")
 	return nil
 }
 
 func (s *Service) TaskByID(ctx context.Context, taskID int) (model.Task, error) {
+	log := s.log.With(slog.String("op", "service.TaskByID"))
 	task, err := s.cache.GetTaskFromCache(ctx, taskID)
 	if err == nil {
 		return task, nil
 	}
-	if errors.Is(err, redis.Nil) {
-		task, err = s.repo.TaskByID(ctx, taskID)
-		if err != nil {
-			return model.Task{}, err
-		}
-		err = s.cache.InsertingCache(ctx, task)
-		if err != nil {
-			return task, fmt.Errorf("cache insertion failed: %w", err)
-		}
+	// недоступный или повреждённый кэш не мешает прочитать задачу из базы
+	if !errors.Is(err, redis.Nil) {
+		log.Warn("failed to get task from cache", sl.Err(err))
+	}
+	task, err = s.repo.TaskByID(ctx, taskID)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if err := s.cache.InsertingCache(ctx, task); err != nil {
+		log.Warn("cache insertion failed", sl.Err(err))
 	}
 	return task, nil
 }
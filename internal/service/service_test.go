@@ -21,6 +21,8 @@ type mocks struct {
 
 func TestService_CreateTask(t *testing.T) {
 	location, _ := time.LoadLocation("Europe/Moscow")
+	created := model.Task{ID: 5, NameTask: "task123", Description: "opisanie", Status: model.TaskStatusTodo,
+		Priority: model.PriorityMedium, Version: 1, CreatedAt: time.Now().UTC(), UpdatedAt: time.Now().UTC()}
 
 	tests := []struct {
 		name     string
@@ -31,14 +33,15 @@ func TestService_CreateTask(t *testing.T) {
 	}{
 		{
 			name:     "positive base test",
-			input:    model.Task{NameTask: "task123", Description: "opisanie", Deadline: time.Date(2025, 3, 30, 10, 0, 0, 0, location)},
-			expected: 0,
+			input:    model.Task{NameTask: "task123", Description: "opisanie", Deadline: time.Now().In(location).Add(24 * time.Hour)},
+			expected: 5,
 			mock: func() mocks {
 				storageMock := mockery.NewStorageRepository(t)
-				storageMock.On("CreateNewTask", mock.Anything, mock.Anything).Return(0, nil)
+				storageMock.On("CreateNewTask", mock.Anything, mock.Anything).Return(created, nil)
 
+				// в кэш попадает строка из базы, а не запрос
 				cacheMock := mockery.NewCacheRepository(t)
-				cacheMock.On("InsertingCache", mock.Anything, mock.Anything).Return(nil)
+				cacheMock.On("InsertingCache", mock.Anything, created).Return(nil)
 
 				return mocks{repositoryStorage: storageMock, repositoryCache: cacheMock}
 			},
@@ -59,12 +62,12 @@ func TestService_CreateTask(t *testing.T) {
 		{name: "negative test 2", input: model.Task{
 			NameTask:    "task123",
 			Description: "opisanie",
-			Deadline:    time.Date(2025, 2, 30, 10, 0, 0, 0, location)},
+			Deadline:    time.Date(time.Now().Year()+1, 2, 30, 10, 0, 0, 0, location)},
 			expected: -1,
 			wantErr:  true,
 			mock: func() mocks {
 				storageMock := mockery.NewStorageRepository(t)
-				storageMock.On("CreateNewTask", mock.Anything, mock.Anything).Return(0, errors.New("incorrect date"))
+				storageMock.On("CreateNewTask", mock.Anything, mock.Anything).Return(model.Task{}, errors.New("incorrect date"))
 				cacheMock := mockery.NewCacheRepository(t)
 
 				return mocks{repositoryStorage: storageMock, repositoryCache: cacheMock}
@@ -84,12 +87,64 @@ func TestService_CreateTask(t *testing.T) {
 				cache: m.repositoryCache,
 			}
 
-			_, err := ct.CreateTask(context.Background(), tt.input)
+			taskID, err := ct.CreateTask(context.Background(), tt.input)
 			if tt.wantErr != (err != nil) {
 				t.Errorf("unexpected error: %v", err)
 				return
 			}
+			if taskID != tt.expected {
+				t.Errorf("expected task ID %d, got %d", tt.expected, taskID)
+			}
+
+		})
+	}
+}
+
+func TestService_TaskUpdateStatus(t *testing.T) {
+	updated := model.Task{ID: 3, Status: model.TaskStatusDone, Version: 5}
+
+	tests := []struct {
+		name     string
+		version  int
+		repoErr  error
+		cacheErr error
+		wantErr  error
+	}{
+		{name: "database first, then cache", version: 4},
+		{name: "without If-Match"},
+		{name: "stale version leaves cache untouched", version: 3, repoErr: model.PreconditionFailed("task %d has been modified, current version is %d", 3, 4), wantErr: model.ErrPreconditionFailed},
+		{name: "failed cache write drops the entry", version: 4, cacheErr: errors.New("redis is down")},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			cacheMock := mockery.NewCacheRepository(t)
+			brokerMock := mockery.NewBroker(t)
 
+			if tt.repoErr != nil {
+				storageMock.On("TaskUpdateStatus", mock.Anything, model.TaskStatusDone, 3, tt.version).Return(model.Task{}, tt.repoErr)
+			} else {
+				update := storageMock.On("TaskUpdateStatus", mock.Anything, model.TaskStatusDone, 3, tt.version).Return(updated, nil)
+				cacheMock.On("InsertingCache", mock.Anything, updated).Return(tt.cacheErr).NotBefore(update)
+				if tt.cacheErr != nil {
+					cacheMock.On("DeleteTaskFromCache", mock.Anything, 3).Return(nil)
+				}
+				storageMock.On("UserByID", mock.Anything, 3).Return([]int(nil), nil)
+			}
+
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock, producer: brokerMock}
+			task, err := s.TaskUpdateStatus(context.Background(), model.TaskStatusDone, 3, tt.version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err != nil || task.Version != updated.Version {
+				t.Errorf("unexpected result: %+v, %v", task, err)
+			}
 		})
 	}
 }
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"Tasks/internal/model"
+)
+
+func (s *Service) CreateSprint(ctx context.Context, sprint model.Sprint) (int, error) {
+	if !sprint.EndDate.After(sprint.StartDate) {
+		return -1, model.Invalid("sprint end date must be after its start date")
+	}
+	return s.repo.CreateSprint(ctx, sprint)
+}
+
+func (s *Service) AddTaskToSprint(ctx context.Context, sprintID int, taskID int) error {
+	sprint, err := s.repo.SprintByID(ctx, sprintID)
+	if err != nil {
+		return err
+	}
+	if sprint.Status == model.SprintCompleted {
+		return model.Conflict("sprint %d is already completed", sprintID)
+	}
+	return s.repo.AddTaskToSprint(ctx, sprintID, taskID)
+}
+
+// SprintTasks все задачи спринта одним списком, для устаревшего маршрута без пагинации
+func (s *Service) SprintTasks(ctx context.Context, sprintID int) ([]model.Task, error) {
+	page, err := s.repo.ListTasks(ctx, model.TaskFilter{SprintID: sprintID}, model.PageRequest{})
+	return page.Items, err
+}
+
+func (s *Service) StartSprint(ctx context.Context, sprintID int) error {
+	return s.repo.StartSprint(ctx, sprintID)
+}
+
+// CompleteSprint завершает спринт и переносит незавершённые задачи в следующий.
+// Если nextSprintID равен 0, берётся ближайший запланированный спринт.
+func (s *Service) CompleteSprint(ctx context.Context, sprintID int, nextSprintID int) ([]int, error) {
+	const op = "service.CompleteSprint"
+	log := s.log.With(slog.String("op", op))
+
+	if nextSprintID != 0 {
+		if nextSprintID == sprintID {
+			return nil, model.Invalid("unfinished tasks cannot be carried over into the same sprint")
+		}
+		next, err := s.repo.SprintByID(ctx, nextSprintID)
+		if err != nil {
+			return nil, err
+		}
+		if next.Status == model.SprintCompleted {
+			return nil, model.Conflict("sprint %d is already completed", nextSprintID)
+		}
+	}
+
+	carried, err := s.repo.CompleteSprint(ctx, sprintID, nextSprintID)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("sprint completed", slog.Int("sprint_id", sprintID), slog.Int("carried_over", len(carried)))
+	return carried, nil
+}
+
+func (s *Service) CreateMilestone(ctx context.Context, milestone model.Milestone) (int, error) {
+	if !milestone.EndDate.After(milestone.StartDate) {
+		return -1, model.Invalid("milestone end date must be after its start date")
+	}
+	return s.repo.CreateMilestone(ctx, milestone)
+}
+
+func (s *Service) SetTaskMilestone(ctx context.Context, taskID int, milestoneID int) error {
+	if err := s.repo.SetTaskMilestone(ctx, taskID, milestoneID); err != nil {
+		return err
+	}
+	// привязка к вехе меняет версию задачи
+	log := s.log.With(slog.String("op", "service.SetTaskMilestone"))
+	s.invalidateCache(ctx, log, taskID)
+	s.publishTask(ctx, log, model.TaskEventUpdated, taskID, 0)
+	return nil
+}
+
+func (s *Service) TaskMilestoneOverrun(ctx context.Context, userID int) ([]model.Task, error) {
+	return s.repo.TaskMilestoneOverrun(ctx, userID)
+}
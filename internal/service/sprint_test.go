@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_CompleteSprint(t *testing.T) {
+	tests := []struct {
+		name         string
+		sprintID     int
+		nextSprintID int
+		expected     []int
+		wantErr      bool
+		mock         func() *mockery.StorageRepository
+	}{
+		{
+			name:     "carry over into the nearest planned sprint",
+			sprintID: 1,
+			expected: []int{3, 4},
+			mock: func() *mockery.StorageRepository {
+				storageMock := mockery.NewStorageRepository(t)
+				storageMock.On("CompleteSprint", mock.Anything, 1, 0).Return([]int{3, 4}, nil)
+				return storageMock
+			},
+		},
+		{
+			name:         "carry over into the explicit sprint",
+			sprintID:     1,
+			nextSprintID: 2,
+			expected:     []int{3},
+			mock: func() *mockery.StorageRepository {
+				storageMock := mockery.NewStorageRepository(t)
+				storageMock.On("SprintByID", mock.Anything, 2).Return(model.Sprint{ID: 2, Status: model.SprintPlanned}, nil)
+				storageMock.On("CompleteSprint", mock.Anything, 1, 2).Return([]int{3}, nil)
+				return storageMock
+			},
+		},
+		{
+			name:     "no sprint to carry over unfinished tasks",
+			sprintID: 1,
+			wantErr:  true,
+			mock: func() *mockery.StorageRepository {
+				storageMock := mockery.NewStorageRepository(t)
+				storageMock.On("CompleteSprint", mock.Anything, 1, 0).
+					Return(nil, model.Conflict("sprint %d has unfinished tasks and no next sprint", 1))
+				return storageMock
+			},
+		},
+		{
+			name:         "next sprint is the same sprint",
+			sprintID:     1,
+			nextSprintID: 1,
+			wantErr:      true,
+			mock: func() *mockery.StorageRepository {
+				return mockery.NewStorageRepository(t)
+			},
+		},
+		{
+			name:         "next sprint is already completed",
+			sprintID:     1,
+			nextSprintID: 2,
+			wantErr:      true,
+			mock: func() *mockery.StorageRepository {
+				storageMock := mockery.NewStorageRepository(t)
+				storageMock.On("SprintByID", mock.Anything, 2).Return(model.Sprint{ID: 2, Status: model.SprintCompleted}, nil)
+				return storageMock
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			ct := Service{
+				log:  slogdiscard.NewDiscardLogger(),
+				repo: tt.mock(),
+			}
+
+			carried, err := ct.CompleteSprint(context.Background(), tt.sprintID, tt.nextSprintID)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			assert.Equal(t, tt.expected, carried)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"Tasks/internal/model"
+)
+
+// TaskCounts незавершённые задачи по статусам и число просроченных на текущий момент
+func (s *Service) TaskCounts(ctx context.Context) (model.TaskCounts, error) {
+	return s.repo.TaskCounts(ctx, time.Now())
+}
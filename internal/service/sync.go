@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"Tasks/internal/model"
+)
+
+// SyncChanges изменения задач, видимых пользователю, после номера since; since = 0 - все видимые задачи
+func (s *Service) SyncChanges(ctx context.Context, callerID int, since int64, limit int) (model.SyncChanges, error) {
+	if limit <= 0 || limit > model.MaxSyncLimit {
+		limit = model.DefaultSyncLimit
+	}
+	scope, err := s.repo.EventScope(ctx, callerID)
+	if err != nil {
+		return model.SyncChanges{}, err
+	}
+	return s.repo.SyncChanges(ctx, scope, since, limit)
+}
+
+// ApplySyncMutations применяет изменения, накопленные клиентом без связи, по порядку теми же операциями,
+// что и отдельные запросы API. Неудача одного изменения не отменяет остальные. Конфликт - состояние
+// на сервере разошлось с тем, что видел клиент: задача изменена или удалена, исполнитель уже назначен
+// или уже снят; в результате возвращается текущая задача.
+func (s *Service) ApplySyncMutations(ctx context.Context, mutations []model.SyncMutation) ([]model.SyncResult, error) {
+	const op = "service.ApplySyncMutations"
+	log := s.log.With(slog.String("op", op))
+	if len(mutations) > model.MaxSyncMutations {
+		return nil, model.Invalid("too many mutations, at most %d are allowed", model.MaxSyncMutations)
+	}
+
+	// созданные задачи по ID изменения create_task: на них ссылаются следующие изменения
+	created := make(map[string]int)
+	results := make([]model.SyncResult, 0, len(mutations))
+	for _, m := range mutations {
+		res := s.applyMutation(ctx, m, created)
+		if res.Err != nil {
+			log.Info("sync mutation not applied", slog.String("mutation_id", m.ID), slog.String("status", res.Status),
+				slog.String("error", res.Err.Error()))
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (s *Service) applyMutation(ctx context.Context, m model.SyncMutation, created map[string]int) model.SyncResult {
+	res := model.SyncResult{MutationID: m.ID, TaskID: m.TaskID, Status: model.SyncApplied}
+	if m.Type == model.MutationCreateTask {
+		taskID, err := s.CreateTask(ctx, m.Task)
+		// ошибка кэша после записи в базу не отменяет создание
+		if taskID <= 0 {
+			res.Status, res.Err = model.SyncFailed, err
+			return res
+		}
+		created[m.ID] = taskID
+		res.TaskID, res.Version = taskID, 1
+		return res
+	}
+
+	if m.TaskRef != "" {
+		taskID, ok := created[m.TaskRef]
+		if !ok {
+			res.Status, res.Err = model.SyncFailed, model.Invalid("task_ref %q does not refer to a task created earlier in this request", m.TaskRef)
+			return res
+		}
+		m.TaskID, res.TaskID = taskID, taskID
+	}
+	if m.TaskID <= 0 {
+		res.Status, res.Err = model.SyncFailed, model.Invalid("task_id or task_ref is required")
+		return res
+	}
+
+	var err error
+	switch m.Type {
+	case model.MutationSetStatus:
+		var task model.Task
+		task, err = s.TaskUpdateStatus(ctx, m.Status, m.TaskID, m.BaseVersion)
+		res.Version = task.Version
+	case model.MutationAssign, model.MutationUnassign:
+		if m.UserID <= 0 {
+			res.Status, res.Err = model.SyncFailed, model.Invalid("user_id is required")
+			return res
+		}
+		if m.Type == model.MutationAssign {
+			err = s.AddUser(ctx, m.UserID, m.TaskID)
+		} else {
+			err = s.RemoveUserFromTask(ctx, m.UserID, m.TaskID)
+		}
+	case model.MutationDeleteTask:
+		err = s.DeleteTask(ctx, m.TaskID, m.BaseVersion)
+	default:
+		err = model.Invalid("unknown mutation type %q", m.Type)
+	}
+	if err == nil {
+		return res
+	}
+	// статус уже записан в базу, ошибка после записи не отменяет изменение
+	if res.Version > 0 {
+		return res
+	}
+	res.Status, res.Err = model.SyncFailed, err
+	if errors.Is(err, model.ErrPreconditionFailed) || errors.Is(err, model.ErrConflict) || errors.Is(err, model.ErrNotFound) {
+		res.Status = model.SyncConflict
+		if task, err := s.repo.TaskByID(ctx, m.TaskID); err == nil {
+			res.Task = &task
+		}
+	}
+	return res
+}
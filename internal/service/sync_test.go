@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_ApplySyncMutations(t *testing.T) {
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("CreateNewTask", mock.Anything, mock.MatchedBy(func(task model.Task) bool {
+		return task.NameTask == "offline task"
+	})).Return(model.Task{ID: 21, Status: model.TaskStatusTodo, Version: 1}, nil).Once()
+	storageMock.On("TaskUpdateStatus", mock.Anything, model.TaskStatusInProgress, 21, 1).
+		Return(model.Task{ID: 21, Status: model.TaskStatusInProgress, Version: 2}, nil).Once()
+	storageMock.On("UserByID", mock.Anything, 21).Return([]int{}, nil).Once()
+	storageMock.On("TaskUpdateStatus", mock.Anything, model.TaskStatusDone, 7, 3).
+		Return(model.Task{}, model.PreconditionFailed("task 7 has been modified, current version is 4")).Once()
+	storageMock.On("TaskByID", mock.Anything, 7).Return(model.Task{ID: 7, Status: model.TaskStatusTodo, Version: 4}, nil).Once()
+	storageMock.On("DeleteTask", mock.Anything, 8, 0).Return(0, nil, model.NotFound("task with ID %d not found", 8)).Once()
+	storageMock.On("TaskByID", mock.Anything, 8).Return(model.Task{}, model.NotFound("task with ID %d not found", 8)).Once()
+
+	cacheMock := mockery.NewCacheRepository(t)
+	cacheMock.On("InsertingCache", mock.Anything, mock.Anything).Return(nil)
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock, cache: cacheMock}
+
+	results, err := s.ApplySyncMutations(context.Background(), []model.SyncMutation{
+		{ID: "m1", Type: model.MutationCreateTask, Task: model.Task{NameTask: "offline task", Deadline: time.Now().Add(time.Hour)}},
+		{ID: "m2", Type: model.MutationSetStatus, TaskRef: "m1", Status: model.TaskStatusInProgress, BaseVersion: 1},
+		{ID: "m3", Type: model.MutationSetStatus, TaskID: 7, Status: model.TaskStatusDone, BaseVersion: 3},
+		{ID: "m4", Type: model.MutationDeleteTask, TaskID: 8},
+		{ID: "m5", Type: model.MutationAssign, TaskRef: "m9", UserID: 3},
+		{ID: "m6", Type: model.MutationSetStatus, TaskID: 7, Status: "archived"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	require.Equal(t, model.SyncResult{MutationID: "m1", TaskID: 21, Status: model.SyncApplied, Version: 1}, results[0])
+	require.Equal(t, model.SyncResult{MutationID: "m2", TaskID: 21, Status: model.SyncApplied, Version: 2}, results[1])
+
+	// версия на сервере новее: клиент получает текущую задачу
+	require.Equal(t, model.SyncConflict, results[2].Status)
+	require.True(t, errors.Is(results[2].Err, model.ErrPreconditionFailed))
+	require.Equal(t, 4, results[2].Task.Version)
+
+	// задача удалена на сервере
+	require.Equal(t, model.SyncConflict, results[3].Status)
+	require.Nil(t, results[3].Task)
+
+	for _, res := range results[4:] {
+		require.Equal(t, model.SyncFailed, res.Status, res.MutationID)
+		require.True(t, errors.Is(res.Err, model.ErrValidation), "unexpected error: %v", res.Err)
+	}
+}
+
+func TestService_ApplySyncMutations_TooMany(t *testing.T) {
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: mockery.NewStorageRepository(t)}
+
+	_, err := s.ApplySyncMutations(context.Background(), make([]model.SyncMutation, model.MaxSyncMutations+1))
+	require.True(t, errors.Is(err, model.ErrValidation), "unexpected error: %v", err)
+}
+
+func TestService_SyncChanges(t *testing.T) {
+	scope := model.EventScope{UserID: 5, Users: []int{5, 6}}
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("EventScope", mock.Anything, 5).Return(scope, nil)
+	storageMock.On("SyncChanges", mock.Anything, scope, int64(40), model.DefaultSyncLimit).
+		Return(model.SyncChanges{Seq: 52}, nil).Twice()
+	storageMock.On("SyncChanges", mock.Anything, scope, int64(40), 10).Return(model.SyncChanges{Seq: 45, More: true}, nil).Once()
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+	for _, limit := range []int{0, model.MaxSyncLimit + 1} {
+		changes, err := s.SyncChanges(context.Background(), 5, 40, limit)
+		require.NoError(t, err)
+		require.Equal(t, int64(52), changes.Seq)
+	}
+	changes, err := s.SyncChanges(context.Background(), 5, 40, 10)
+	require.NoError(t, err)
+	require.True(t, changes.More)
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"Tasks/internal/lib/query"
+	"Tasks/internal/model"
+)
+
+// CreateView сохраняет представление пользователя view.OwnerID
+func (s *Service) CreateView(ctx context.Context, view model.View) (int, error) {
+	if err := s.prepareView(ctx, &view); err != nil {
+		return -1, err
+	}
+	return s.repo.CreateView(ctx, view)
+}
+
+// UpdateView изменяет представление. Менять представление может только его владелец callerID.
+func (s *Service) UpdateView(ctx context.Context, callerID int, view model.View) error {
+	existing, err := s.View(ctx, callerID, view.ID)
+	if err != nil {
+		return err
+	}
+	if existing.OwnerID != callerID {
+		return model.Forbidden("only the owner can change view %d", view.ID)
+	}
+	view.OwnerID = callerID
+	if err := s.prepareView(ctx, &view); err != nil {
+		return err
+	}
+	return s.repo.UpdateView(ctx, view)
+}
+
+// DeleteView удаляет представление владельца callerID
+func (s *Service) DeleteView(ctx context.Context, callerID int, viewID int) error {
+	view, err := s.View(ctx, callerID, viewID)
+	if err != nil {
+		return err
+	}
+	if view.OwnerID != callerID {
+		return model.Forbidden("only the owner can change view %d", viewID)
+	}
+	return s.repo.DeleteView(ctx, viewID)
+}
+
+// View возвращает представление, доступное пользователю callerID: его собственное или открытое
+// для проекта, в котором он участвует. О чужих представлениях сообщается как о несуществующих.
+func (s *Service) View(ctx context.Context, callerID int, viewID int) (model.View, error) {
+	view, err := s.repo.ViewByID(ctx, viewID)
+	if err != nil {
+		return view, err
+	}
+	if view.OwnerID == callerID {
+		return view, nil
+	}
+	if view.ProjectID != 0 {
+		member, err := s.repo.ProjectMember(ctx, view.ProjectID, callerID)
+		if err != nil {
+			return view, err
+		}
+		if member {
+			return view, nil
+		}
+	}
+	return model.View{}, model.NotFound("view with ID %d not found", viewID)
+}
+
+func (s *Service) Views(ctx context.Context, callerID int) ([]model.View, error) {
+	return s.repo.Views(ctx, callerID)
+}
+
+// ViewTasks выполняет представление для пользователя callerID: me в условии - это он,
+// в выборку попадают только видимые ему задачи. Сортировку задаёт представление.
+func (s *Service) ViewTasks(ctx context.Context, callerID int, viewID int, page model.PageRequest) (model.View, model.Page[model.Task], error) {
+	view, err := s.View(ctx, callerID, viewID)
+	if err != nil {
+		return view, model.Page[model.Task]{}, err
+	}
+	q, err := query.View(view, query.Env{Now: time.Now(), UserID: callerID})
+	if err != nil {
+		return view, model.Page[model.Task]{}, err
+	}
+	page.Sort, page.Desc = q.Sort, q.Desc
+	if err := validatePage(&page); err != nil {
+		return view, model.Page[model.Task]{}, err
+	}
+	tasks, err := s.repo.ListTasks(ctx, model.TaskFilter{VisibleTo: callerID, Query: &q}, page)
+	return view, tasks, err
+}
+
+// SubscribeView подписывает пользователя на уведомления о задачах, попавших в представление
+func (s *Service) SubscribeView(ctx context.Context, callerID int, viewID int) error {
+	if _, err := s.View(ctx, callerID, viewID); err != nil {
+		return err
+	}
+	return s.repo.SubscribeView(ctx, viewID, callerID)
+}
+
+func (s *Service) UnsubscribeView(ctx context.Context, callerID int, viewID int) error {
+	return s.repo.UnsubscribeView(ctx, viewID, callerID)
+}
+
+// prepareView проверяет условие, сортировку, колонки и проект представления и заполняет колонки по умолчанию.
+// Открыть представление проекту может только его участник.
+func (s *Service) prepareView(ctx context.Context, view *model.View) error {
+	q, err := query.Parse(view.Query, query.Env{Now: time.Now(), UserID: view.OwnerID})
+	if err != nil {
+		return err
+	}
+	if view.Sort != "" {
+		if !model.ValidSortField(view.Sort) {
+			return model.Invalid("unknown sort field %q", view.Sort)
+		}
+		if q.Sort != "" {
+			return model.Invalid("view sort cannot be combined with ORDER BY in the query")
+		}
+	}
+
+	if len(view.Columns) == 0 {
+		view.Columns = model.DefaultViewColumns
+	}
+	for _, column := range view.Columns {
+		if !model.ValidViewColumn(column) {
+			return model.Invalid("unknown view column %q", column)
+		}
+	}
+
+	if view.ProjectID != 0 {
+		if _, err := s.repo.ProjectByID(ctx, view.ProjectID); err != nil {
+			return err
+		}
+		member, err := s.repo.ProjectMember(ctx, view.ProjectID, view.OwnerID)
+		if err != nil {
+			return err
+		}
+		if !member {
+			return model.Forbidden("user with ID %d is not a member of project %d", view.OwnerID, view.ProjectID)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/lib/query"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_CreateView(t *testing.T) {
+	member, stranger := true, false
+	tests := []struct {
+		name    string
+		view    model.View
+		member  *bool
+		want    model.View
+		wantErr error
+	}{
+		{
+			name: "default columns",
+			view: model.View{OwnerID: 5, Name: "mine", Query: "assignee = me AND status != done"},
+			want: model.View{OwnerID: 5, Name: "mine", Query: "assignee = me AND status != done", Columns: model.DefaultViewColumns},
+		},
+		{
+			name:   "shared with a project by its member",
+			view:   model.View{OwnerID: 5, ProjectID: 2, Name: "board", Sort: model.SortPriority, Desc: true, Columns: []string{"title"}},
+			member: &member,
+			want:   model.View{OwnerID: 5, ProjectID: 2, Name: "board", Sort: model.SortPriority, Desc: true, Columns: []string{"title"}},
+		},
+		{
+			name:    "shared with a foreign project",
+			view:    model.View{OwnerID: 5, ProjectID: 2, Name: "board"},
+			member:  &stranger,
+			wantErr: model.ErrForbidden,
+		},
+		{name: "query syntax error", view: model.View{OwnerID: 5, Name: "bad", Query: "status = "}},
+		{name: "unknown column", view: model.View{OwnerID: 5, Name: "bad", Columns: []string{"secret"}}, wantErr: model.ErrValidation},
+		{name: "unknown sort", view: model.View{OwnerID: 5, Name: "bad", Sort: "title"}, wantErr: model.ErrValidation},
+		{
+			name:    "sort and ORDER BY",
+			view:    model.View{OwnerID: 5, Name: "bad", Query: "ORDER BY deadline", Sort: model.SortPriority},
+			wantErr: model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			if tt.member != nil {
+				storageMock.On("ProjectByID", mock.Anything, tt.view.ProjectID).Return(model.Project{ID: tt.view.ProjectID}, nil)
+				storageMock.On("ProjectMember", mock.Anything, tt.view.ProjectID, tt.view.OwnerID).Return(*tt.member, nil)
+			}
+			wantOK := tt.want.Name != ""
+			if wantOK {
+				storageMock.On("CreateView", mock.Anything, tt.want).Return(1, nil)
+			}
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+			_, err := s.CreateView(context.Background(), tt.view)
+			switch {
+			case wantOK:
+				require.NoError(t, err)
+			case tt.wantErr != nil:
+				require.True(t, errors.Is(err, tt.wantErr), "unexpected error: %v", err)
+			default:
+				var qErr *query.Error
+				require.True(t, errors.As(err, &qErr), "unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestService_ViewAccess(t *testing.T) {
+	personal := model.View{ID: 1, OwnerID: 5, Name: "mine"}
+	shared := model.View{ID: 2, OwnerID: 5, ProjectID: 3, Name: "board"}
+
+	tests := []struct {
+		name      string
+		view      model.View
+		callerID  int
+		member    bool
+		readErr   error
+		updateErr error
+	}{
+		{name: "owner", view: shared, callerID: 5},
+		{name: "project member reads a shared view", view: shared, callerID: 6, member: true, updateErr: model.ErrForbidden},
+		{name: "stranger does not see a shared view", view: shared, callerID: 7, readErr: model.ErrNotFound, updateErr: model.ErrNotFound},
+		{name: "personal view of another user", view: personal, callerID: 6, readErr: model.ErrNotFound, updateErr: model.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("ViewByID", mock.Anything, tt.view.ID).Return(tt.view, nil)
+			if tt.callerID != tt.view.OwnerID && tt.view.ProjectID != 0 {
+				storageMock.On("ProjectMember", mock.Anything, tt.view.ProjectID, tt.callerID).Return(tt.member, nil)
+			}
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+			_, err := s.View(context.Background(), tt.callerID, tt.view.ID)
+			if tt.readErr != nil {
+				require.True(t, errors.Is(err, tt.readErr), "unexpected error: %v", err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tt.updateErr == nil {
+				storageMock.On("DeleteView", mock.Anything, tt.view.ID).Return(nil)
+			}
+			err = s.DeleteView(context.Background(), tt.callerID, tt.view.ID)
+			if tt.updateErr != nil {
+				require.True(t, errors.Is(err, tt.updateErr), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_ViewTasks(t *testing.T) {
+	view := model.View{ID: 1, OwnerID: 5, Query: "assignee = me ORDER BY priority DESC"}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("ViewByID", mock.Anything, view.ID).Return(view, nil)
+	storageMock.On("ListTasks", mock.Anything, mock.MatchedBy(func(f model.TaskFilter) bool {
+		cond, ok := f.Query.Where.(model.QueryCond)
+		return f.VisibleTo == 5 && ok && cond.Values[0] == 5
+	}), model.PageRequest{Limit: 10, Sort: model.SortPriority, Desc: true}).Return(model.Page[model.Task]{}, nil)
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+	_, _, err := s.ViewTasks(context.Background(), 5, view.ID, model.PageRequest{Limit: 10})
+	require.NoError(t, err)
+
+	// курсор, выданный для другой сортировки, отклоняется
+	_, _, err = s.ViewTasks(context.Background(), 5, view.ID,
+		model.PageRequest{After: &model.Cursor{Sort: model.SortDeadline, Value: "2025-01-15T10:00:00", ID: 3}})
+	require.True(t, errors.Is(err, model.ErrValidation), "unexpected error: %v", err)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+
+	"Tasks/internal/model"
+)
+
+// CreateWebhook подписывает внешнюю систему на события задач проекта. Без ключа подписи он создаётся
+// случайным и возвращается вместе с вебхуком: потом ключ уже не отдаётся.
+func (s *Service) CreateWebhook(ctx context.Context, callerID int, webhook model.Webhook) (model.Webhook, error) {
+	if err := s.manageWebhooks(ctx, callerID, webhook.ProjectID); err != nil {
+		return webhook, err
+	}
+	if err := prepareWebhook(&webhook); err != nil {
+		return webhook, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = newWebhookSecret()
+	}
+	webhook.CreatedBy = callerID
+	webhook.Active = true
+	id, err := s.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return webhook, err
+	}
+	webhook.ID = id
+	return webhook, nil
+}
+
+// UpdateWebhook изменяет адрес, типы событий, ключ подписи и включение вебхука. Проект не меняется.
+func (s *Service) UpdateWebhook(ctx context.Context, callerID int, webhook model.Webhook) error {
+	existing, err := s.Webhook(ctx, callerID, webhook.ID)
+	if err != nil {
+		return err
+	}
+	webhook.ProjectID = existing.ProjectID
+	if err := prepareWebhook(&webhook); err != nil {
+		return err
+	}
+	return s.repo.UpdateWebhook(ctx, webhook)
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, callerID int, webhookID int) error {
+	if _, err := s.Webhook(ctx, callerID, webhookID); err != nil {
+		return err
+	}
+	return s.repo.DeleteWebhook(ctx, webhookID)
+}
+
+// Webhook возвращает вебхук проекта, которым руководит пользователь callerID
+func (s *Service) Webhook(ctx context.Context, callerID int, webhookID int) (model.Webhook, error) {
+	webhook, err := s.repo.WebhookByID(ctx, webhookID)
+	if err != nil {
+		return webhook, err
+	}
+	if err := s.manageWebhooks(ctx, callerID, webhook.ProjectID); err != nil {
+		return model.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+func (s *Service) Webhooks(ctx context.Context, callerID int, projectID int) ([]model.Webhook, error) {
+	if err := s.manageWebhooks(ctx, callerID, projectID); err != nil {
+		return nil, err
+	}
+	return s.repo.Webhooks(ctx, projectID)
+}
+
+// WebhookDeliveries журнал доставок вебхука, новые первыми
+func (s *Service) WebhookDeliveries(ctx context.Context, callerID int, webhookID int, page model.PageRequest) (model.Page[model.WebhookDelivery], error) {
+	if _, err := s.Webhook(ctx, callerID, webhookID); err != nil {
+		return model.Page[model.WebhookDelivery]{}, err
+	}
+	if page.Sort != "" {
+		return model.Page[model.WebhookDelivery]{}, model.Invalid("unknown sort field %q", page.Sort)
+	}
+	return s.repo.WebhookDeliveries(ctx, webhookID, page)
+}
+
+// ReplayWebhookDelivery повторяет доставку события, например после исправления получателя.
+// Повтор - новая запись журнала; вебхук должен быть включён, иначе доставка будет ждать включения.
+func (s *Service) ReplayWebhookDelivery(ctx context.Context, callerID int, webhookID int, deliveryID int) (int, error) {
+	if _, err := s.Webhook(ctx, callerID, webhookID); err != nil {
+		return -1, err
+	}
+	return s.repo.ReplayWebhookDelivery(ctx, webhookID, deliveryID)
+}
+
+// manageWebhooks вебхуками проекта управляют его руководитель и администраторы: ключ подписи
+// позволяет подделать доставку, а журнал содержит задачи проекта
+func (s *Service) manageWebhooks(ctx context.Context, userID int, projectID int) error {
+	if _, err := s.repo.ProjectByID(ctx, projectID); err != nil {
+		return err
+	}
+	scope, err := s.repo.EventScope(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !scope.Admin && !slices.Contains(scope.Projects, projectID) {
+		return model.Forbidden("only the manager of project %d can manage its webhooks", projectID)
+	}
+	return nil
+}
+
+// prepareWebhook проверяет адрес и типы событий, повторы типов отбрасываются
+func prepareWebhook(webhook *model.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return model.Invalid("webhook URL must be an absolute http or https URL")
+	}
+	events := make([]string, 0, len(webhook.Events))
+	for _, eventType := range webhook.Events {
+		if !model.ValidTaskEventType(eventType) {
+			return model.Invalid("unknown event type %q", eventType)
+		}
+		if !slices.Contains(events, eventType) {
+			events = append(events, eventType)
+		}
+	}
+	webhook.Events = events
+	return nil
+}
+
+func newWebhookSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+func TestService_CreateWebhook(t *testing.T) {
+	manager := model.EventScope{UserID: 5, Projects: []int{2}}
+	tests := []struct {
+		name    string
+		webhook model.Webhook
+		scope   model.EventScope
+		events  []string
+		wantErr error
+	}{
+		{
+			name:    "project manager, all events",
+			webhook: model.Webhook{ProjectID: 2, URL: "https://ci.example.com/hooks/tasks"},
+			scope:   manager,
+			events:  []string{},
+		},
+		{
+			name: "admin, repeated event types",
+			webhook: model.Webhook{ProjectID: 2, URL: "http://reports.internal:8080/events",
+				Events: []string{model.TaskEventDeleted, model.TaskEventCreated, model.TaskEventDeleted}},
+			scope:  model.EventScope{UserID: 1, Admin: true},
+			events: []string{model.TaskEventDeleted, model.TaskEventCreated},
+		},
+		{
+			name:    "not a manager",
+			webhook: model.Webhook{ProjectID: 2, URL: "https://ci.example.com/hooks/tasks"},
+			scope:   model.EventScope{UserID: 6, Users: []int{6, 5}, Projects: []int{3}},
+			wantErr: model.ErrForbidden,
+		},
+		{
+			name:    "relative URL",
+			webhook: model.Webhook{ProjectID: 2, URL: "/hooks/tasks"},
+			scope:   manager,
+			wantErr: model.ErrValidation,
+		},
+		{
+			name:    "unsupported scheme",
+			webhook: model.Webhook{ProjectID: 2, URL: "ftp://ci.example.com/hooks"},
+			scope:   manager,
+			wantErr: model.ErrValidation,
+		},
+		{
+			name:    "unknown event type",
+			webhook: model.Webhook{ProjectID: 2, URL: "https://ci.example.com/hooks/tasks", Events: []string{"task_archived"}},
+			scope:   manager,
+			wantErr: model.ErrValidation,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("ProjectByID", mock.Anything, 2).Return(model.Project{ID: 2, ManagerID: 5}, nil)
+			storageMock.On("EventScope", mock.Anything, tt.scope.UserID).Return(tt.scope, nil)
+			if tt.wantErr == nil {
+				storageMock.On("CreateWebhook", mock.Anything, mock.MatchedBy(func(w model.Webhook) bool {
+					return w.URL == tt.webhook.URL && w.CreatedBy == tt.scope.UserID && w.Active &&
+						len(w.Secret) == 64 && slices.Equal(tt.events, w.Events)
+				})).Return(8, nil)
+			}
+			s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+			webhook, err := s.CreateWebhook(context.Background(), tt.scope.UserID, tt.webhook)
+			if tt.wantErr != nil {
+				require.True(t, errors.Is(err, tt.wantErr), "unexpected error: %v", err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, 8, webhook.ID)
+			require.NotEmpty(t, webhook.Secret)
+		})
+	}
+}
+
+func TestService_ReplayWebhookDelivery(t *testing.T) {
+	webhook := model.Webhook{ID: 4, ProjectID: 2}
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("WebhookByID", mock.Anything, webhook.ID).Return(webhook, nil)
+	storageMock.On("ProjectByID", mock.Anything, 2).Return(model.Project{ID: 2, ManagerID: 5}, nil)
+	storageMock.On("EventScope", mock.Anything, 5).Return(model.EventScope{UserID: 5, Projects: []int{2}}, nil)
+	storageMock.On("EventScope", mock.Anything, 6).Return(model.EventScope{UserID: 6}, nil)
+	storageMock.On("ReplayWebhookDelivery", mock.Anything, webhook.ID, 31).Return(32, nil).Once()
+	s := Service{log: slogdiscard.NewDiscardLogger(), repo: storageMock}
+
+	replayID, err := s.ReplayWebhookDelivery(context.Background(), 5, webhook.ID, 31)
+	require.NoError(t, err)
+	require.Equal(t, 32, replayID)
+
+	// журнал и повтор доступны только руководителю проекта
+	_, err = s.ReplayWebhookDelivery(context.Background(), 6, webhook.ID, 31)
+	require.True(t, errors.Is(err, model.ErrForbidden), "unexpected error: %v", err)
+}
@@ -0,0 +1,50 @@
+package webhook
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+var errAddressNotAllowed = errors.New("address is not allowed")
+
+// blockedPrefixes сети, не покрытые методами netip.Addr: "этот" хост, CGNAT и сеть для тестов производительности
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// allowedAddress false для адресов, через которые вебхук достал бы внутренние сервисы: loopback,
+// частные сети, link-local (в том числе метаданные облака 169.254.169.254), multicast и неуказанный адрес
+func allowedAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialControl проверяет адрес, к которому подключается клиент, после разрешения имени
+func dialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !allowedAddress(ip) {
+		return fmt.Errorf("%w: %s", errAddressNotAllowed, ip)
+	}
+	return nil
+}
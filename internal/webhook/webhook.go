@@ -0,0 +1,320 @@
+// Package webhook доставляет события задач внешним системам, подписанным на проекты.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"Tasks/internal/events"
+	"Tasks/internal/interfaces"
+	"Tasks/internal/lib/logger/sl"
+	"Tasks/internal/model"
+)
+
+// Заголовки доставки. Подпись - HMAC-SHA256 ключом вебхука от строки "<timestamp>.<тело>".
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// retryDelay пауза перед повторной подпиской на события после ошибки
+const retryDelay = time.Second
+
+// Config Interval - опрос очереди доставок, Batch - сколько доставок выполняется одновременно,
+// Timeout - ожидание ответа получателя. Неудачная попытка повторяется через BackoffBase, затем
+// интервал удваивается до BackoffMax; после MaxAttempts попыток доставка считается неудачной.
+// Вебхук отключается после DisableAfter неудачных попыток подряд по любым доставкам.
+// AllowPrivate разрешает доставку во внутренние сети, только для локальной разработки.
+type Config struct {
+	Interval     time.Duration
+	Batch        int
+	Timeout      time.Duration
+	MaxAttempts  int
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	DisableAfter int
+	AllowPrivate bool
+}
+
+// Dispatcher ставит события задач в очередь доставки и доставляет их. Очередь хранится в PostgreSQL:
+// каждый экземпляр приложения получает все события, но доставка события вебхуку создаётся один раз,
+// а экземпляры берут доставки из очереди, не мешая друг другу.
+type Dispatcher struct {
+	log    *slog.Logger
+	repo   interfaces.StorageRepository
+	events *events.Hub
+	client *http.Client
+	cfg    Config
+}
+
+func NewDispatcher(log *slog.Logger, repo interfaces.StorageRepository, events *events.Hub, cfg Config) *Dispatcher {
+	return &Dispatcher{
+		log:    log.With(slog.String("component", "webhook/dispatcher")),
+		repo:   repo,
+		events: events,
+		client: newClient(cfg),
+		cfg:    cfg,
+	}
+}
+
+// newClient HTTP-клиент доставок. Адрес получателя проверяется при подключении, уже после разрешения
+// имени, поэтому DNS-запись, указывающая во внутреннюю сеть, тоже отклоняется.
+func newClient(cfg Config) *http.Client {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivate {
+		dialer.Control = dialControl
+	}
+	return &http.Client{
+		Timeout: cfg.Timeout,
+		Transport: &http.Transport{
+			// прокси из окружения подключался бы к получателю в обход проверки адреса
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			ForceAttemptHTTP2:   true,
+			MaxIdleConns:        100,
+			IdleConnTimeout:     90 * time.Second,
+			TLSHandshakeTimeout: 10 * time.Second,
+		},
+		// перенаправление POST превратилось бы в GET без тела, поэтому 3xx - неудачная попытка
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// Run ставит события в очередь и доставляет их, пока не будет отменён ctx
+func (d *Dispatcher) Run(ctx context.Context) {
+	d.log.Info("starting webhook dispatcher")
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		d.runEvents(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		d.runDeliveries(ctx)
+	}()
+	wg.Wait()
+	d.log.Info("webhook dispatcher stopped")
+}
+
+// runEvents после отключения подписки продолжает с последнего полученного события
+func (d *Dispatcher) runEvents(ctx context.Context) {
+	last := ""
+	for ctx.Err() == nil {
+		sub, err := d.events.Subscribe(ctx, last)
+		if err != nil {
+			d.log.Error("failed to subscribe to task events", sl.Err(err))
+		} else {
+			if !sub.Complete {
+				d.log.Warn("task events were missed, webhooks will not receive them", slog.String("last_event_id", last))
+			}
+			for _, event := range sub.Replay {
+				d.enqueue(ctx, event)
+			}
+			last = d.consume(ctx, sub, sub.Last)
+			sub.Close()
+		}
+		wait(ctx, retryDelay)
+	}
+}
+
+// consume ставит в очередь события подписки до её отключения и возвращает ID последнего
+func (d *Dispatcher) consume(ctx context.Context, sub *events.Subscription, last string) string {
+	for {
+		select {
+		case <-ctx.Done():
+			return last
+		case event, ok := <-sub.C:
+			if !ok {
+				return last
+			}
+			// события не позже last уже поставлены в очередь из Replay
+			if !events.After(event.ID, last) {
+				continue
+			}
+			d.enqueue(ctx, event)
+			last = event.ID
+		}
+	}
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, event model.TaskEvent) {
+	if event.ProjectID == 0 {
+		return
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		d.log.Error("failed to marshal task event", sl.Err(err))
+		return
+	}
+	if _, err := d.repo.EnqueueWebhookDeliveries(ctx, event, payload); err != nil {
+		d.log.Error("failed to enqueue webhook deliveries", slog.String("event_id", event.ID), sl.Err(err))
+	}
+}
+
+func (d *Dispatcher) runDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			d.deliverDue(ctx)
+		}
+	}
+}
+
+// deliverDue выполняет доставки, срок которых наступил, пачками по Batch, пока очередь не опустеет
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// попытка длится не дольше Timeout, запас - на запись результата
+		jobs, err := d.repo.ClaimWebhookDeliveries(ctx, d.cfg.Batch, 2*d.cfg.Timeout)
+		if err != nil {
+			d.log.Error("failed to claim webhook deliveries", sl.Err(err))
+			return
+		}
+		var wg sync.WaitGroup
+		for _, job := range jobs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				d.deliver(ctx, job)
+			}()
+		}
+		wg.Wait()
+		if len(jobs) < d.cfg.Batch {
+			return
+		}
+	}
+}
+
+// deliver выполняет попытку доставки и записывает результат
+func (d *Dispatcher) deliver(ctx context.Context, job model.WebhookJob) {
+	delivery := job.Delivery
+	log := d.log.With(slog.Int("delivery_id", delivery.ID), slog.Int("webhook_id", delivery.WebhookID))
+
+	attempt := model.WebhookAttempt{DeliveryID: delivery.ID, WebhookID: delivery.WebhookID, Status: model.DeliverySucceeded}
+	status, err := d.send(ctx, job)
+	attempt.ResponseStatus = status
+	if ctx.Err() != nil {
+		// остановка приложения не считается неудачей получателя, доставку повторят после lease
+		return
+	}
+	if err != nil {
+		// журнал доставок видят руководители проекта, поэтому в нём только общее описание ошибки
+		attempt.Error = attemptError(err)
+		attempt.Status = model.DeliveryPending
+		attempt.RetryIn = d.backoff(delivery.Attempts)
+		if delivery.Attempts >= d.cfg.MaxAttempts {
+			attempt.Status, attempt.RetryIn = model.DeliveryFailed, 0
+		}
+		log.Warn("webhook delivery attempt failed", slog.Int("attempt", delivery.Attempts), sl.Err(err))
+	}
+
+	disabled, err := d.repo.RecordWebhookAttempt(ctx, attempt, d.cfg.DisableAfter)
+	if err != nil {
+		log.Error("failed to record webhook attempt", sl.Err(err))
+		return
+	}
+	if disabled {
+		log.Warn("webhook disabled after repeated failures", slog.Int("failures", d.cfg.DisableAfter))
+	}
+}
+
+// send отправляет доставку; без ошибки - получатель ответил 2xx
+func (d *Dispatcher) send(ctx context.Context, job model.WebhookJob) (int, error) {
+	delivery := job.Delivery
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Tasks-Webhook/1.0")
+	req.Header.Set(HeaderDelivery, strconv.Itoa(delivery.ID))
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(job.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	// тело ответа не читается и не сохраняется
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, statusError(resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// statusError получатель ответил не 2xx
+type statusError int
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", int(e))
+}
+
+// attemptError описание ошибки для журнала доставок: без тела ответа, адресов и текста сетевых ошибок
+func attemptError(err error) string {
+	var status statusError
+	var netErr net.Error
+	switch {
+	case errors.As(err, &status):
+		return status.Error()
+	case errors.Is(err, errAddressNotAllowed):
+		return "webhook address is not allowed"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "request timed out"
+	}
+	return "request failed"
+}
+
+// backoff пауза после attempt-й неудачной попытки: BackoffBase, удваиваемая с каждой попыткой
+// до BackoffMax, со случайным разбросом в половину паузы, чтобы повторы не приходили разом
+func (d *Dispatcher) backoff(attempt int) time.Duration {
+	delay := d.cfg.BackoffMax
+	if attempt <= 30 {
+		if exp := d.cfg.BackoffBase << (attempt - 1); exp > 0 && exp < delay {
+			delay = exp
+		}
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// Sign подпись доставки для заголовка X-Webhook-Signature. Получатель вычисляет её так же и сравнивает
+// за постоянное время; метка времени в подписи не даёт повторить перехваченную доставку позже.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func wait(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
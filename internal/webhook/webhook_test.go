@@ -0,0 +1,182 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"Tasks/internal/lib/logger/handler/slogdiscard"
+	"Tasks/internal/model"
+	mockery "Tasks/internal/service/mocks"
+)
+
+var testConfig = Config{
+	Interval:     time.Second,
+	Batch:        4,
+	Timeout:      time.Second,
+	MaxAttempts:  3,
+	BackoffBase:  time.Minute,
+	BackoffMax:   time.Hour,
+	DisableAfter: 5,
+	// тестовый получатель слушает 127.0.0.1
+	AllowPrivate: true,
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"id":"1-0"}`)
+	signature := Sign("secret", 1700000000, body)
+
+	require.Equal(t, "sha256=", signature[:7])
+	require.Len(t, signature, 7+64)
+	require.Equal(t, signature, Sign("secret", 1700000000, body))
+	require.NotEqual(t, signature, Sign("secret", 1700000001, body))
+	require.NotEqual(t, signature, Sign("other", 1700000000, body))
+}
+
+func TestDispatcher_Deliver(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		attempts int
+		want     string
+		retry    bool
+		disabled bool
+	}{
+		{name: "success", status: http.StatusNoContent, attempts: 1, want: model.DeliverySucceeded},
+		{name: "failure is retried", status: http.StatusInternalServerError, attempts: 1, want: model.DeliveryPending, retry: true},
+		{name: "redirect is a failure", status: http.StatusFound, attempts: 2, want: model.DeliveryPending, retry: true},
+		{name: "last attempt", status: http.StatusBadGateway, attempts: 3, want: model.DeliveryFailed},
+		{name: "webhook disabled", status: http.StatusInternalServerError, attempts: 1, want: model.DeliveryPending, retry: true, disabled: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			payload := []byte(`{"id":"5-0","type":"task_created","task_id":7}`)
+			var got *http.Request
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				body, _ = io.ReadAll(r.Body)
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("internal details"))
+			}))
+			defer server.Close()
+
+			storageMock := mockery.NewStorageRepository(t)
+			storageMock.On("RecordWebhookAttempt", mock.Anything, mock.MatchedBy(func(a model.WebhookAttempt) bool {
+				retryOK := a.RetryIn == 0
+				if tt.retry {
+					retryOK = a.RetryIn >= testConfig.BackoffBase<<(tt.attempts-1)/2 && a.RetryIn <= testConfig.BackoffBase<<(tt.attempts-1)
+				}
+				return a.DeliveryID == 11 && a.WebhookID == 3 && a.Status == tt.want && a.ResponseStatus == tt.status &&
+					(a.Error == "") == (tt.want == model.DeliverySucceeded) && !strings.Contains(a.Error, "internal") && retryOK
+			}), testConfig.DisableAfter).Return(tt.disabled, nil)
+
+			d := NewDispatcher(slogdiscard.NewDiscardLogger(), storageMock, nil, testConfig)
+			d.deliver(context.Background(), model.WebhookJob{
+				Delivery: model.WebhookDelivery{ID: 11, WebhookID: 3, EventID: "5-0", EventType: model.TaskEventCreated,
+					Payload: payload, Attempts: tt.attempts},
+				URL:    server.URL,
+				Secret: "secret",
+			})
+
+			require.NotNil(t, got)
+			require.Equal(t, payload, body)
+			require.Equal(t, "11", got.Header.Get(HeaderDelivery))
+			require.Equal(t, model.TaskEventCreated, got.Header.Get(HeaderEvent))
+			require.Equal(t, "5-0", got.Header.Get(HeaderEventID))
+			timestamp, err := strconv.ParseInt(got.Header.Get(HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			require.True(t, hmac.Equal([]byte(Sign("secret", timestamp, payload)), []byte(got.Header.Get(HeaderSignature))))
+		})
+	}
+}
+
+func TestDispatcher_Backoff(t *testing.T) {
+	d := NewDispatcher(slogdiscard.NewDiscardLogger(), nil, nil, testConfig)
+	for attempt, want := range map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		5:  16 * time.Minute,
+		7:  time.Hour,
+		40: time.Hour,
+	} {
+		for i := 0; i < 20; i++ {
+			delay := d.backoff(attempt)
+			require.GreaterOrEqual(t, delay, want/2, "attempt %d", attempt)
+			require.LessOrEqual(t, delay, want, "attempt %d", attempt)
+		}
+	}
+}
+
+func TestDispatcher_Enqueue(t *testing.T) {
+	storageMock := mockery.NewStorageRepository(t)
+	event := model.TaskEvent{ID: "2-0", Type: model.TaskEventDeleted, TaskID: 4, ProjectID: 9}
+	storageMock.On("EnqueueWebhookDeliveries", mock.Anything, event, mock.MatchedBy(func(payload []byte) bool {
+		return string(payload) == `{"id":"2-0","type":"task_deleted","task_id":4,"project_id":9,"timestamp":"0001-01-01T00:00:00Z"}`
+	})).Return(1, nil).Once()
+
+	d := NewDispatcher(slogdiscard.NewDiscardLogger(), storageMock, nil, testConfig)
+	d.enqueue(context.Background(), event)
+	// у задачи без проекта нет вебхуков
+	d.enqueue(context.Background(), model.TaskEvent{ID: "3-0", Type: model.TaskEventCreated, TaskID: 5})
+}
+
+func TestDispatcher_Deliver_PrivateAddress(t *testing.T) {
+	requested := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = true
+	}))
+	defer server.Close()
+
+	storageMock := mockery.NewStorageRepository(t)
+	storageMock.On("RecordWebhookAttempt", mock.Anything, mock.MatchedBy(func(a model.WebhookAttempt) bool {
+		return a.Status == model.DeliveryPending && a.ResponseStatus == 0 && a.Error == "webhook address is not allowed"
+	}), testConfig.DisableAfter).Return(false, nil).Once()
+
+	cfg := testConfig
+	cfg.AllowPrivate = false
+	d := NewDispatcher(slogdiscard.NewDiscardLogger(), storageMock, nil, cfg)
+	// имя разрешается в 127.0.0.1, адрес проверяется уже после разрешения
+	d.deliver(context.Background(), model.WebhookJob{
+		Delivery: model.WebhookDelivery{ID: 11, WebhookID: 3, Payload: []byte(`{}`), Attempts: 1},
+		URL:      strings.Replace(server.URL, "127.0.0.1", "localhost", 1),
+		Secret:   "secret",
+	})
+	require.False(t, requested)
+}
+
+func TestAllowedAddress(t *testing.T) {
+	for address, want := range map[string]bool{
+		"93.184.216.34":    true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"::1":              false,
+		"10.1.2.3":         false,
+		"172.16.0.1":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"fe80::1":          false,
+		"fd00::1":          false,
+		"0.0.0.0":          false,
+		"100.64.0.1":       false,
+		"::ffff:127.0.0.1": false,
+		"224.0.0.1":        false,
+	} {
+		require.Equal(t, want, allowedAddress(netip.MustParseAddr(address)), address)
+	}
+}
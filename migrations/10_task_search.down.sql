@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_search;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS search_vector;
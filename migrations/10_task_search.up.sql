@@ -0,0 +1,10 @@
+-- Полнотекстовый поиск по задачам. Вектор строится в русской и английской конфигурациях,
+-- название весит больше описания. Комментарии к задачам войдут в поиск отдельным вектором, когда появятся.
+ALTER TABLE tasks ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX idx_tasks_search ON tasks USING GIN (search_vector);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS view_matches;
+DROP TABLE IF EXISTS view_subscriptions;
+DROP TABLE IF EXISTS views;
@@ -0,0 +1,38 @@
+-- Сохранённые представления: условие на языке фильтров, сортировка и колонки.
+-- У пользователя не больше одного представления по умолчанию.
+CREATE TABLE views (
+                       view_id SERIAL PRIMARY KEY,
+                       owner_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                       project_id INT REFERENCES projects(project_id) ON DELETE CASCADE,
+                       name VARCHAR(255) NOT NULL,
+                       query TEXT NOT NULL DEFAULT '',
+                       sort VARCHAR(20) NOT NULL DEFAULT '',
+                       sort_desc BOOLEAN NOT NULL DEFAULT FALSE,
+                       columns TEXT[] NOT NULL DEFAULT '{}',
+                       is_default BOOLEAN NOT NULL DEFAULT FALSE,
+                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                       updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_views_owner ON views(owner_id);
+CREATE INDEX idx_views_project ON views(project_id);
+CREATE UNIQUE INDEX idx_views_default ON views(owner_id) WHERE is_default;
+
+-- Подписки на попадание задач в представление. evaluated_at IS NULL - представление ещё не вычислялось
+-- для подписчика, первое вычисление только запоминает текущие задачи.
+CREATE TABLE view_subscriptions (
+                                    view_id INT NOT NULL REFERENCES views(view_id) ON DELETE CASCADE,
+                                    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                                    evaluated_at TIMESTAMP,
+                                    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                    PRIMARY KEY (view_id, user_id)
+);
+
+-- Задачи, которые были в представлении при последнем вычислении для подписчика
+CREATE TABLE view_matches (
+                              view_id INT NOT NULL,
+                              user_id INT NOT NULL,
+                              task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                              PRIMARY KEY (view_id, user_id, task_id),
+                              FOREIGN KEY (view_id, user_id) REFERENCES view_subscriptions(view_id, user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Версия задачи для оптимистичной блокировки: увеличивается при каждом изменении задачи, отдаётся клиенту в ETag
+ALTER TABLE tasks ADD COLUMN version INT NOT NULL DEFAULT 1;
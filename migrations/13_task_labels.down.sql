@@ -0,0 +1,5 @@
+ALTER TABLE task_assignments DROP CONSTRAINT task_assignments_task_id_fkey;
+ALTER TABLE task_assignments ADD CONSTRAINT task_assignments_task_id_fkey
+    FOREIGN KEY (task_id) REFERENCES tasks(task_id);
+
+DROP TABLE IF EXISTS task_labels;
@@ -0,0 +1,13 @@
+-- Метки задач
+CREATE TABLE task_labels (
+                             task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                             label VARCHAR(50) NOT NULL,
+                             PRIMARY KEY (task_id, label)
+);
+
+CREATE INDEX idx_task_labels_label ON task_labels(label);
+
+-- Удаление задачи снимает её исполнителей: без каскада нельзя было удалить назначенную задачу
+ALTER TABLE task_assignments DROP CONSTRAINT task_assignments_task_id_fkey;
+ALTER TABLE task_assignments ADD CONSTRAINT task_assignments_task_id_fkey
+    FOREIGN KEY (task_id) REFERENCES tasks(task_id) ON DELETE CASCADE;
@@ -0,0 +1,41 @@
+-- Подписки внешних систем на события задач проекта. events пустой - все типы событий.
+-- failures - неудачные попытки доставки подряд, после порога подписка отключается (active = FALSE).
+CREATE TABLE webhooks (
+                          webhook_id SERIAL PRIMARY KEY,
+                          project_id INT NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+                          url TEXT NOT NULL,
+                          events TEXT[] NOT NULL DEFAULT '{}',
+                          secret VARCHAR(255) NOT NULL,
+                          active BOOLEAN NOT NULL DEFAULT TRUE,
+                          failures INT NOT NULL DEFAULT 0,
+                          disabled_at TIMESTAMP,
+                          created_by INT REFERENCES users(user_id) ON DELETE SET NULL,
+                          created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                          updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhooks_project ON webhooks(project_id);
+
+-- Журнал доставок. Очередная попытка доставки pending-записи - не раньше next_attempt_at.
+-- replay_of - повтор доставки, запрошенный вручную.
+CREATE TABLE webhook_deliveries (
+                                    delivery_id BIGSERIAL PRIMARY KEY,
+                                    webhook_id INT NOT NULL REFERENCES webhooks(webhook_id) ON DELETE CASCADE,
+                                    event_id VARCHAR(50) NOT NULL,
+                                    event_type VARCHAR(50) NOT NULL,
+                                    payload JSONB NOT NULL,
+                                    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+                                        CHECK (status IN ('pending', 'succeeded', 'failed')),
+                                    attempts INT NOT NULL DEFAULT 0,
+                                    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+                                    response_status INT,
+                                    last_error TEXT NOT NULL DEFAULT '',
+                                    replay_of BIGINT REFERENCES webhook_deliveries(delivery_id) ON DELETE SET NULL,
+                                    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                    delivered_at TIMESTAMP
+);
+
+-- событие доставляется подписке один раз, сколько бы экземпляров приложения его ни получили
+CREATE UNIQUE INDEX idx_webhook_deliveries_event ON webhook_deliveries(webhook_id, event_id) WHERE replay_of IS NULL;
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_webhook ON webhook_deliveries(webhook_id, delivery_id DESC);
@@ -0,0 +1,15 @@
+DROP TABLE IF EXISTS sync_horizon;
+DROP TRIGGER IF EXISTS task_assignments_tombstone ON task_assignments;
+DROP TRIGGER IF EXISTS tasks_tombstone ON tasks;
+DROP FUNCTION IF EXISTS assignment_tombstone();
+DROP FUNCTION IF EXISTS task_tombstone();
+DROP TABLE IF EXISTS sync_tombstones;
+DROP TRIGGER IF EXISTS task_labels_touch_task ON task_labels;
+DROP TRIGGER IF EXISTS task_assignments_touch_task ON task_assignments;
+DROP FUNCTION IF EXISTS touch_task();
+DROP TRIGGER IF EXISTS tasks_change_seq ON tasks;
+DROP FUNCTION IF EXISTS set_change_seq();
+DROP INDEX IF EXISTS idx_tasks_change_seq;
+ALTER TABLE tasks DROP COLUMN IF EXISTS change_seq;
+DROP FUNCTION IF EXISTS next_change_seq();
+DROP SEQUENCE IF EXISTS change_seq;
@@ -0,0 +1,95 @@
+-- Последовательность изменений для синхронизации офлайн-клиентов. Номер изменения выдаёт next_change_seq():
+-- транзакция, получившая номер, держит разделяемую блокировку до своего завершения, поэтому, взяв эту
+-- блокировку монопольно, синхронизация знает, что все выданные номера уже зафиксированы или отменены.
+CREATE SEQUENCE change_seq;
+
+CREATE FUNCTION next_change_seq() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock_shared(hashtext('change_seq'));
+    RETURN nextval('change_seq');
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE tasks ADD COLUMN change_seq BIGINT NOT NULL DEFAULT 0;
+UPDATE tasks SET change_seq = nextval('change_seq');
+CREATE INDEX idx_tasks_change_seq ON tasks(change_seq);
+
+CREATE FUNCTION set_change_seq() RETURNS trigger AS $$
+BEGIN
+    NEW.change_seq := next_change_seq();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_change_seq BEFORE INSERT OR UPDATE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION set_change_seq();
+
+-- Исполнители и метки входят в задачу для клиента: их изменение - изменение задачи.
+-- Номер задаче присваивает триггер tasks_change_seq.
+CREATE FUNCTION touch_task() RETURNS trigger AS $$
+BEGIN
+    UPDATE tasks SET change_seq = change_seq
+    WHERE task_id = CASE WHEN TG_OP = 'DELETE' THEN OLD.task_id ELSE NEW.task_id END;
+    IF TG_OP = 'UPDATE' AND OLD.task_id <> NEW.task_id THEN
+        UPDATE tasks SET change_seq = change_seq WHERE task_id = OLD.task_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_assignments_touch_task AFTER INSERT OR UPDATE OR DELETE ON task_assignments
+    FOR EACH ROW EXECUTE FUNCTION touch_task();
+CREATE TRIGGER task_labels_touch_task AFTER INSERT OR UPDATE OR DELETE ON task_labels
+    FOR EACH ROW EXECUTE FUNCTION touch_task();
+
+-- Надгробия: удалённые задачи (entity = 'task') и снятые исполнители (entity = 'assignment'),
+-- после снятия которых задача могла перестать быть видна пользователю. project_id и assignees -
+-- на момент удаления, по ним определяется, кому видно надгробие.
+CREATE TABLE sync_tombstones (
+                                 change_seq BIGINT PRIMARY KEY DEFAULT next_change_seq(),
+                                 entity VARCHAR(20) NOT NULL CHECK (entity IN ('task', 'assignment')),
+                                 task_id INT NOT NULL,
+                                 user_id INT,
+                                 project_id INT,
+                                 assignees INT[] NOT NULL DEFAULT '{}',
+                                 deleted_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sync_tombstones_deleted_at ON sync_tombstones(deleted_at);
+
+-- BEFORE: исполнители удаляются каскадом после самой задачи
+CREATE FUNCTION task_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity, task_id, project_id, assignees)
+    VALUES ('task', OLD.task_id, OLD.project_id,
+            ARRAY(SELECT user_id FROM task_assignments WHERE task_id = OLD.task_id));
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER tasks_tombstone BEFORE DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION task_tombstone();
+
+-- снятие исполнителя при удалении задачи покрывает надгробие самой задачи
+CREATE FUNCTION assignment_tombstone() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO sync_tombstones (entity, task_id, user_id, project_id, assignees)
+    SELECT 'assignment', t.task_id, OLD.user_id, t.project_id,
+           ARRAY(SELECT user_id FROM task_assignments WHERE task_id = t.task_id) || OLD.user_id
+    FROM tasks t
+    WHERE t.task_id = OLD.task_id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_assignments_tombstone AFTER DELETE ON task_assignments
+    FOR EACH ROW EXECUTE FUNCTION assignment_tombstone();
+
+-- Горизонт синхронизации: надгробия с номером не больше horizon удалены, клиент с более старым
+-- токеном получает все данные заново
+CREATE TABLE sync_horizon (
+                              id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+                              horizon BIGINT NOT NULL DEFAULT 0
+);
+
+INSERT INTO sync_horizon DEFAULT VALUES;
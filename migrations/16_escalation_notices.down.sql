@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_task_escalations_notify;
+ALTER TABLE task_escalations DROP COLUMN IF EXISTS notify_user_id;
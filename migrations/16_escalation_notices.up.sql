@@ -0,0 +1,4 @@
+-- notify_user_id - руководитель, которому ещё не отправлено уведомление об эскалации
+ALTER TABLE task_escalations ADD COLUMN notify_user_id INT REFERENCES users(user_id) ON DELETE SET NULL;
+
+CREATE INDEX idx_task_escalations_notify ON task_escalations(notify_user_id) WHERE notify_user_id IS NOT NULL;
@@ -0,0 +1,6 @@
+CREATE OR REPLACE FUNCTION next_change_seq() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_advisory_xact_lock_shared(hashtext('change_seq'));
+    RETURN nextval('change_seq');
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,9 @@
+-- Синхронизация больше не берёт монопольную блокировку: она ждёт транзакции из снимка, снятого после
+-- чтения change_seq. Для этого транзакция получает xid до номера изменения, и любой выданный номер
+-- принадлежит транзакции, которая уже есть в снимке.
+CREATE OR REPLACE FUNCTION next_change_seq() RETURNS BIGINT AS $$
+BEGIN
+    PERFORM pg_current_xact_id();
+    RETURN nextval('change_seq');
+END;
+$$ LANGUAGE plpgsql;
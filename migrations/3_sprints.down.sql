@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS sprint_tasks;
+
+DROP TABLE IF EXISTS sprints;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS milestone_id;
+
+DROP TABLE IF EXISTS milestones;
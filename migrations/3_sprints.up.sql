@@ -0,0 +1,29 @@
+CREATE TABLE milestones (
+                            milestone_id SERIAL PRIMARY KEY,
+                            title VARCHAR(255) NOT NULL,
+                            start_date TIMESTAMP NOT NULL,
+                            end_date TIMESTAMP NOT NULL,
+                            created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                            CHECK (end_date > start_date)
+);
+
+ALTER TABLE tasks ADD COLUMN milestone_id INT REFERENCES milestones(milestone_id) ON DELETE SET NULL;
+
+CREATE TABLE sprints (
+                         sprint_id SERIAL PRIMARY KEY,
+                         title VARCHAR(255) NOT NULL,
+                         status VARCHAR(50) NOT NULL DEFAULT 'planned',
+                         start_date TIMESTAMP NOT NULL,
+                         end_date TIMESTAMP NOT NULL,
+                         created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                         CHECK (end_date > start_date)
+);
+
+CREATE TABLE sprint_tasks (
+                              sprint_id INT NOT NULL REFERENCES sprints(sprint_id) ON DELETE CASCADE,
+                              task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                              carried_over BOOLEAN NOT NULL DEFAULT FALSE,
+                              PRIMARY KEY (sprint_id, task_id)
+);
+
+CREATE INDEX idx_sprint_tasks_task ON sprint_tasks(task_id);
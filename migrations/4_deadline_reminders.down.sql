@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_deadline;
+
+DROP TABLE IF EXISTS deadline_reminders;
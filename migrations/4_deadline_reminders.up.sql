@@ -0,0 +1,11 @@
+-- threshold_seconds = 0 означает напоминание о просроченном дедлайне
+CREATE TABLE deadline_reminders (
+                                    task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                                    user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                                    threshold_seconds BIGINT NOT NULL,
+                                    deadline TIMESTAMP NOT NULL,
+                                    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                    PRIMARY KEY (task_id, user_id, threshold_seconds, deadline)
+);
+
+CREATE INDEX idx_tasks_deadline ON tasks(deadline);
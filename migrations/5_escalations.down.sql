@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS task_escalations;
+
+DROP TABLE IF EXISTS escalation_rules;
+
+DROP TABLE IF EXISTS task_history;
+
+ALTER TABLE tasks DROP COLUMN IF EXISTS priority;
+ALTER TABLE tasks DROP COLUMN IF EXISTS project_id;
+
+DROP TABLE IF EXISTS projects;
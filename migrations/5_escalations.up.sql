@@ -0,0 +1,40 @@
+CREATE TABLE projects (
+                          project_id SERIAL PRIMARY KEY,
+                          name VARCHAR(255) NOT NULL UNIQUE,
+                          manager_id INT REFERENCES users(user_id) ON DELETE SET NULL,
+                          created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE tasks ADD COLUMN project_id INT REFERENCES projects(project_id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN priority VARCHAR(20) NOT NULL DEFAULT 'medium';
+
+CREATE INDEX idx_tasks_project ON tasks(project_id);
+
+CREATE TABLE task_history (
+                              id SERIAL PRIMARY KEY,
+                              task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                              event VARCHAR(50) NOT NULL,
+                              details TEXT,
+                              created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_task_history_task ON task_history(task_id);
+
+CREATE TABLE escalation_rules (
+                                  rule_id SERIAL PRIMARY KEY,
+                                  project_id INT NOT NULL REFERENCES projects(project_id) ON DELETE CASCADE,
+                                  priority VARCHAR(20) NOT NULL,
+                                  overdue_seconds BIGINT NOT NULL DEFAULT 0,
+                                  assign_manager BOOLEAN NOT NULL DEFAULT TRUE,
+                                  notify_manager BOOLEAN NOT NULL DEFAULT TRUE,
+                                  enabled BOOLEAN NOT NULL DEFAULT TRUE,
+                                  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE task_escalations (
+                                  rule_id INT NOT NULL REFERENCES escalation_rules(rule_id) ON DELETE CASCADE,
+                                  task_id INT NOT NULL REFERENCES tasks(task_id) ON DELETE CASCADE,
+                                  deadline TIMESTAMP NOT NULL,
+                                  escalated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+                                  PRIMARY KEY (rule_id, task_id, deadline)
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS team_members;
+
+DROP TABLE IF EXISTS teams;
+
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+CREATE TABLE teams (
+                       team_id SERIAL PRIMARY KEY,
+                       name VARCHAR(255) NOT NULL UNIQUE,
+                       created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE team_members (
+                              team_id INT NOT NULL REFERENCES teams(team_id) ON DELETE CASCADE,
+                              user_id INT NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+                              PRIMARY KEY (team_id, user_id)
+);
+
+CREATE INDEX idx_team_members_user ON team_members(user_id);
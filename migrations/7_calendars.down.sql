@@ -0,0 +1,6 @@
+ALTER TABLE users DROP COLUMN IF EXISTS calendar_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS calendar_id;
+
+DROP TABLE IF EXISTS calendar_holidays;
+
+DROP TABLE IF EXISTS calendars;
@@ -0,0 +1,19 @@
+CREATE TABLE calendars (
+                           calendar_id SERIAL PRIMARY KEY,
+                           name VARCHAR(255) NOT NULL UNIQUE,
+                           timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+                           work_start TIME NOT NULL DEFAULT '09:00',
+                           work_end TIME NOT NULL DEFAULT '18:00',
+                           weekends INT[] NOT NULL DEFAULT '{0,6}',
+                           CHECK (work_end > work_start)
+);
+
+CREATE TABLE calendar_holidays (
+                                   calendar_id INT NOT NULL REFERENCES calendars(calendar_id) ON DELETE CASCADE,
+                                   day DATE NOT NULL,
+                                   name VARCHAR(255),
+                                   PRIMARY KEY (calendar_id, day)
+);
+
+ALTER TABLE projects ADD COLUMN calendar_id INT REFERENCES calendars(calendar_id) ON DELETE SET NULL;
+ALTER TABLE users ADD COLUMN calendar_id INT REFERENCES calendars(calendar_id) ON DELETE SET NULL;
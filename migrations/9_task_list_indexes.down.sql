@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_task_assignments_task;
+DROP INDEX IF EXISTS idx_tasks_updated_at_id;
+DROP INDEX IF EXISTS idx_tasks_created_at_id;
+DROP INDEX IF EXISTS idx_tasks_deadline_id;
@@ -0,0 +1,5 @@
+-- Индексы под постраничную выборку задач: выражения совпадают с сортировкой в ListTasks
+CREATE INDEX idx_tasks_deadline_id ON tasks ((COALESCE(deadline, 'infinity'::timestamp)), task_id);
+CREATE INDEX idx_tasks_created_at_id ON tasks ((COALESCE(created_at, '-infinity'::timestamp)), task_id);
+CREATE INDEX idx_tasks_updated_at_id ON tasks ((COALESCE(updated_at, '-infinity'::timestamp)), task_id);
+CREATE INDEX idx_task_assignments_task ON task_assignments(task_id, user_id);